@@ -17,12 +17,17 @@ type Branch struct {
 	Recency   string
 	Pushables string
 	Pullables string
+	Upstream  string
+	CommitSha string // the commit at the tip of the branch
 	Selected  bool
 }
 
 // GetDisplayStrings returns the display string of branch
 func (b *Branch) GetDisplayStrings(isFocused bool) []string {
 	displayName := utils.ColoredString(b.Name, b.GetColor())
+	if b.Upstream != "" {
+		displayName = fmt.Sprintf("%s → %s", displayName, utils.ColoredString(b.Upstream, color.FgCyan))
+	}
 	if isFocused && b.Selected && b.Pushables != "" && b.Pullables != "" {
 		displayName = fmt.Sprintf("%s ↑%s↓%s", displayName, b.Pushables, b.Pullables)
 	}
@@ -62,6 +62,32 @@ func (b *BranchListBuilder) obtainReflogBranches() []*Branch {
 	return uniqueByName(branches)
 }
 
+// obtainUpstreams returns a map from each local branch name to the name of the
+// branch it tracks, fetched in one go rather than asking git branch by branch
+func (b *BranchListBuilder) obtainUpstreams() map[string]string {
+	rawString, err := b.GitCommand.OSCommand.RunCommandWithOutput("git for-each-ref --format='%(refname:short) %(upstream:short)' refs/heads")
+	if err != nil {
+		return map[string]string{}
+	}
+
+	return branchFieldFromOutput(rawString)
+}
+
+// setCommitShas resolves the tip commit of each local branch. Branches we
+// can't resolve, like ones from the reflog that have since been deleted, are
+// left without a sha
+func (b *BranchListBuilder) setCommitShas(branches []*Branch) {
+	rawString, err := b.GitCommand.OSCommand.RunCommandWithOutput("git for-each-ref --format='%(refname:short) %(objectname)' refs/heads")
+	if err != nil {
+		return
+	}
+
+	shas := branchFieldFromOutput(rawString)
+	for _, branch := range branches {
+		branch.CommitSha = shas[branch.Name]
+	}
+}
+
 func (b *BranchListBuilder) obtainSafeBranches() []*Branch {
 	branches := make([]*Branch, 0)
 
@@ -116,6 +142,13 @@ func (b *BranchListBuilder) Build() []*Branch {
 
 	branches[0].Recency = "  *"
 
+	upstreams := b.obtainUpstreams()
+	for _, branch := range branches {
+		branch.Upstream = upstreams[branch.Name]
+	}
+
+	b.setCommitShas(branches)
+
 	return branches
 }
 
@@ -148,6 +181,25 @@ func branchInfoFromLine(line string) (string, string, string) {
 	return words[0], words[1], words[len(words)-1]
 }
 
+// branchFieldFromOutput parses the output of a for-each-ref listing a field for
+// each branch. Each line will have the form 'feature origin/feature', or just
+// 'feature ' if the field is empty e.g. when the branch has no upstream
+func branchFieldFromOutput(output string) map[string]string {
+	upstreams := map[string]string{}
+	for _, line := range utils.SplitLines(output) {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		upstream := ""
+		if len(fields) > 1 {
+			upstream = fields[1]
+		}
+		upstreams[fields[0]] = upstream
+	}
+	return upstreams
+}
+
 func abbreviatedTimeUnit(timeUnit string) string {
 	r := regexp.MustCompile("s$")
 	timeUnit = r.ReplaceAllString(timeUnit, "")
@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// NewDummyBranchListBuilder creates a new dummy BranchListBuilder for testing
+func NewDummyBranchListBuilder() *BranchListBuilder {
+	return &BranchListBuilder{
+		Log:        NewDummyLog(),
+		GitCommand: NewDummyGitCommand(),
+	}
+}
+
+// TestBranchFieldFromOutput is a function.
+func TestBranchFieldFromOutput(t *testing.T) {
+	type scenario struct {
+		testName string
+		output   string
+		expected map[string]string
+	}
+
+	scenarios := []scenario{
+		{
+			"No branches",
+			"",
+			map[string]string{},
+		},
+		{
+			"Branches with and without upstreams",
+			"master origin/master\nfeature/login origin/feature/login\nscratch \n",
+			map[string]string{
+				"master":        "origin/master",
+				"feature/login": "origin/feature/login",
+				"scratch":       "",
+			},
+		},
+		{
+			"Branch with no trailing space",
+			"scratch",
+			map[string]string{
+				"scratch": "",
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, branchFieldFromOutput(s.output))
+		})
+	}
+}
+
+// TestBranchListBuilderObtainUpstreams is a function.
+func TestBranchListBuilderObtainUpstreams(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		expected map[string]string
+	}
+
+	scenarios := []scenario{
+		{
+			"Can't retrieve upstreams",
+			func(string, ...string) *exec.Cmd {
+				return exec.Command("test")
+			},
+			map[string]string{},
+		},
+		{
+			"Retrieves upstreams",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"for-each-ref", "--format=%(refname:short) %(upstream:short)", "refs/heads"}, args)
+				return exec.Command("echo", "master origin/master\nscratch ")
+			},
+			map[string]string{
+				"master":  "origin/master",
+				"scratch": "",
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			b := NewDummyBranchListBuilder()
+			b.GitCommand.OSCommand.SetCommand(s.command)
+			assert.EqualValues(t, s.expected, b.obtainUpstreams())
+		})
+	}
+}
+
+// TestBranchListBuilderSetCommitShas is a function.
+func TestBranchListBuilderSetCommitShas(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		expected []string
+	}
+
+	scenarios := []scenario{
+		{
+			"Can't list the branches",
+			func(string, ...string) *exec.Cmd {
+				return exec.Command("test")
+			},
+			[]string{"", "", ""},
+		},
+		{
+			"Resolves each branch's tip commit",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"for-each-ref", "--format=%(refname:short) %(objectname)", "refs/heads"}, args)
+				return exec.Command("echo", "master 1234abcd\nfeature/login 5678efab")
+			},
+			[]string{"1234abcd", "5678efab", ""},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			b := NewDummyBranchListBuilder()
+			b.GitCommand.OSCommand.SetCommand(s.command)
+			branches := []*Branch{{Name: "master"}, {Name: "feature/login"}, {Name: "deleted"}}
+			b.setCommitShas(branches)
+
+			for i, branch := range branches {
+				assert.EqualValues(t, s.expected[i], branch.CommitSha)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"strings"
+
 	"github.com/fatih/color"
 	"github.com/jesseduffield/lazygit/pkg/theme"
 	"github.com/jesseduffield/lazygit/pkg/utils"
@@ -12,8 +14,19 @@ type Commit struct {
 	Name          string
 	Status        string // one of "unpushed", "pushed", "merged", "rebasing" or "selected"
 	DisplayString string
-	Action        string // one of "", "pick", "edit", "squash", "reword", "drop", "fixup"
-	Copied        bool   // to know if this commit is ready to be cherry-picked somewhere
+	Action        string   // one of "", "pick", "edit", "squash", "reword", "drop", "fixup"
+	Copied        bool     // to know if this commit is ready to be cherry-picked somewhere
+	Refs          []string // e.g. 'HEAD -> master', 'origin/master', 'tag: v1.0'
+	Marked        bool     // to know if this commit is to be squashed with the other marked commits
+	ShowFullSha   bool     // to render the full sha rather than the abbreviated one
+}
+
+// ShortSha returns the abbreviated form of the commit's sha
+func (c *Commit) ShortSha() string {
+	if len(c.Sha) < 7 {
+		return c.Sha
+	}
+	return c.Sha[:7]
 }
 
 // GetDisplayStrings is a function.
@@ -56,5 +69,20 @@ func (c *Commit) GetDisplayStrings(isFocused bool) []string {
 		actionString = cyan.Sprint(utils.WithPadding(c.Action, 7)) + " "
 	}
 
-	return []string{shaColor.Sprint(c.Sha), actionString + defaultColor.Sprint(c.Name)}
+	markerString := ""
+	if c.Marked {
+		markerString = magenta.Sprint("* ")
+	}
+
+	refsString := ""
+	if len(c.Refs) > 0 {
+		refsString = green.Sprint("("+strings.Join(c.Refs, ", ")+")") + " "
+	}
+
+	sha := c.ShortSha()
+	if c.ShowFullSha {
+		sha = c.Sha
+	}
+
+	return []string{shaColor.Sprint(sha), markerString + actionString + refsString + defaultColor.Sprint(c.Name)}
 }
@@ -9,8 +9,10 @@ import (
 type CommitFile struct {
 	Sha           string
 	Name          string
+	PreviousName  string // the file's name before a rename or copy, if any
 	DisplayString string
-	Status        int // one of 'WHOLE' 'PART' 'NONE'
+	Status        int    // one of 'WHOLE' 'PART' 'NONE'
+	ChangeStatus  string // e.g. 'M', 'A', 'D', 'R', as reported by git diff --name-status
 }
 
 const (
@@ -23,6 +23,13 @@ import (
 // if we find out we need to use one of these functions in the git.go file, we
 // can just pull them out of here and put them there and then call them from in here
 
+// these are the ways the commits panel can treat merge commits
+const (
+	ShowMergeCommits = iota
+	HideMergeCommits
+	FirstParentOnly
+)
+
 // CommitListBuilder returns a list of Branch objects for the current repo
 type CommitListBuilder struct {
 	Log                 *logrus.Entry
@@ -31,6 +38,8 @@ type CommitListBuilder struct {
 	Tr                  *i18n.Localizer
 	CherryPickedCommits []*Commit
 	DiffEntries         []*Commit
+	ShowFullSha         bool
+	MergeCommitsMode    int // one of ShowMergeCommits, HideMergeCommits, FirstParentOnly
 }
 
 // NewCommitListBuilder builds a new commit list builder
@@ -69,16 +78,22 @@ func (c *CommitListBuilder) GetCommits() ([]*Commit, error) {
 
 	// now we can split it up and turn it into commits
 	for _, line := range utils.SplitLines(log) {
-		splitLine := strings.Split(line, " ")
-		sha := splitLine[0]
+		// see getLog for the format
+		splitLine := strings.SplitN(line, "\x00", 3)
+		if len(splitLine) < 3 {
+			continue
+		}
+		sha, decorations, name := splitLine[0], splitLine[1], splitLine[2]
 		_, unpushed := unpushedCommits[sha]
 		status := map[bool]string{true: "unpushed", false: "pushed"}[unpushed]
-		commits = append(commits, &Commit{
-			Sha:           sha,
-			Name:          strings.Join(splitLine[1:], " "),
-			Status:        status,
-			DisplayString: strings.Join(splitLine, " "),
-		})
+		commit := &Commit{
+			Sha:    sha,
+			Name:   name,
+			Status: status,
+			Refs:   parseDecorations(decorations),
+		}
+		commit.DisplayString = commit.ShortSha() + " " + name
+		commits = append(commits, commit)
 	}
 	if rebaseMode != "" {
 		currentCommit := commits[len(rebasingCommits)]
@@ -98,6 +113,7 @@ func (c *CommitListBuilder) GetCommits() ([]*Commit, error) {
 	}
 
 	for _, commit := range commits {
+		commit.ShowFullSha = c.ShowFullSha
 		for _, entry := range c.DiffEntries {
 			if entry.Sha == commit.Sha {
 				commit.Status = "selected"
@@ -189,7 +205,7 @@ func (c *CommitListBuilder) getInteractiveRebasingCommits() ([]*Commit, error) {
 		}
 		splitLine := strings.Split(line, " ")
 		commits = append([]*Commit{{
-			Sha:    splitLine[1][0:7],
+			Sha:    splitLine[1],
 			Name:   strings.Join(splitLine[2:], " "),
 			Status: "rebasing",
 			Action: splitLine[0],
@@ -206,7 +222,7 @@ func (c *CommitListBuilder) getInteractiveRebasingCommits() ([]*Commit, error) {
 // Subject: second commit on master
 func (c *CommitListBuilder) commitFromPatch(content string) (*Commit, error) {
 	lines := strings.Split(content, "\n")
-	sha := strings.Split(lines[0], " ")[1][0:7]
+	sha := strings.Split(lines[0], " ")[1]
 	name := strings.TrimPrefix(lines[3], "Subject: ")
 	return &Commit{
 		Sha:    sha,
@@ -255,9 +271,12 @@ func (c *CommitListBuilder) getMergeBase() (string, error) {
 		return "", err
 	}
 
-	baseBranch := "master"
-	if strings.HasPrefix(currentBranch, "feature/") {
-		baseBranch = "develop"
+	baseBranch := "develop"
+	if !strings.HasPrefix(currentBranch, "feature/") {
+		baseBranch, err = c.GitCommand.GetDefaultBranch()
+		if err != nil {
+			baseBranch = "master"
+		}
 	}
 
 	// swallowing error because it's not a big deal; probably because there are no commits yet
@@ -269,7 +288,7 @@ func (c *CommitListBuilder) getMergeBase() (string, error) {
 // to the remote branch of the current branch, a map is returned to ease look up
 func (c *CommitListBuilder) getUnpushedCommits() map[string]bool {
 	pushables := map[string]bool{}
-	o, err := c.OSCommand.RunCommandWithOutput("git rev-list @{u}..HEAD --abbrev-commit")
+	o, err := c.OSCommand.RunCommandWithOutput("git rev-list @{u}..HEAD")
 	if err != nil {
 		return pushables
 	}
@@ -285,7 +304,17 @@ func (c *CommitListBuilder) getUnpushedCommits() map[string]bool {
 func (c *CommitListBuilder) getLog() string {
 	// currently limiting to 30 for performance reasons
 	// TODO: add lazyloading when you scroll down
-	result, err := c.OSCommand.RunCommandWithOutput("git log --oneline -30")
+	// each line is the full sha, the refs pointing at the commit (like
+	// --decorate shows them) and the subject, separated by null bytes. We
+	// abbreviate the sha when rendering
+	mergesArg := ""
+	switch c.MergeCommitsMode {
+	case HideMergeCommits:
+		mergesArg = " --no-merges"
+	case FirstParentOnly:
+		mergesArg = " --first-parent"
+	}
+	result, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git log --format=%%H%%x00%%D%%x00%%s%s -30", mergesArg))
 	if err != nil {
 		// assume if there is an error there are no commits yet for this branch
 		return ""
@@ -293,3 +322,15 @@ func (c *CommitListBuilder) getLog() string {
 
 	return result
 }
+
+// parseDecorations parses git's ref decorations e.g.
+// '(HEAD -> master, origin/master, tag: v1.0)', with or without the brackets
+func parseDecorations(decorations string) []string {
+	decorations = strings.TrimSpace(decorations)
+	decorations = strings.TrimSuffix(strings.TrimPrefix(decorations, "("), ")")
+	if decorations == "" {
+		return nil
+	}
+
+	return strings.Split(decorations, ", ")
+}
@@ -76,6 +76,9 @@ func TestCommitListBuilderGetMergeBase(t *testing.T) {
 
 				switch args[0] {
 				case "symbolic-ref":
+					if args[2] == "refs/remotes/origin/HEAD" {
+						return exec.Command("echo", "origin/master")
+					}
 					assert.EqualValues(t, []string{"symbolic-ref", "--short", "HEAD"}, args)
 					return exec.Command("echo", "master")
 				case "merge-base":
@@ -96,6 +99,9 @@ func TestCommitListBuilderGetMergeBase(t *testing.T) {
 
 				switch args[0] {
 				case "symbolic-ref":
+					if args[2] == "refs/remotes/origin/HEAD" {
+						return exec.Command("echo", "origin/master")
+					}
 					assert.EqualValues(t, []string{"symbolic-ref", "--short", "HEAD"}, args)
 					return exec.Command("echo", "master")
 				case "merge-base":
@@ -163,7 +169,7 @@ func TestCommitListBuilderGetLog(t *testing.T) {
 			"Retrieves logs",
 			func(cmd string, args ...string) *exec.Cmd {
 				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"log", "--oneline", "-30"}, args)
+				assert.EqualValues(t, []string{"log", "--format=%H%x00%D%x00%s", "-30"}, args)
 
 				return exec.Command("echo", "6f0b32f commands/git : add GetCommits tests refactor\n9d9d775 circle : remove new line")
 			},
@@ -175,7 +181,7 @@ func TestCommitListBuilderGetLog(t *testing.T) {
 			"An error occurred when retrieving logs",
 			func(cmd string, args ...string) *exec.Cmd {
 				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"log", "--oneline", "-30"}, args)
+				assert.EqualValues(t, []string{"log", "--format=%H%x00%D%x00%s", "-30"}, args)
 				return exec.Command("test")
 			},
 			func(output string) {
@@ -193,6 +199,47 @@ func TestCommitListBuilderGetLog(t *testing.T) {
 	}
 }
 
+// TestCommitListBuilderGetLogMergeCommitsMode is a function.
+func TestCommitListBuilderGetLogMergeCommitsMode(t *testing.T) {
+	type scenario struct {
+		testName     string
+		mode         int
+		expectedArgs []string
+	}
+
+	scenarios := []scenario{
+		{
+			"Shows merge commits",
+			ShowMergeCommits,
+			[]string{"log", "--format=%H%x00%D%x00%s", "-30"},
+		},
+		{
+			"Hides merge commits",
+			HideMergeCommits,
+			[]string{"log", "--format=%H%x00%D%x00%s", "--no-merges", "-30"},
+		},
+		{
+			"Follows first parents only",
+			FirstParentOnly,
+			[]string{"log", "--format=%H%x00%D%x00%s", "--first-parent", "-30"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			c := NewDummyCommitListBuilder()
+			c.MergeCommitsMode = s.mode
+			c.OSCommand.SetCommand(func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, s.expectedArgs, args)
+
+				return exec.Command("echo")
+			})
+			c.getLog()
+		})
+	}
+}
+
 // TestCommitListBuilderGetCommits is a function.
 func TestCommitListBuilderGetCommits(t *testing.T) {
 	type scenario struct {
@@ -209,15 +256,18 @@ func TestCommitListBuilderGetCommits(t *testing.T) {
 
 				switch args[0] {
 				case "rev-list":
-					assert.EqualValues(t, []string{"rev-list", "@{u}..HEAD", "--abbrev-commit"}, args)
+					assert.EqualValues(t, []string{"rev-list", "@{u}..HEAD"}, args)
 					return exec.Command("echo")
 				case "log":
-					assert.EqualValues(t, []string{"log", "--oneline", "-30"}, args)
+					assert.EqualValues(t, []string{"log", "--format=%H%x00%D%x00%s", "-30"}, args)
 					return exec.Command("echo")
 				case "merge-base":
 					assert.EqualValues(t, []string{"merge-base", "HEAD", "master"}, args)
 					return exec.Command("test")
 				case "symbolic-ref":
+					if args[2] == "refs/remotes/origin/HEAD" {
+						return exec.Command("echo", "origin/master")
+					}
 					assert.EqualValues(t, []string{"symbolic-ref", "--short", "HEAD"}, args)
 					return exec.Command("echo", "master")
 				}
@@ -236,15 +286,18 @@ func TestCommitListBuilderGetCommits(t *testing.T) {
 
 				switch args[0] {
 				case "rev-list":
-					assert.EqualValues(t, []string{"rev-list", "@{u}..HEAD", "--abbrev-commit"}, args)
-					return exec.Command("echo", "8a2bb0e")
+					assert.EqualValues(t, []string{"rev-list", "@{u}..HEAD"}, args)
+					return exec.Command("echo", "8a2bb0e6bd0e1a5e9b4d4c4f1a6d1e2b0f9b3e3a")
 				case "log":
-					assert.EqualValues(t, []string{"log", "--oneline", "-30"}, args)
-					return exec.Command("echo", "8a2bb0e commit 1\n78976bc commit 2")
+					assert.EqualValues(t, []string{"log", "--format=%H%x00%D%x00%s", "-30"}, args)
+					return exec.Command("printf", "8a2bb0e6bd0e1a5e9b4d4c4f1a6d1e2b0f9b3e3a\\000HEAD -> master, origin/master\\000commit 1\\n78976bc8a7fd9b8d2e6b6a1e1c3a0c9b6a9e7f4d\\000\\000commit 2\\n")
 				case "merge-base":
 					assert.EqualValues(t, []string{"merge-base", "HEAD", "master"}, args)
-					return exec.Command("echo", "78976bc")
+					return exec.Command("echo", "78976bc8a7fd9b8d2e6b6a1e1c3a0c9b6a9e7f4d")
 				case "symbolic-ref":
+					if args[2] == "refs/remotes/origin/HEAD" {
+						return exec.Command("echo", "origin/master")
+					}
 					assert.EqualValues(t, []string{"symbolic-ref", "--short", "HEAD"}, args)
 					return exec.Command("echo", "master")
 				}
@@ -256,18 +309,20 @@ func TestCommitListBuilderGetCommits(t *testing.T) {
 				assert.Len(t, commits, 2)
 				assert.EqualValues(t, []*Commit{
 					{
-						Sha:           "8a2bb0e",
+						Sha:           "8a2bb0e6bd0e1a5e9b4d4c4f1a6d1e2b0f9b3e3a",
 						Name:          "commit 1",
 						Status:        "unpushed",
 						DisplayString: "8a2bb0e commit 1",
+						Refs:          []string{"HEAD -> master", "origin/master"},
 					},
 					{
-						Sha:           "78976bc",
+						Sha:           "78976bc8a7fd9b8d2e6b6a1e1c3a0c9b6a9e7f4d",
 						Name:          "commit 2",
 						Status:        "merged",
 						DisplayString: "78976bc commit 2",
 					},
 				}, commits)
+				assert.EqualValues(t, "8a2bb0e", commits[0].GetDisplayStrings(false)[0])
 			},
 		},
 		{
@@ -277,11 +332,11 @@ func TestCommitListBuilderGetCommits(t *testing.T) {
 
 				switch args[0] {
 				case "rev-list":
-					assert.EqualValues(t, []string{"rev-list", "@{u}..HEAD", "--abbrev-commit"}, args)
+					assert.EqualValues(t, []string{"rev-list", "@{u}..HEAD"}, args)
 					return exec.Command("echo", "8a2bb0e")
 				case "log":
-					assert.EqualValues(t, []string{"log", "--oneline", "-30"}, args)
-					return exec.Command("echo", "8a2bb0e commit 1\n78976bc commit 2")
+					assert.EqualValues(t, []string{"log", "--format=%H%x00%D%x00%s", "-30"}, args)
+					return exec.Command("printf", "8a2bb0e\\000HEAD -> master, origin/master\\000commit 1\\n78976bc\\000\\000commit 2\\n")
 				case "merge-base":
 					assert.EqualValues(t, []string{"merge-base", "HEAD", "master"}, args)
 					return exec.Command("echo", "78976bc")
@@ -312,3 +367,83 @@ func TestCommitListBuilderGetCommits(t *testing.T) {
 		})
 	}
 }
+
+// TestCommitListBuilderGetCommitsWithFullSha is a function.
+func TestCommitListBuilderGetCommitsWithFullSha(t *testing.T) {
+	c := NewDummyCommitListBuilder()
+	c.ShowFullSha = true
+	c.OSCommand.SetCommand(func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+
+		switch args[0] {
+		case "rev-list":
+			assert.EqualValues(t, []string{"rev-list", "@{u}..HEAD"}, args)
+			return exec.Command("echo", "8a2bb0e6bd0e1a5e9b4d4c4f1a6d1e2b0f9b3e3a")
+		case "log":
+			assert.EqualValues(t, []string{"log", "--format=%H%x00%D%x00%s", "-30"}, args)
+			return exec.Command("printf", "8a2bb0e6bd0e1a5e9b4d4c4f1a6d1e2b0f9b3e3a\\000\\000commit 1\\n")
+		case "merge-base":
+			return exec.Command("test")
+		case "symbolic-ref":
+			return exec.Command("echo", "master")
+		}
+
+		return nil
+	})
+
+	commits, err := c.GetCommits()
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*Commit{
+		{
+			Sha:           "8a2bb0e6bd0e1a5e9b4d4c4f1a6d1e2b0f9b3e3a",
+			Name:          "commit 1",
+			Status:        "unpushed",
+			DisplayString: "8a2bb0e commit 1",
+			ShowFullSha:   true,
+		},
+	}, commits)
+	assert.EqualValues(t, "8a2bb0e6bd0e1a5e9b4d4c4f1a6d1e2b0f9b3e3a", commits[0].GetDisplayStrings(false)[0])
+}
+
+// TestParseDecorations is a function.
+func TestParseDecorations(t *testing.T) {
+	type scenario struct {
+		testName    string
+		decorations string
+		expected    []string
+	}
+
+	scenarios := []scenario{
+		{
+			"no decorations",
+			"",
+			nil,
+		},
+		{
+			"HEAD pointing at a branch with its remote and a tag",
+			"HEAD -> main, origin/main, tag: v1.0",
+			[]string{"HEAD -> main", "origin/main", "tag: v1.0"},
+		},
+		{
+			"detached HEAD",
+			"HEAD",
+			[]string{"HEAD"},
+		},
+		{
+			"with brackets as --decorate shows them",
+			" (HEAD -> feature, upstream/feature, origin/HEAD)",
+			[]string{"HEAD -> feature", "upstream/feature", "origin/HEAD"},
+		},
+		{
+			"only tags",
+			"tag: v1.0, tag: v1.0-rc1",
+			[]string{"tag: v1.0", "tag: v1.0-rc1"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, parseDecorations(s.decorations))
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package commands
 
-import "github.com/fatih/color"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+)
 
 // File : A file from git status
 // duplicating this for now
@@ -15,6 +20,7 @@ type File struct {
 	DisplayString           string
 	Type                    string // one of 'file', 'directory', and 'other'
 	ShortStatus             string // e.g. 'AD', ' A', 'M ', '??'
+	Marked                  bool   // to know if this file is to be amended into HEAD with the other marked files
 }
 
 // GetDisplayStrings returns the display string of a file
@@ -23,11 +29,18 @@ func (f *File) GetDisplayStrings(isFocused bool) []string {
 	// objects with each render
 	red := color.New(color.FgRed)
 	green := color.New(color.FgGreen)
+	magenta := color.New(color.FgMagenta)
+
+	markerString := ""
+	if f.Marked {
+		markerString = magenta.Sprint("* ")
+	}
+
 	if !f.Tracked && !f.HasStagedChanges {
-		return []string{red.Sprint(f.DisplayString)}
+		return []string{markerString + red.Sprint(f.DisplayString)}
 	}
 
-	output := green.Sprint(f.DisplayString[0:1])
+	output := markerString + green.Sprint(f.DisplayString[0:1])
 	output += red.Sprint(f.DisplayString[1:3])
 	if f.HasUnstagedChanges {
 		output += red.Sprint(f.Name)
@@ -36,3 +49,58 @@ func (f *File) GetDisplayStrings(isFocused bool) []string {
 	}
 	return []string{output}
 }
+
+// DiffPanels tells us which diff to show in the main and secondary panels when
+// the file is selected, where true means the staged diff. If the file has both
+// staged and unstaged changes we split the panels and show the staged diff in
+// the main panel, otherwise only the main panel is used
+func (f *File) DiffPanels() (mainCached bool, secondaryCached bool, split bool) {
+	if f.HasStagedChanges && f.HasUnstagedChanges {
+		return true, false, true
+	}
+	return !f.HasUnstagedChanges, false, false
+}
+
+// ConflictedFiles filters the given files down to those with merge conflicts
+func ConflictedFiles(files []*File) []*File {
+	conflicted := []*File{}
+	for _, file := range files {
+		if file.HasMergeConflicts {
+			conflicted = append(conflicted, file)
+		}
+	}
+	return conflicted
+}
+
+// FormatChangesSummary summarises the state of the working tree e.g.
+// '3 staged, 2 unstaged, 1 untracked'. A file with both staged and unstaged
+// changes counts towards both
+func FormatChangesSummary(files []*File) string {
+	staged, unstaged, untracked := 0, 0, 0
+	for _, file := range files {
+		if !file.Tracked && !file.HasStagedChanges {
+			untracked++
+			continue
+		}
+		if file.HasStagedChanges {
+			staged++
+		}
+		if file.HasUnstagedChanges {
+			unstaged++
+		}
+	}
+
+	parts := []string{}
+	for _, count := range []struct {
+		n     int
+		label string
+	}{{staged, "staged"}, {unstaged, "unstaged"}, {untracked, "untracked"}} {
+		if count.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", count.n, count.label))
+		}
+	}
+	if len(parts) == 0 {
+		return "clean"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -1,12 +1,17 @@
 package commands
 
 import (
+	"crypto/sha1"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mgutz/str"
@@ -77,6 +82,9 @@ type GitCommand struct {
 	DotGitDir            string
 	onSuccessfulContinue func() error
 	PatchManager         *PatchManager
+	defaultBranch        string
+	commitStats          map[string]commitStat
+	commitStatsMutex     sync.Mutex
 }
 
 // NewGitCommand it runs git commands
@@ -163,6 +171,66 @@ func stashEntryFromLine(line string, index int) *StashEntry {
 		Name:          line,
 		Index:         index,
 		DisplayString: line,
+		Branch:        stashBranch(line),
+	}
+}
+
+// stashBranch gets the branch name out of a stash message, which git writes as
+// 'WIP on <branch>: ...' when no message was given and 'On <branch>: ...'
+// otherwise
+func stashBranch(message string) string {
+	for _, prefix := range []string{"WIP on ", "On "} {
+		if !strings.HasPrefix(message, prefix) {
+			continue
+		}
+		rest := strings.TrimPrefix(message, prefix)
+		if i := strings.Index(rest, ":"); i != -1 {
+			return rest[:i]
+		}
+	}
+	return ""
+}
+
+// GetReflogSince returns the reflog entries since the given time, which can be
+// anything git understands e.g. '1 day ago' or '2020-01-01'
+func (c *GitCommand) GetReflogSince(since string) ([]*ReflogEntry, error) {
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git reflog --since=%s --date=iso --format=\"%%h%%x09%%gd%%x09%%gs\"", c.OSCommand.Quote(since)))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []*ReflogEntry{}
+	for _, line := range utils.SplitLines(output) {
+		if entry := reflogEntryFromLine(line); entry != nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// reflogEntryFromLine parses a line like
+// 'abc1234\tHEAD@{2020-04-01 10:00:00 +1100}\tcommit: add feature'
+func reflogEntryFromLine(line string) *ReflogEntry {
+	fields := strings.SplitN(line, "\t", 3)
+	if len(fields) < 3 {
+		return nil
+	}
+
+	date := fields[1]
+	if start := strings.Index(date, "@{"); start != -1 {
+		date = strings.TrimSuffix(date[start+2:], "}")
+	}
+
+	action, description := fields[2], ""
+	if split := strings.SplitN(fields[2], ": ", 2); len(split) == 2 {
+		action, description = split[0], split[1]
+	}
+
+	return &ReflogEntry{
+		Sha:         fields[0],
+		Date:        date,
+		Action:      action,
+		Description: description,
 	}
 }
 
@@ -171,6 +239,38 @@ func (c *GitCommand) GetStashEntryDiff(index int) (string, error) {
 	return c.OSCommand.RunCommandWithOutput("git stash show -p --color stash@{" + fmt.Sprint(index) + "}")
 }
 
+// GetStashStat returns a summary of the files a stash entry would change.
+// Like the other stash commands, index 0 is the most recent entry
+func (c *GitCommand) GetStashStat(index int) (string, error) {
+	return c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git stash show --stat stash@{%d}", index))
+}
+
+// DiffStashAgainstWorkingTree returns the difference between a stash entry and
+// the working tree, which is empty if the stashed changes are already there
+func (c *GitCommand) DiffStashAgainstWorkingTree(index int) (string, error) {
+	return c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git diff --color %sstash@{%d}", c.diffAlgorithmArg(), index))
+}
+
+// GetStashEntryFiles returns the names of the files changed in a stash entry
+func (c *GitCommand) GetStashEntryFiles(index int) ([]string, error) {
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git stash show --name-only stash@{%d}", index))
+	if err != nil {
+		return nil, err
+	}
+	return utils.SplitLines(output), nil
+}
+
+// RestoreStashFile checks out a single file as it is in the stash entry,
+// leaving the stash entry itself alone
+func (c *GitCommand) RestoreStashFile(index int, fileName string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git checkout stash@{%d} -- %s", index, c.OSCommand.Quote(fileName)))
+}
+
+// GetConflictedFiles returns only the files that still have merge conflicts
+func (c *GitCommand) GetConflictedFiles() []*File {
+	return ConflictedFiles(c.GetStatusFiles())
+}
+
 // GetStatusFiles git status files
 func (c *GitCommand) GetStatusFiles() []*File {
 	statusOutput, _ := c.GitStatus()
@@ -215,6 +315,59 @@ func (c *GitCommand) StashSave(message string) error {
 	return c.OSCommand.RunCommand(fmt.Sprintf("git stash save %s", c.OSCommand.Quote(message)))
 }
 
+// StashSaveWithUntracked stashes untracked files along with tracked changes,
+// leaving a pristine working tree
+func (c *GitCommand) StashSaveWithUntracked(message string) error {
+	if message == "" {
+		return c.OSCommand.RunCommand("git stash -u")
+	}
+	return c.OSCommand.RunCommand(fmt.Sprintf("git stash save --include-untracked %s", c.OSCommand.Quote(message)))
+}
+
+// WithAutoStash stashes any changes (including untracked files), runs the given
+// function, and then pops the stash again. The stash is popped whether or not
+// the function succeeds, so that the user never loses track of their changes
+func (c *GitCommand) WithAutoStash(f func() error) error {
+	output, err := c.OSCommand.RunCommandWithOutput("git stash --include-untracked")
+	if err != nil {
+		return err
+	}
+	// if there was nothing to stash, popping would apply some unrelated stash entry
+	if strings.Contains(output, "No local changes to save") {
+		return f()
+	}
+
+	fErr := f()
+	popErr := c.OSCommand.RunCommand("git stash pop")
+
+	switch {
+	case fErr != nil && popErr != nil:
+		return errors.New(fmt.Sprintf("%s\n%s", fErr.Error(), popErr.Error()))
+	case fErr != nil:
+		return fErr
+	default:
+		return popErr
+	}
+}
+
+// CheckoutCarryingChanges checks out the branch, bringing any uncommitted
+// changes (including untracked files) along with us. If the changes conflict
+// with the branch we say so, as git keeps them in the stash in that case
+func (c *GitCommand) CheckoutCarryingChanges(branch string) error {
+	checkedOut := false
+	err := c.WithAutoStash(func() error {
+		if err := c.Checkout(branch, false); err != nil {
+			return err
+		}
+		checkedOut = true
+		return nil
+	})
+	if err != nil && checkedOut {
+		return errors.New(fmt.Sprintf("%s\n\n%s", c.Tr.SLocalize("CarriedChangesConflict"), err.Error()))
+	}
+	return err
+}
+
 // MergeStatusFiles merge status files
 func (c *GitCommand) MergeStatusFiles(oldFiles, newFiles []*File) []*File {
 	if len(oldFiles) == 0 {
@@ -267,9 +420,15 @@ func (c *GitCommand) GetCurrentBranchUpstreamDifferenceCount() (string, string)
 	return c.GetCommitDifferences("HEAD", "@{u}")
 }
 
+// GetBranchUpstreamDifferenceCount returns the pushables and pullables of the
+// given branch against the branch it tracks, or '?' for both if it doesn't
+// track anything
 func (c *GitCommand) GetBranchUpstreamDifferenceCount(branchName string) (string, string) {
-	upstream := "origin" // hardcoded for now
-	return c.GetCommitDifferences(branchName, fmt.Sprintf("%s/%s", upstream, branchName))
+	remote, remoteBranch, hasUpstream := c.GetUpstreamRemoteAndBranch(branchName)
+	if !hasUpstream {
+		return "?", "?"
+	}
+	return c.GetCommitDifferences(branchName, UpstreamRef(remote, remoteBranch))
 }
 
 // GetCommitDifferences checks how many pushables/pullables there are for the
@@ -292,6 +451,12 @@ func (c *GitCommand) RenameCommit(name string) error {
 	return c.OSCommand.RunCommand(fmt.Sprintf("git commit --allow-empty --amend -m %s", c.OSCommand.Quote(name)))
 }
 
+// GetCommitMessage returns the full message of the given commit
+func (c *GitCommand) GetCommitMessage(sha string) (string, error) {
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git log --format=%%B -n 1 %s", sha))
+	return strings.TrimSpace(output), err
+}
+
 // RebaseBranch interactive rebases onto a branch
 func (c *GitCommand) RebaseBranch(branchName string) error {
 	cmd, err := c.PrepareInteractiveRebaseCommand(branchName, "", false)
@@ -302,9 +467,36 @@ func (c *GitCommand) RebaseBranch(branchName string) error {
 	return c.OSCommand.RunPreparedCommand(cmd)
 }
 
+// RebaseOnto replays the commits after oldBase on the current branch on top of
+// newBase, leaving behind anything before oldBase
+func (c *GitCommand) RebaseOnto(newBase, oldBase string) error {
+	return c.RunSkipEditorCommand(fmt.Sprintf("git rebase --autostash --onto %s %s", newBase, oldBase))
+}
+
+// RebaseBranchOnto moves the commits the current branch has on top of
+// baseBranch onto newBase, leaving behind everything between the point where
+// the branch forked off and newBase
+func (c *GitCommand) RebaseBranchOnto(newBase, baseBranch string) error {
+	oldBase, err := c.GetMergeBase("HEAD", baseBranch)
+	if err != nil {
+		return err
+	}
+	return c.RebaseOnto(newBase, oldBase)
+}
+
+// GetMergeBase returns the best common ancestor of the two refs
+func (c *GitCommand) GetMergeBase(refA, refB string) (string, error) {
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git merge-base %s %s", refA, refB))
+	return strings.TrimSpace(output), err
+}
+
 // Fetch fetch git repo
 func (c *GitCommand) Fetch(unamePassQuestion func(string) string, canAskForCredentials bool) error {
-	return c.OSCommand.DetectUnamePass("git fetch", func(question string) string {
+	command := "git fetch"
+	if c.Config.GetUserConfig().GetBool("git.fetch.tags") {
+		command += " --tags"
+	}
+	return c.OSCommand.DetectUnamePass(command, func(question string) string {
 		if canAskForCredentials {
 			return unamePassQuestion(question)
 		}
@@ -319,9 +511,67 @@ func (c *GitCommand) ResetToCommit(sha string, strength string) error {
 
 // NewBranch create new branch
 func (c *GitCommand) NewBranch(name string) error {
+	if c.useSwitch() {
+		return c.Switch(name, true)
+	}
 	return c.OSCommand.RunCommand(fmt.Sprintf("git checkout -b %s", name))
 }
 
+// RenameBranch renames a branch other than the checked out one
+func (c *GitCommand) RenameBranch(oldName, newName string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git branch -m %s %s", oldName, newName))
+}
+
+// RenameCurrentBranch renames the checked out branch
+func (c *GitCommand) RenameCurrentBranch(newName string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git branch -m %s", newName))
+}
+
+// Switch switches to the given branch using `git switch`, creating it first if
+// create is true
+func (c *GitCommand) Switch(branch string, create bool) error {
+	createArg := ""
+	if create {
+		createArg = "-c "
+	}
+	return c.OSCommand.RunCommand(fmt.Sprintf("git switch %s%s", createArg, c.OSCommand.Quote(branch)))
+}
+
+func (c *GitCommand) useSwitch() bool {
+	return c.Config.GetUserConfig().GetBool("git.useSwitch")
+}
+
+// DiffStatAgainstBranch shows how the working tree differs from the given branch
+func (c *GitCommand) DiffStatAgainstBranch(branchName string) (string, error) {
+	return c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git diff --stat %s", c.OSCommand.Quote(branchName)))
+}
+
+// AddRemote adds a remote with the given name and url
+func (c *GitCommand) AddRemote(name string, url string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git remote add %s %s", c.OSCommand.Quote(name), c.OSCommand.Quote(url)))
+}
+
+// RenameRemote renames a remote
+func (c *GitCommand) RenameRemote(oldName string, newName string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git remote rename %s %s", c.OSCommand.Quote(oldName), c.OSCommand.Quote(newName)))
+}
+
+// RemoveRemote removes a remote along with its remote-tracking branches
+func (c *GitCommand) RemoveRemote(name string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git remote remove %s", c.OSCommand.Quote(name)))
+}
+
+// MoveCommitsToNewBranch creates a new branch at HEAD and then takes the top
+// `count` commits off the current branch, for when you've committed to the
+// wrong branch. Note that this hard resets so any uncommitted changes are lost
+func (c *GitCommand) MoveCommitsToNewBranch(name string, count int) error {
+	if err := c.OSCommand.RunCommand(fmt.Sprintf("git branch %s", c.OSCommand.Quote(name))); err != nil {
+		return err
+	}
+
+	return c.OSCommand.RunCommand(fmt.Sprintf("git reset --hard HEAD~%d", count))
+}
+
 // CurrentBranchName is a function.
 func (c *GitCommand) CurrentBranchName() (string, error) {
 	branchName, err := c.OSCommand.RunCommandWithOutput("git symbolic-ref --short HEAD")
@@ -334,6 +584,92 @@ func (c *GitCommand) CurrentBranchName() (string, error) {
 	return utils.TrimTrailingNewline(branchName), nil
 }
 
+// RunMaintenance runs `git maintenance run`, which does the housekeeping tasks
+// (e.g. gc, commit-graph) configured for the repo, returning what it printed
+func (c *GitCommand) RunMaintenance() (string, error) {
+	return c.OSCommand.RunCommandWithOutput("git maintenance run")
+}
+
+// GetMaintenanceStrategy returns the repo's maintenance.strategy e.g.
+// 'incremental', or an empty string if scheduled maintenance isn't set up
+func (c *GitCommand) GetMaintenanceStrategy() (string, error) {
+	output, err := c.OSCommand.RunCommandWithOutput("git config --get maintenance.strategy")
+	if err != nil {
+		// git config exits with 1 and no output when the key isn't set
+		if strings.TrimSpace(output) == "" {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// GetCommitCount returns how many commits are reachable from HEAD. A repo with
+// no commits yet has no HEAD to count from, which we treat as zero commits
+func (c *GitCommand) GetCommitCount() (int, error) {
+	output, err := c.OSCommand.RunCommandWithOutput("git rev-list --count HEAD")
+	if err != nil {
+		if strings.Contains(output, "ambiguous argument 'HEAD'") || strings.Contains(output, "unknown revision") {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(output))
+}
+
+// IsDetachedHead tells us whether HEAD points directly at a commit rather than
+// at a branch, in which case new commits won't belong to any branch
+func (c *GitCommand) IsDetachedHead() (bool, error) {
+	if _, err := c.OSCommand.RunCommandWithOutput("git symbolic-ref --short HEAD"); err == nil {
+		return false, nil
+	}
+	// symbolic-ref also fails outside of a repo, so we make sure HEAD exists
+	if _, err := c.OSCommand.RunCommandWithOutput("git rev-parse --verify HEAD"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// HasDirtySubmodules tells us whether any submodule has a different commit
+// checked out than the one recorded in the superproject, which `git submodule
+// status` marks with a leading '+'
+func (c *GitCommand) HasDirtySubmodules() (bool, error) {
+	output, err := c.OSCommand.RunCommandWithOutput("git submodule status")
+	if err != nil {
+		return false, err
+	}
+	for _, line := range utils.SplitLines(output) {
+		if strings.HasPrefix(line, "+") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetDefaultBranch returns the name of the repo's main branch, going by the
+// remote's HEAD if we have it and otherwise looking for a local main or master
+// branch. The result is cached given it's unlikely to change
+func (c *GitCommand) GetDefaultBranch() (string, error) {
+	if c.defaultBranch != "" {
+		return c.defaultBranch, nil
+	}
+
+	output, err := c.OSCommand.RunCommandWithOutput("git symbolic-ref --short refs/remotes/origin/HEAD")
+	if err == nil {
+		c.defaultBranch = strings.TrimPrefix(utils.TrimTrailingNewline(output), "origin/")
+		return c.defaultBranch, nil
+	}
+
+	for _, branchName := range []string{"main", "master"} {
+		if _, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git show-ref --verify --quiet refs/heads/%s", branchName)); err == nil {
+			c.defaultBranch = branchName
+			return c.defaultBranch, nil
+		}
+	}
+
+	return "", errors.New(c.Tr.SLocalize("NoDefaultBranch"))
+}
+
 // DeleteBranch delete branch
 func (c *GitCommand) DeleteBranch(branch string, force bool) error {
 	command := "git branch -d"
@@ -345,6 +681,12 @@ func (c *GitCommand) DeleteBranch(branch string, force bool) error {
 	return c.OSCommand.RunCommand(fmt.Sprintf("%s %s", command, branch))
 }
 
+// DeleteRemoteBranch deletes a branch on the given remote
+func (c *GitCommand) DeleteRemoteBranch(remote, branch string, ask func(string) string) error {
+	command := fmt.Sprintf("git push %s --delete %s", c.OSCommand.Quote(remote), c.OSCommand.Quote(branch))
+	return c.OSCommand.DetectUnamePass(command, ask)
+}
+
 // ListStash list stash
 func (c *GitCommand) ListStash() (string, error) {
 	return c.OSCommand.RunCommandWithOutput("git stash list")
@@ -372,6 +714,89 @@ func (c *GitCommand) usingGpg() bool {
 	return value == "true" || value == "1" || value == "yes" || value == "on"
 }
 
+var commitTrailerRegexp = regexp.MustCompile(`^[\w-]+: \S`)
+
+// FormatCommitMessage tidies up a commit message written in our own prompt
+// according to the user's config, separating the subject from the body and
+// hard-wrapping the body
+func (c *GitCommand) FormatCommitMessage(message string) string {
+	if c.Config.GetUserConfig().GetBool("git.commit.blankLineAfterSubject") {
+		message = ensureBlankLineAfterSubject(message)
+	}
+	return wrapCommitBody(message, c.Config.GetUserConfig().GetInt("git.commit.bodyWrap"))
+}
+
+// ensureBlankLineAfterSubject inserts the blank line git expects between the
+// subject and the body if the body starts straight after the subject
+func ensureBlankLineAfterSubject(message string) string {
+	lines := strings.Split(message, "\n")
+	if len(lines) < 2 || strings.TrimSpace(lines[1]) == "" {
+		return message
+	}
+	return lines[0] + "\n\n" + strings.Join(lines[1:], "\n")
+}
+
+// wrapCommitBody wraps every line after the subject line to the given width,
+// leaving trailers like 'Signed-off-by: ...' alone. A width of zero or less
+// means no wrapping
+func wrapCommitBody(message string, width int) string {
+	if width <= 0 {
+		return message
+	}
+
+	lines := strings.Split(message, "\n")
+	result := []string{lines[0]}
+	for _, line := range lines[1:] {
+		if textColumns(line) <= width || commitTrailerRegexp.MatchString(line) {
+			result = append(result, line)
+			continue
+		}
+
+		// indented lines e.g. code snippets or list items keep their
+		// indentation on each of the lines they're wrapped onto
+		content := strings.TrimLeft(line, " \t")
+		indent := line[:len(line)-len(content)]
+		wrappedLine := indent
+		for _, word := range strings.Fields(content) {
+			if wrappedLine != indent && textColumns(wrappedLine)+1+textColumns(word) > width {
+				result = append(result, wrappedLine)
+				wrappedLine = indent
+			}
+			if wrappedLine != indent {
+				wrappedLine += " "
+			}
+			wrappedLine += word
+		}
+		result = append(result, wrappedLine)
+	}
+
+	return strings.Join(result, "\n")
+}
+
+// textColumns returns how many columns a line takes up, with tabs taking us to
+// the next multiple of eight as they do in git's output
+func textColumns(text string) int {
+	columns := 0
+	for _, r := range text {
+		if r == '\t' {
+			columns += 8 - columns%8
+		} else {
+			columns++
+		}
+	}
+	return columns
+}
+
+// CommitFlags returns the flags to pass to Commit. Hooks are skipped if the
+// message starts with the skip-hook prefix, or if the user has asked to skip
+// them for this commit
+func CommitFlags(message string, skipHookPrefix string, skipHooks bool) string {
+	if skipHooks || (skipHookPrefix != "" && strings.HasPrefix(message, skipHookPrefix)) {
+		return "--no-verify"
+	}
+	return ""
+}
+
 // Commit commits to git
 func (c *GitCommand) Commit(message string, flags string) (*exec.Cmd, error) {
 	command := fmt.Sprintf("git commit %s -m %s", flags, c.OSCommand.Quote(message))
@@ -382,9 +807,16 @@ func (c *GitCommand) Commit(message string, flags string) (*exec.Cmd, error) {
 	return nil, c.OSCommand.RunCommand(command)
 }
 
-// AmendHead amends HEAD with whatever is staged in your working tree
-func (c *GitCommand) AmendHead() (*exec.Cmd, error) {
-	command := "git commit --amend --no-edit --allow-empty"
+// CommitPathspec commits only the changes under the given paths, leaving any
+// other staged changes staged. As with any 'git commit -- <paths>' the working
+// tree state of those paths is committed, whether or not it's been staged
+func (c *GitCommand) CommitPathspec(message string, paths []string, flags string) (*exec.Cmd, error) {
+	quotedPaths := make([]string, len(paths))
+	for i, path := range paths {
+		quotedPaths[i] = c.OSCommand.Quote(path)
+	}
+
+	command := fmt.Sprintf("git commit %s -m %s -- %s", flags, c.OSCommand.Quote(message), strings.Join(quotedPaths, " "))
 	if c.usingGpg() {
 		return c.OSCommand.PrepareSubProcess(c.OSCommand.Platform.shell, c.OSCommand.Platform.shellArg, command), nil
 	}
@@ -392,125 +824,577 @@ func (c *GitCommand) AmendHead() (*exec.Cmd, error) {
 	return nil, c.OSCommand.RunCommand(command)
 }
 
-// Pull pulls from repo
-func (c *GitCommand) Pull(ask func(string) string) error {
-	return c.OSCommand.DetectUnamePass("git pull --no-edit", ask)
-}
-
-// Push pushes to a branch
-func (c *GitCommand) Push(branchName string, force bool, upstream string, ask func(string) string) error {
-	forceFlag := ""
-	if force {
-		forceFlag = "--force-with-lease"
+// CommitAndPush runs the given commit and then the given push, but only if the
+// commit succeeded. A commit that has to happen in a subprocess (e.g. to sign
+// it with gpg) can't be followed by the push, so that's not supported
+func (c *GitCommand) CommitAndPush(commit func() (*exec.Cmd, error), push func() error) error {
+	if c.usingGpg() {
+		return errors.New(c.Tr.SLocalize("DisabledForGPG"))
 	}
 
-	setUpstreamArg := ""
-	if upstream != "" {
-		setUpstreamArg = "--set-upstream " + upstream
+	sub, err := commit()
+	if err != nil {
+		return err
+	}
+	if sub != nil {
+		return errors.New(c.Tr.SLocalize("DisabledForGPG"))
 	}
 
-	cmd := fmt.Sprintf("git push %s %s", forceFlag, setUpstreamArg)
-	return c.OSCommand.DetectUnamePass(cmd, ask)
+	return push()
 }
 
-// CatFile obtains the content of a file
-func (c *GitCommand) CatFile(fileName string) (string, error) {
-	return c.OSCommand.RunCommandWithOutput(fmt.Sprintf("cat %s", c.OSCommand.Quote(fileName)))
+// CommitWithAuthor commits with the given author in the form 'Name <email>',
+// e.g. for when you're committing a patch someone else wrote
+func (c *GitCommand) CommitWithAuthor(message string, flags string, author string) (*exec.Cmd, error) {
+	return c.Commit(message, strings.TrimSpace(flags+" "+c.authorFlag(author)))
 }
 
-// StageFile stages a file
-func (c *GitCommand) StageFile(fileName string) error {
-	return c.OSCommand.RunCommand(fmt.Sprintf("git add %s", c.OSCommand.Quote(fileName)))
+func (c *GitCommand) authorFlag(author string) string {
+	return "--author=" + c.OSCommand.Quote(author)
 }
 
-// StageAll stages all files
-func (c *GitCommand) StageAll() error {
-	return c.OSCommand.RunCommand("git add -A")
-}
+// GetRecentAuthors returns the distinct authors of the last `limit` commits,
+// most recent first
+func (c *GitCommand) GetRecentAuthors(limit int) ([]string, error) {
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git log --format=\"%%an <%%ae>\" -%d", limit))
+	if err != nil {
+		return nil, err
+	}
 
-// UnstageAll stages all files
-func (c *GitCommand) UnstageAll() error {
-	return c.OSCommand.RunCommand("git reset")
+	return parseAuthors(output), nil
 }
 
-// UnStageFile unstages a file
-func (c *GitCommand) UnStageFile(fileName string, tracked bool) error {
-	command := "git rm --cached %s"
-	if tracked {
-		command = "git reset HEAD %s"
-	}
-
-	// renamed files look like "file1 -> file2"
-	fileNames := strings.Split(fileName, " -> ")
-	for _, name := range fileNames {
-		if err := c.OSCommand.RunCommand(fmt.Sprintf(command, c.OSCommand.Quote(name))); err != nil {
-			return err
+func parseAuthors(output string) []string {
+	authors := []string{}
+	seen := map[string]bool{}
+	for _, author := range utils.SplitLines(output) {
+		if author == "" || seen[author] {
+			continue
 		}
+		seen[author] = true
+		authors = append(authors, author)
 	}
-	return nil
+	return authors
 }
 
-// GitStatus returns the plaintext short status of the repo
-func (c *GitCommand) GitStatus() (string, error) {
-	return c.OSCommand.RunCommandWithOutput("git status --untracked-files=all --porcelain")
-}
+// SaveCommitDraft stores an unfinished commit message for the current repo so
+// that it isn't lost when the commit message panel is closed
+func (c *GitCommand) SaveCommitDraft(message string) error {
+	if message == "" {
+		return c.ClearCommitDraft()
+	}
 
-// IsInMergeState states whether we are still mid-merge
-func (c *GitCommand) IsInMergeState() (bool, error) {
-	output, err := c.OSCommand.RunCommandWithOutput("git status --untracked-files=all")
+	path, err := c.commitDraftPath()
 	if err != nil {
-		return false, err
+		return err
 	}
-	return strings.Contains(output, "conclude merge") || strings.Contains(output, "unmerged paths"), nil
+	return c.OSCommand.CreateFileWithContent(path, message)
 }
 
-// RebaseMode returns "" for non-rebase mode, "normal" for normal rebase
-// and "interactive" for interactive rebase
-func (c *GitCommand) RebaseMode() (string, error) {
-	exists, err := c.OSCommand.FileExists(fmt.Sprintf("%s/rebase-apply", c.DotGitDir))
+// LoadCommitDraft returns the draft commit message for the current repo, if any
+func (c *GitCommand) LoadCommitDraft() (string, error) {
+	path, err := c.commitDraftPath()
 	if err != nil {
 		return "", err
 	}
-	if exists {
-		return "normal", nil
-	}
-	exists, err = c.OSCommand.FileExists(fmt.Sprintf("%s/rebase-merge", c.DotGitDir))
-	if exists {
-		return "interactive", err
-	} else {
-		return "", err
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", WrapError(err)
 	}
+	return string(content), nil
 }
 
-// DiscardAllFileChanges directly
-func (c *GitCommand) DiscardAllFileChanges(file *File) error {
-	// if the file isn't tracked, we assume you want to delete it
-	quotedFileName := c.OSCommand.Quote(file.Name)
-	if file.HasStagedChanges || file.HasMergeConflicts {
-		if err := c.OSCommand.RunCommand(fmt.Sprintf("git reset -- %s", quotedFileName)); err != nil {
-			return err
-		}
+// ClearCommitDraft removes the draft commit message for the current repo
+func (c *GitCommand) ClearCommitDraft() error {
+	path, err := c.commitDraftPath()
+	if err != nil {
+		return err
 	}
 
-	if !file.Tracked {
-		return c.removeFile(file.Name)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return WrapError(err)
 	}
-	return c.DiscardUnstagedFileChanges(file)
+	return nil
 }
 
-// DiscardUnstagedFileChanges directly
-func (c *GitCommand) DiscardUnstagedFileChanges(file *File) error {
-	quotedFileName := c.OSCommand.Quote(file.Name)
-	return c.OSCommand.RunCommand(fmt.Sprintf("git checkout -- %s", quotedFileName))
+// commitDraftPath keys drafts by a hash of the repo's path so that each repo
+// gets its own draft without us having to sanitise the path
+func (c *GitCommand) commitDraftPath() (string, error) {
+	repoPath, err := os.Getwd()
+	if err != nil {
+		return "", WrapError(err)
+	}
+
+	key := fmt.Sprintf("%x", sha1.Sum([]byte(repoPath)))
+	return filepath.Join(c.Config.GetUserConfigDir(), "commit_drafts", key), nil
 }
 
-// Checkout checks out a branch, with --force if you set the force arg to true
-func (c *GitCommand) Checkout(branch string, force bool) error {
+const amendHeadCommand = "git commit --amend --no-edit --allow-empty"
+
+// AmendHead amends HEAD with whatever is staged in your working tree
+func (c *GitCommand) AmendHead() (*exec.Cmd, error) {
+	command := amendHeadCommand
+	if c.usingGpg() {
+		return c.OSCommand.PrepareSubProcess(c.OSCommand.Platform.shell, c.OSCommand.Platform.shellArg, command), nil
+	}
+
+	return nil, c.OSCommand.RunCommand(command)
+}
+
+// AmendHeadKeepDate amends HEAD like AmendHead does, but keeps the original
+// author date and uses it for the committer date too, so the commit looks as
+// though it was always this way
+func (c *GitCommand) AmendHeadKeepDate() (*exec.Cmd, error) {
+	output, err := c.OSCommand.RunCommandWithOutput("git log --format=%aD -n 1 HEAD")
+	if err != nil {
+		return nil, err
+	}
+	date := strings.TrimSpace(output)
+
+	command := fmt.Sprintf("%s --date=%s", amendHeadCommand, c.OSCommand.Quote(date))
+	if c.usingGpg() {
+		cmd := c.OSCommand.PrepareSubProcess(c.OSCommand.Platform.shell, c.OSCommand.Platform.shellArg, command)
+		cmd.Env = append(cmd.Env, "GIT_COMMITTER_DATE="+date)
+		return cmd, nil
+	}
+
+	cmd := c.OSCommand.ExecutableFromString(command)
+	cmd.Env = append(cmd.Env, "GIT_COMMITTER_DATE="+date)
+	return nil, c.OSCommand.RunExecutable(cmd)
+}
+
+// AmendAndContinueRebase amends HEAD with whatever is staged and then continues
+// the rebase, for when we've stopped at a commit to edit it. If the user has
+// gpg enabled we do both in a subprocess so that they can enter their password
+func (c *GitCommand) AmendAndContinueRebase() (*exec.Cmd, error) {
+	if c.usingGpg() {
+		command := fmt.Sprintf("%s && git rebase --continue", amendHeadCommand)
+		return c.OSCommand.PrepareSubProcess(c.OSCommand.Platform.shell, c.OSCommand.Platform.shellArg, command), nil
+	}
+
+	if _, err := c.AmendHead(); err != nil {
+		return nil, err
+	}
+
+	return nil, c.GenericMerge("rebase", "continue")
+}
+
+// AmendFileToHead amends HEAD with the changes of a single file, leaving
+// any other staged files alone
+func (c *GitCommand) AmendFileToHead(fileName string) (*exec.Cmd, error) {
+	command := fmt.Sprintf("git commit --amend --no-edit --only -- %s", c.OSCommand.Quote(fileName))
+	if c.usingGpg() {
+		return c.OSCommand.PrepareSubProcess(c.OSCommand.Platform.shell, c.OSCommand.Platform.shellArg, command), nil
+	}
+
+	return nil, c.OSCommand.RunCommand(command)
+}
+
+// AmendHeadWithFiles stages the given files and amends HEAD with only those,
+// leaving anything else that's staged for a later commit. Staging first means
+// untracked files can be included too
+func (c *GitCommand) AmendHeadWithFiles(fileNames []string) (*exec.Cmd, error) {
+	if len(fileNames) == 0 {
+		return nil, errors.New(c.Tr.SLocalize("NoFilesMarkedForAmend"))
+	}
+
+	quotedFileNames := make([]string, len(fileNames))
+	for i, fileName := range fileNames {
+		quotedFileNames[i] = c.OSCommand.Quote(fileName)
+	}
+	pathspec := strings.Join(quotedFileNames, " ")
+
+	if err := c.OSCommand.RunCommand(fmt.Sprintf("git add -- %s", pathspec)); err != nil {
+		return nil, err
+	}
+
+	command := fmt.Sprintf("git commit --amend --no-edit --only -- %s", pathspec)
+	if c.usingGpg() {
+		return c.OSCommand.PrepareSubProcess(c.OSCommand.Platform.shell, c.OSCommand.Platform.shellArg, command), nil
+	}
+
+	return nil, c.OSCommand.RunCommand(command)
+}
+
+// Pull pulls from repo
+func (c *GitCommand) Pull(ask func(string) string) error {
+	return c.OSCommand.DetectUnamePass("git pull --no-edit", ask)
+}
+
+// Push pushes to a branch
+func (c *GitCommand) Push(branchName string, force bool, upstream string, ask func(string) string) error {
+	forceFlag := ""
+	if force {
+		if c.IsProtectedBranch(branchName) {
+			return errors.New(c.Tr.SLocalize("ForcePushProtectedBranch"))
+		}
+		forceFlag = "--force-with-lease"
+	}
+
+	setUpstreamArg := ""
+	if upstream != "" {
+		setUpstreamArg = "--set-upstream " + upstream
+	}
+
+	cmd := fmt.Sprintf("git push %s %s", forceFlag, setUpstreamArg)
+	return c.OSCommand.DetectUnamePass(cmd, ask)
+}
+
+// IsProtectedBranch tells us whether the branch matches one of the patterns in
+// git.protectedBranches, meaning we won't force push to it
+func (c *GitCommand) IsProtectedBranch(branchName string) bool {
+	return isProtectedBranch(branchName, c.Config.GetUserConfig().GetStringSlice("git.protectedBranches"))
+}
+
+// isProtectedBranch matches the branch name against glob patterns like
+// 'release/*', where '*' doesn't match a slash
+func isProtectedBranch(name string, protected []string) bool {
+	for _, pattern := range protected {
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// CatFile obtains the content of a file
+func (c *GitCommand) CatFile(fileName string) (string, error) {
+	return c.OSCommand.RunCommandWithOutput(fmt.Sprintf("cat %s", c.OSCommand.Quote(fileName)))
+}
+
+// StageFile stages a file
+func (c *GitCommand) StageFile(fileName string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git add %s", c.OSCommand.Quote(fileName)))
+}
+
+// GetStagedDiff returns the diff of everything that's staged, i.e. what the
+// next commit will contain
+func (c *GitCommand) GetStagedDiff() (string, error) {
+	return c.OSCommand.RunCommandWithOutput("git diff --cached --color")
+}
+
+// StageGlob stages every file matching the given pathspec e.g. '*.go'. The
+// pattern is quoted so that git, rather than the shell, does the matching,
+// meaning it also applies to files in subdirectories
+func (c *GitCommand) StageGlob(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return errors.New(c.Tr.SLocalize("EmptyPattern"))
+	}
+	return c.OSCommand.RunCommand(fmt.Sprintf("git add -- %s", c.OSCommand.Quote(pattern)))
+}
+
+// IsLargeFile tells us whether the file is bigger than the user's
+// git.largeFileWarningSize (in megabytes) so that we can warn before staging it
+func (c *GitCommand) IsLargeFile(fileName string) (bool, error) {
+	exists, err := c.OSCommand.FileExists(fileName)
+	if err != nil || !exists {
+		// deleted files have nothing to warn about
+		return false, err
+	}
+
+	size, err := c.OSCommand.FileSize(fileName)
+	if err != nil {
+		return false, err
+	}
+
+	return exceedsFileSizeLimit(size, c.Config.GetUserConfig().GetInt("git.largeFileWarningSize")), nil
+}
+
+// LargeFiles filters the given files down to those IsLargeFile warns about
+func (c *GitCommand) LargeFiles(fileNames []string) ([]string, error) {
+	largeFiles := []string{}
+	for _, fileName := range fileNames {
+		isLarge, err := c.IsLargeFile(fileName)
+		if err != nil {
+			return nil, err
+		}
+		if isLarge {
+			largeFiles = append(largeFiles, fileName)
+		}
+	}
+	return largeFiles, nil
+}
+
+// FilesMatchingGlob returns the files StageGlob would stage for the given
+// pattern, going by the "add 'file'" lines of a dry run
+func (c *GitCommand) FilesMatchingGlob(pattern string) ([]string, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return nil, errors.New(c.Tr.SLocalize("EmptyPattern"))
+	}
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git add --dry-run -- %s", c.OSCommand.Quote(pattern)))
+	if err != nil {
+		return nil, err
+	}
+
+	fileNames := []string{}
+	for _, line := range utils.SplitLines(output) {
+		if strings.HasPrefix(line, "add '") && strings.HasSuffix(line, "'") {
+			fileNames = append(fileNames, strings.TrimSuffix(strings.TrimPrefix(line, "add '"), "'"))
+		}
+	}
+	return fileNames, nil
+}
+
+// exceedsFileSizeLimit takes a size in bytes and a limit in megabytes. A limit
+// of 0 or less disables the check
+func exceedsFileSizeLimit(size int64, limitInMB int) bool {
+	if limitInMB <= 0 {
+		return false
+	}
+	return size > int64(limitInMB)*1024*1024
+}
+
+// StageAll stages all files
+func (c *GitCommand) StageAll() error {
+	return c.OSCommand.RunCommand("git add -A")
+}
+
+// UnstageAll stages all files
+func (c *GitCommand) UnstageAll() error {
+	return c.OSCommand.RunCommand("git reset")
+}
+
+// UnstageFiles unstages each of the given files, leaving their changes in the
+// working tree
+func (c *GitCommand) UnstageFiles(files []*File) error {
+	for _, file := range files {
+		if err := c.UnStageFile(file.Name, file.Tracked); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnStageFile unstages a file
+func (c *GitCommand) UnStageFile(fileName string, tracked bool) error {
+	command := "git rm --cached %s"
+	if tracked {
+		command = "git reset HEAD %s"
+	}
+
+	// renamed files look like "file1 -> file2"
+	fileNames := strings.Split(fileName, " -> ")
+	for _, name := range fileNames {
+		if err := c.OSCommand.RunCommand(fmt.Sprintf(command, c.OSCommand.Quote(name))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GitStatus returns the plaintext short status of the repo
+func (c *GitCommand) GitStatus() (string, error) {
+	return c.OSCommand.RunCommandWithOutput("git status --untracked-files=all --porcelain")
+}
+
+// IsInMergeState states whether we are still mid-merge
+func (c *GitCommand) IsInMergeState() (bool, error) {
+	output, err := c.OSCommand.RunCommandWithOutput("git status --untracked-files=all")
+	if err != nil {
+		return false, err
+	}
+	return strings.Contains(output, "conclude merge") || strings.Contains(output, "unmerged paths"), nil
+}
+
+// RebaseMode returns "" for non-rebase mode, "normal" for normal rebase
+// and "interactive" for interactive rebase
+func (c *GitCommand) RebaseMode() (string, error) {
+	exists, err := c.OSCommand.FileExists(fmt.Sprintf("%s/rebase-apply", c.DotGitDir))
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		// git am uses the same directory, so we need to rule that out
+		applying, err := c.AmMode()
+		if err != nil || applying {
+			return "", err
+		}
+		return "normal", nil
+	}
+	exists, err = c.OSCommand.FileExists(fmt.Sprintf("%s/rebase-merge", c.DotGitDir))
+	if exists {
+		return "interactive", err
+	} else {
+		return "", err
+	}
+}
+
+// AmMode tells us whether we're in the middle of applying mailbox patches with
+// git am, e.g. because one of them didn't apply cleanly
+func (c *GitCommand) AmMode() (bool, error) {
+	return c.OSCommand.FileExists(fmt.Sprintf("%s/rebase-apply/applying", c.DotGitDir))
+}
+
+// CherryPickMode tells us whether we're in the middle of a cherry-pick
+func (c *GitCommand) CherryPickMode() (bool, error) {
+	return c.OSCommand.FileExists(fmt.Sprintf("%s/CHERRY_PICK_HEAD", c.DotGitDir))
+}
+
+// MergeMode tells us whether we're in the middle of a merge, e.g. a pull that
+// stopped on conflicts. Unlike IsInMergeState this doesn't depend on the output
+// of git status, which varies between git versions
+func (c *GitCommand) MergeMode() (bool, error) {
+	return c.OSCommand.FileExists(fmt.Sprintf("%s/MERGE_HEAD", c.DotGitDir))
+}
+
+// RevertMode tells us whether we're in the middle of a revert
+func (c *GitCommand) RevertMode() (bool, error) {
+	return c.OSCommand.FileExists(fmt.Sprintf("%s/REVERT_HEAD", c.DotGitDir))
+}
+
+// LastFetchTime returns when the repo was last fetched, going by when git last
+// wrote FETCH_HEAD. It returns the zero time if the repo has never been fetched
+func (c *GitCommand) LastFetchTime() (time.Time, error) {
+	path := filepath.Join(c.DotGitDir, "FETCH_HEAD")
+	exists, err := c.OSCommand.FileExists(path)
+	if err != nil || !exists {
+		return time.Time{}, err
+	}
+	return c.OSCommand.FileModTime(path)
+}
+
+// BisectMode tells us whether a bisect session has been started
+func (c *GitCommand) BisectMode() (bool, error) {
+	return c.OSCommand.FileExists(fmt.Sprintf("%s/BISECT_LOG", c.DotGitDir))
+}
+
+// AbortAll detects whichever operation is currently in progress and aborts it,
+// returning the repo to the state it was in before the operation began
+func (c *GitCommand) AbortAll() error {
+	applying, err := c.AmMode()
+	if err != nil {
+		return err
+	}
+	if applying {
+		return c.GenericMerge("am", "abort")
+	}
+	rebaseMode, err := c.RebaseMode()
+	if err != nil {
+		return err
+	}
+	cherryPicking, err := c.CherryPickMode()
+	if err != nil {
+		return err
+	}
+	reverting, err := c.RevertMode()
+	if err != nil {
+		return err
+	}
+	merging, err := c.IsInMergeState()
+	if err != nil {
+		return err
+	}
+	mergeHeadExists, err := c.MergeMode()
+	if err != nil {
+		return err
+	}
+
+	commandType := getAbortCommandType(rebaseMode, cherryPicking, reverting, merging || mergeHeadExists)
+	if commandType == "" {
+		return errors.New(c.Tr.SLocalize("NothingToAbort"))
+	}
+
+	return c.GenericMerge(commandType, "abort")
+}
+
+// getAbortCommandType returns the git command whose --abort flag will get us
+// out of the current state. A rebase takes precedence because conflicts
+// mid-rebase also look like a merge state
+func getAbortCommandType(rebaseMode string, cherryPicking bool, reverting bool, merging bool) string {
+	switch {
+	case rebaseMode != "":
+		return "rebase"
+	case cherryPicking:
+		return "cherry-pick"
+	case reverting:
+		return "revert"
+	case merging:
+		return "merge"
+	default:
+		return ""
+	}
+}
+
+// CanContinueRebase tells us whether we're mid-rebase with all conflicts
+// resolved, meaning we can safely run `git rebase --continue`
+func (c *GitCommand) CanContinueRebase() (bool, error) {
+	rebaseMode, err := c.RebaseMode()
+	if err != nil {
+		return false, err
+	}
+	inMergeState, err := c.IsInMergeState()
+	if err != nil {
+		return false, err
+	}
+	return canContinueRebase(rebaseMode, inMergeState), nil
+}
+
+func canContinueRebase(rebaseMode string, inMergeState bool) bool {
+	return rebaseMode != "" && !inMergeState
+}
+
+// DiscardAllFileChanges directly
+func (c *GitCommand) DiscardAllFileChanges(file *File) error {
+	// if the file isn't tracked, we assume you want to delete it
+	quotedFileName := c.OSCommand.Quote(file.Name)
+	if file.HasStagedChanges || file.HasMergeConflicts {
+		if err := c.OSCommand.RunCommand(fmt.Sprintf("git reset -- %s", quotedFileName)); err != nil {
+			return err
+		}
+	}
+
+	if !file.Tracked {
+		return c.removeFile(file.Name)
+	}
+	return c.DiscardUnstagedFileChanges(file)
+}
+
+// DiscardUnstagedFileChanges directly
+func (c *GitCommand) DiscardUnstagedFileChanges(file *File) error {
+	quotedFileName := c.OSCommand.Quote(file.Name)
+	return c.OSCommand.RunCommand(fmt.Sprintf("git checkout -- %s", quotedFileName))
+}
+
+// ResetFileToHead resets both the index and the working tree version of a file
+// to how it is in HEAD
+func (c *GitCommand) ResetFileToHead(file *File) error {
+	quotedFileName := c.OSCommand.Quote(file.Name)
+	return c.OSCommand.RunCommand(fmt.Sprintf("git checkout HEAD -- %s", quotedFileName))
+}
+
+// RemoveTrackedFile deletes the file and stages its deletion
+func (c *GitCommand) RemoveTrackedFile(fileName string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git rm %s", c.OSCommand.Quote(fileName)))
+}
+
+// RemoveTrackedFileKeepLocal stages the file's deletion but leaves it on disk,
+// so that it becomes untracked
+func (c *GitCommand) RemoveTrackedFileKeepLocal(fileName string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git rm --cached %s", c.OSCommand.Quote(fileName)))
+}
+
+// Checkout checks out a branch, with --force if you set the force arg to true.
+// `git switch` only takes local branches without --detach, so we leave shas,
+// tags and remote branches to `git checkout` even when git.useSwitch is set
+func (c *GitCommand) Checkout(branch string, force bool) error {
 	forceArg := ""
 	if force {
 		forceArg = "--force "
 	}
-	return c.OSCommand.RunCommand(fmt.Sprintf("git checkout %s %s", forceArg, branch))
+	command := "checkout"
+	if c.useSwitch() && c.isLocalBranch(branch) {
+		command = "switch"
+	}
+	return c.OSCommand.RunCommand(fmt.Sprintf("git %s %s%s", command, forceArg, c.OSCommand.Quote(branch)))
+}
+
+func (c *GitCommand) isLocalBranch(name string) bool {
+	_, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git show-ref --verify --quiet %s", c.OSCommand.Quote("refs/heads/"+name)))
+	return err == nil
 }
 
 // PrepareCommitSubProcess prepares a subprocess for `git commit`
@@ -523,11 +1407,39 @@ func (c *GitCommand) PrepareCommitAmendSubProcess() *exec.Cmd {
 	return c.OSCommand.PrepareSubProcess("git", "commit", "--amend", "--allow-empty")
 }
 
-// GetBranchGraph gets the color-formatted graph of the log for the given branch
-// Currently it limits the result to 100 commits, but when we get async stuff
-// working we can do lazy loading
-func (c *GitCommand) GetBranchGraph(branchName string) (string, error) {
-	return c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git log --graph --color --abbrev-commit --decorate --date=relative --pretty=medium -100 %s", branchName))
+// GetBranchGraph gets the color-formatted graph of the log for the given
+// branch, limited to the given number of commits. Further commits can be
+// loaded with GetBranchGraphFrom
+func (c *GitCommand) GetBranchGraph(branchName string, limit int) (string, error) {
+	return c.GetBranchGraphFrom(branchName, 0, limit)
+}
+
+// GetBranchGraphFrom gets a page of the graph of the log for the given branch,
+// skipping the commits we've already loaded
+func (c *GitCommand) GetBranchGraphFrom(branchName string, skip, limit int) (string, error) {
+	skipArg := ""
+	if skip > 0 {
+		skipArg = fmt.Sprintf("--skip=%d ", skip)
+	}
+	return c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git log --graph --color --abbrev-commit --decorate --date=relative --pretty=medium %s-n %d %s", skipArg, limit, branchName))
+}
+
+// GetAllBranchesGraph gets the graph of the log across all branches, one commit
+// per line so that a commit can be picked out of it with GraphLineSha
+func (c *GitCommand) GetAllBranchesGraph() (string, error) {
+	return c.OSCommand.RunCommandWithOutput("git log --graph --all --oneline --decorate --color=never -100")
+}
+
+var graphLineShaRegexp = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// GraphLineSha returns the sha of the commit on a line of a oneline graph, or
+// an empty string if the line only contains the graph itself e.g. '|/'
+func GraphLineSha(line string) string {
+	fields := strings.Fields(strings.TrimLeft(line, "*|/\\_.- "))
+	if len(fields) == 0 || !graphLineShaRegexp.MatchString(fields[0]) {
+		return ""
+	}
+	return fields[0]
 }
 
 func (c *GitCommand) GetUpstreamForBranch(branchName string) (string, error) {
@@ -535,14 +1447,230 @@ func (c *GitCommand) GetUpstreamForBranch(branchName string) (string, error) {
 	return strings.TrimSpace(output), err
 }
 
+// GetUpstreamRemoteAndBranch returns the remote a branch tracks along with the
+// name of the branch on that remote, as set in the branch.<name>.remote and
+// branch.<name>.merge config keys. A remote of '.' means the branch tracks
+// another local branch. If the branch has no upstream we fall back to a branch
+// of the same name on origin, with the bool returned telling the caller that
+// this is only a guess
+func (c *GitCommand) GetUpstreamRemoteAndBranch(branchName string) (string, string, bool) {
+	remote, err := c.getBranchConfig(branchName, "remote")
+	if err != nil || remote == "" {
+		return "origin", branchName, false
+	}
+	merge, err := c.getBranchConfig(branchName, "merge")
+	if err != nil || merge == "" {
+		return "origin", branchName, false
+	}
+	return remote, strings.TrimPrefix(merge, "refs/heads/"), true
+}
+
+func (c *GitCommand) getBranchConfig(branchName, key string) (string, error) {
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git config --get %s", c.OSCommand.Quote(fmt.Sprintf("branch.%s.%s", branchName, key))))
+	return strings.TrimSpace(output), err
+}
+
+// UpstreamRef returns the ref to use locally for an upstream returned by
+// GetUpstreamRemoteAndBranch: the remote tracking branch, or the branch itself
+// if the upstream is a local branch
+func UpstreamRef(remote, remoteBranch string) string {
+	if remote == "." {
+		return remoteBranch
+	}
+	return remote + "/" + remoteBranch
+}
+
+// CheckIgnore returns the gitignore rule that matches the given path, in the
+// form 'source:line:pattern<tab>path', or an empty string if no rule matches.
+// The path doesn't need to exist, and we ignore the index so that we still
+// find the rule for a file that was committed before it was ignored
+func (c *GitCommand) CheckIgnore(path string) (string, error) {
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git check-ignore --no-index -v %s", c.OSCommand.Quote(path)))
+	if err != nil {
+		// check-ignore exits with 1 and no output when the file isn't ignored
+		if strings.TrimSpace(output) == "" {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// MoveFile moves or renames a file with git mv, so that the index is updated
+// along with the working tree. git mv would refuse to overwrite an existing
+// file, so we check for that up front to give a friendlier error
+func (c *GitCommand) MoveFile(from, to string) error {
+	exists, err := c.OSCommand.FileExists(to)
+	if err != nil {
+		return err
+	}
+	if exists && c.OSCommand.FileType(to) != "directory" {
+		return errors.New(c.Tr.SLocalize("MoveTargetExists"))
+	}
+
+	return c.OSCommand.RunCommand(fmt.Sprintf("git mv %s %s", c.OSCommand.Quote(from), c.OSCommand.Quote(to)))
+}
+
 // Ignore adds a file to the gitignore for the repo
 func (c *GitCommand) Ignore(filename string) error {
 	return c.OSCommand.AppendLineToFile(".gitignore", filename)
 }
 
+// GetTags returns the repo's tags, most recently created first
+func (c *GitCommand) GetTags() ([]*Tag, error) {
+	output, err := c.OSCommand.RunCommandWithOutput("git tag --list --sort=-creatordate --format='%(refname:short)%09%(objecttype)%09%(contents:subject)'")
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []*Tag{}
+	for _, line := range utils.SplitLines(output) {
+		if tag := tagFromLine(line); tag != nil {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// tagFromLine parses a line like 'v1.0\ttag\tfirst release'. Only annotated
+// tags are objects of type 'tag'; for lightweight tags the subject is that of
+// the tagged commit, so we leave it out
+func tagFromLine(line string) *Tag {
+	fields := strings.SplitN(line, "\t", 3)
+	if fields[0] == "" {
+		return nil
+	}
+	tag := &Tag{Name: fields[0]}
+	if len(fields) == 3 && fields[1] == "tag" {
+		tag.Message = fields[2]
+	}
+	return tag
+}
+
+// CreateLightweightTag tags the given commit
+func (c *GitCommand) CreateLightweightTag(name, sha string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git tag %s %s", c.OSCommand.Quote(name), sha))
+}
+
+// CreateAnnotatedTag tags HEAD with the given message
+func (c *GitCommand) CreateAnnotatedTag(name, message string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git tag -a %s -m %s", c.OSCommand.Quote(name), c.OSCommand.Quote(message)))
+}
+
+// DeleteTag deletes a local tag
+func (c *GitCommand) DeleteTag(name string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git tag -d %s", c.OSCommand.Quote(name)))
+}
+
+// CheckoutTag checks out the given tag, leaving us with a detached HEAD
+func (c *GitCommand) CheckoutTag(tagName string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git checkout %s", c.OSCommand.Quote(tagName)))
+}
+
+// ShowTag shows the given tag. For annotated tags git prints the tag message
+// followed by the tagged commit
+func (c *GitCommand) ShowTag(tagName string) (string, error) {
+	return c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git show --color %s", c.OSCommand.Quote(tagName)))
+}
+
+// DiffTags returns the diff between two tags
+func (c *GitCommand) DiffTags(tag1, tag2 string) (string, error) {
+	return c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git diff --color %s%s", c.diffAlgorithmArg(), c.OSCommand.Quote(tag1+".."+tag2)))
+}
+
+// GetCommitsBetweenTags returns the commits reachable from tag2 but not from
+// tag1, newest first
+func (c *GitCommand) GetCommitsBetweenTags(tag1, tag2 string) ([]*Commit, error) {
+	log, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git log --oneline %s", c.OSCommand.Quote(tag1+".."+tag2)))
+	if err != nil {
+		return nil, err
+	}
+
+	return commitsFromOnelineLog(log), nil
+}
+
+type commitStat struct {
+	files     int
+	additions int
+	deletions int
+}
+
+// GetCommitStat returns the number of files changed, insertions and deletions
+// of a commit. Merge commits and commits without changes have no shortstat so
+// we return zeroes for those. A commit's stat never changes so we cache it,
+// which means sha has to be an actual sha rather than e.g. 'HEAD'
+func (c *GitCommand) GetCommitStat(sha string) (int, int, int, error) {
+	c.commitStatsMutex.Lock()
+	defer c.commitStatsMutex.Unlock()
+
+	if stat, ok := c.commitStats[sha]; ok {
+		return stat.files, stat.additions, stat.deletions, nil
+	}
+
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git show --shortstat --format= %s", sha))
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	files, additions, deletions := parseShortstat(output)
+	if c.commitStats == nil {
+		c.commitStats = map[string]commitStat{}
+	}
+	c.commitStats[sha] = commitStat{files: files, additions: additions, deletions: deletions}
+	return files, additions, deletions, nil
+}
+
+var shortstatRegexps = []*regexp.Regexp{
+	regexp.MustCompile(`(\d+) files? changed`),
+	regexp.MustCompile(`(\d+) insertions?\(\+\)`),
+	regexp.MustCompile(`(\d+) deletions?\(-\)`),
+}
+
+// parseShortstat parses a line like
+// ' 3 files changed, 40 insertions(+), 12 deletions(-)'. git leaves out the
+// insertions or deletions part when there are none
+func parseShortstat(output string) (int, int, int) {
+	counts := make([]int, len(shortstatRegexps))
+	for i, re := range shortstatRegexps {
+		match := re.FindStringSubmatch(output)
+		if match == nil {
+			continue
+		}
+		counts[i], _ = strconv.Atoi(match[1])
+	}
+	return counts[0], counts[1], counts[2]
+}
+
+// GetParentCount tells us how many parents a commit has, so that merge
+// commits can be told apart from the rest
+func (c *GitCommand) GetParentCount(sha string) (int, error) {
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git rev-list --parents -n 1 %s", sha))
+	if err != nil {
+		return 0, err
+	}
+	// the commit's own sha comes first, followed by its parents
+	return len(strings.Fields(output)) - 1, nil
+}
+
+// ShowMergeAgainstParent shows what a merge commit changed relative to one of
+// its parents, where parentNum is 1 for the branch that was merged into and 2
+// for the branch that was merged in
+func (c *GitCommand) ShowMergeAgainstParent(sha string, parentNum int) (string, error) {
+	if parentNum < 1 {
+		return "", errors.New(c.Tr.SLocalize("InvalidParentNumber"))
+	}
+	return c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git diff --color %s^%d %s", sha, parentNum, sha))
+}
+
+// ShowWithSignature shows a commit along with the output of verifying its
+// signature. Unsigned commits are shown without a signature block
+func (c *GitCommand) ShowWithSignature(sha string) (string, error) {
+	return c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git show --show-signature --color %s", sha))
+}
+
 // Show shows the diff of a commit
 func (c *GitCommand) Show(sha string) (string, error) {
-	show, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git show --color --no-renames %s", sha))
+	show, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git show --color --no-renames %s%s%s", c.diffAlgorithmArg(), c.submoduleArg(), sha))
 	if err != nil {
 		return "", err
 	}
@@ -570,71 +1698,407 @@ func (c *GitCommand) Show(sha string) (string, error) {
 		return show, nil
 	}
 
-	mergeDiff, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git diff --color %s...%s", secondLineWords[1], secondLineWords[2]))
+	mergeDiff, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git diff --color %s%s%s...%s", c.diffAlgorithmArg(), c.submoduleArg(), secondLineWords[1], secondLineWords[2]))
 	if err != nil {
 		return "", err
 	}
 	return show + mergeDiff, nil
 }
 
-// GetRemoteURL returns current repo remote url
-func (c *GitCommand) GetRemoteURL() string {
-	url, _ := c.OSCommand.RunCommandWithOutput("git config --get remote.origin.url")
+// submoduleArg returns e.g. '--submodule=log ' so that diffs show the commits a
+// submodule was moved through rather than just the change of sha. It's empty
+// when git's default format is configured
+func (c *GitCommand) submoduleArg() string {
+	format := c.Config.GetUserConfig().GetString("git.submoduleDiffFormat")
+	if format == "" || format == "short" {
+		return ""
+	}
+	return fmt.Sprintf("--submodule=%s ", format)
+}
+
+// diffAlgorithmArg returns e.g. '--diff-algorithm=patience ' for the configured
+// git.diffAlgorithm. It's empty for myers, git's default
+func (c *GitCommand) diffAlgorithmArg() string {
+	algorithm := c.Config.GetUserConfig().GetString("git.diffAlgorithm")
+	if algorithm == "" || algorithm == "myers" {
+		return ""
+	}
+	return fmt.Sprintf("--diff-algorithm=%s ", algorithm)
+}
+
+// ShowFileAtRevision returns the contents of a file as it was at the given
+// revision. The file name is expected to be relative to the repo root
+func (c *GitCommand) ShowFileAtRevision(rev, fileName string) (string, error) {
+	return c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git show %s", c.OSCommand.Quote(rev+":"+fileName)))
+}
+
+// Blame returns the blame output for a file. If a revision is given, we blame
+// the file as it was at that revision, otherwise as it is in the working tree
+func (c *GitCommand) Blame(fileName string, rev string) (string, error) {
+	revArg := ""
+	if rev != "" {
+		revArg = c.OSCommand.Quote(rev) + " "
+	}
+
+	return c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git blame %s--date=short -- %s", revArg, c.OSCommand.Quote(fileName)))
+}
+
+// BlameLineShas takes the default output of git blame and returns the sha of
+// the commit each line comes from. Lines which haven't been committed yet get
+// an empty sha, given there's no commit to go to
+func BlameLineShas(output string) []string {
+	shas := []string{}
+	for _, line := range utils.SplitLines(output) {
+		fields := strings.Fields(line)
+		sha := ""
+		if len(fields) > 0 {
+			// boundary commits are prefixed with a caret
+			sha = strings.TrimPrefix(fields[0], "^")
+		}
+		if !graphLineShaRegexp.MatchString(sha) || strings.Trim(sha, "0") == "" {
+			sha = ""
+		}
+		shas = append(shas, sha)
+	}
+	return shas
+}
+
+// GetDirectoryLog returns the log of commits touching the given directory
+func (c *GitCommand) GetDirectoryLog(dir string, limit int) (string, error) {
+	return c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git log --color --oneline -%d -- %s", limit, c.OSCommand.Quote(dir)))
+}
+
+// GetShortlog returns the number of commits per author across all refs
+func (c *GitCommand) GetShortlog() (string, error) {
+	return c.OSCommand.RunCommandWithOutput("git shortlog -sn --all")
+}
+
+// GetCommitsNotIn returns the commits on HEAD that aren't on the base branch
+func (c *GitCommand) GetCommitsNotIn(baseBranch string) ([]*Commit, error) {
+	return c.getCommitsInRange(fmt.Sprintf("%s..HEAD", baseBranch))
+}
+
+// GetCommitsNotInHead returns the commits on the given branch that HEAD doesn't
+// have yet
+func (c *GitCommand) GetCommitsNotInHead(branchName string) ([]*Commit, error) {
+	return c.getCommitsInRange(fmt.Sprintf("HEAD..%s", branchName))
+}
+
+// GetIncomingCommits returns the commits on the upstream branch that we'd get
+// by pulling. This only knows about what was there as of the last fetch
+func (c *GitCommand) GetIncomingCommits() ([]*Commit, error) {
+	if err := c.OSCommand.RunCommand("git rev-parse --abbrev-ref --symbolic-full-name @{u}"); err != nil {
+		return nil, errors.New(c.Tr.SLocalize("NoUpstreamForBranch"))
+	}
+
+	return c.getCommitsInRange("HEAD..@{u}")
+}
+
+// OldestUnpushedCommit returns the oldest commit that we have but the branch
+// we push to doesn't, which before a force push is the point from which we'd be
+// rewriting history. It returns nil if there's no push target or nothing to push.
+// Note that git limits the commits before reversing them, so '-n 1' would give
+// us the newest commit instead; we take the first line ourselves
+func (c *GitCommand) OldestUnpushedCommit() (*Commit, error) {
+	if err := c.OSCommand.RunCommand("git rev-parse --abbrev-ref --symbolic-full-name @{push}"); err != nil {
+		return nil, nil
+	}
+
+	log, err := c.OSCommand.RunCommandWithOutput("git log @{push}..HEAD --reverse --oneline")
+	if err != nil {
+		return nil, err
+	}
+	commits := commitsFromOnelineLog(log)
+	if len(commits) == 0 {
+		return nil, nil
+	}
+	return commits[0], nil
+}
+
+func (c *GitCommand) getCommitsInRange(revisionRange string) ([]*Commit, error) {
+	log, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git log --oneline %s", revisionRange))
+	if err != nil {
+		return nil, err
+	}
+
+	return commitsFromOnelineLog(log), nil
+}
+
+func commitsFromOnelineLog(log string) []*Commit {
+	commits := []*Commit{}
+	for _, line := range utils.SplitLines(log) {
+		splitLine := strings.Split(line, " ")
+		commits = append(commits, &Commit{
+			Sha:           splitLine[0],
+			Name:          strings.Join(splitLine[1:], " "),
+			DisplayString: line,
+		})
+	}
+	return commits
+}
+
+// GetRemoteURL returns the url of the given remote
+func (c *GitCommand) GetRemoteURL(remote string) string {
+	url, _ := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git config --get remote.%s.url", remote))
 	return utils.TrimTrailingNewline(url)
 }
 
 // CheckRemoteBranchExists Returns remote branch
 func (c *GitCommand) CheckRemoteBranchExists(branch *Branch) bool {
+	remote, remoteBranch, _ := c.GetUpstreamRemoteAndBranch(branch.Name)
+	if remote == "." {
+		return false
+	}
 	_, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf(
-		"git show-ref --verify -- refs/remotes/origin/%s",
-		branch.Name,
+		"git show-ref --verify -- refs/remotes/%s/%s",
+		remote,
+		remoteBranch,
 	))
 
-	return err == nil
+	return err == nil
+}
+
+// Diff returns the diff of a file
+func (c *GitCommand) Diff(file *File, plain bool, cached bool) string {
+	cachedArg := ""
+	trackedArg := "--"
+	colorArg := "--color"
+	split := strings.Split(file.Name, " -> ") // in case of a renamed file we get the new filename
+	fileName := c.OSCommand.Quote(split[len(split)-1])
+	if cached {
+		cachedArg = "--cached"
+	}
+	if !file.Tracked && !file.HasStagedChanges {
+		trackedArg = "--no-index /dev/null"
+	}
+	if plain {
+		colorArg = ""
+	}
+
+	command := fmt.Sprintf("git diff %s %s %s%s %s", colorArg, cachedArg, c.diffAlgorithmArg(), trackedArg, fileName)
+
+	// for now we assume an error means the file was deleted
+	s, _ := c.OSCommand.RunCommandWithOutput(command)
+	return s
+}
+
+// IsBinaryDiff tells us whether a diff is of a binary file, in which case git
+// doesn't show any lines we could stage individually
+func IsBinaryDiff(diff string) bool {
+	for _, line := range utils.SplitLines(utils.Decolorise(diff)) {
+		if strings.HasPrefix(line, "Binary files ") && strings.HasSuffix(line, " differ") {
+			return true
+		}
+	}
+	return false
+}
+
+// HunkStagedState reports whether the changes within the given range of lines
+// of the working tree file are already staged. Space will unstage the range if
+// this returns true and stage it otherwise.
+func (c *GitCommand) HunkStagedState(file *File, firstLine, lastLine int) (bool, error) {
+	if firstLine > lastLine {
+		return false, errors.New("first line must not come after last line")
+	}
+
+	unstagedDiff := c.Diff(file, true, false)
+	stagedDiff := c.Diff(file, true, true)
+
+	return hunkStagedState(stagedDiff, unstagedDiff, firstLine, lastLine), nil
+}
+
+var hunkRangeRegexp = regexp.MustCompile(`(?m)^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// hunkStagedState takes line numbers of the working tree file. Any unstaged
+// change in the range means the range is not staged. Otherwise we shift the
+// range by the lines the unstaged changes above it add or remove, giving us
+// line numbers in the index, and check whether the staged diff touches those
+// lines.
+func hunkStagedState(stagedDiff, unstagedDiff string, firstLine, lastLine int) bool {
+	if diffChangesRange(unstagedDiff, firstLine, lastLine) {
+		return false
+	}
+
+	// we can't go by the hunk headers here because a hunk's context lines may
+	// reach into the range even when all of its changes are above it
+	offset := 0
+	walkDiffLines(unstagedDiff, func(lineIdx int, firstChar byte, lineNumber int) bool {
+		if lineNumber >= firstLine {
+			return false
+		}
+		switch firstChar {
+		case '+':
+			offset++
+		case '-':
+			offset--
+		}
+		return true
+	})
+
+	return diffChangesRange(stagedDiff, firstLine-offset, lastLine-offset)
+}
+
+// diffChangesRange tells us whether any added or removed lines of the diff fall
+// within the given range of line numbers on the new side of the diff. A removed
+// line counts against the line that now takes its place.
+func diffChangesRange(diff string, firstLine, lastLine int) bool {
+	changed := false
+	walkDiffLines(diff, func(lineIdx int, firstChar byte, lineNumber int) bool {
+		if firstChar != ' ' && lineNumber >= firstLine && lineNumber <= lastLine {
+			changed = true
+			return false
+		}
+		return true
+	})
+	return changed
+}
+
+// DiffLineNumber returns the line number on the new side of the diff of the
+// line at the given index of the diff, or -1 if the index isn't within a hunk.
+// A removed line gets the number of the line that now takes its place.
+func DiffLineNumber(diff string, lineIdx int) int {
+	result := -1
+	walkDiffLines(diff, func(idx int, firstChar byte, lineNumber int) bool {
+		if idx == lineIdx {
+			result = lineNumber
+			return false
+		}
+		return idx < lineIdx
+	})
+	return result
+}
+
+// walkDiffLines calls f with each added, removed or context line of the diff
+// along with its line number on the new side of the diff, until f returns false
+func walkDiffLines(diff string, f func(lineIdx int, firstChar byte, lineNumber int) bool) {
+	lineNumber := 0
+	inHunk := false
+	for lineIdx, line := range strings.Split(diff, "\n") {
+		if match := hunkRangeRegexp.FindStringSubmatch(line); match != nil {
+			lineNumber, _ = strconv.Atoi(match[3])
+			// for a hunk that only removes lines git gives the line before them
+			if hunkRangeLength(match[4]) == 0 {
+				lineNumber++
+			}
+			inHunk = true
+			continue
+		}
+		if !inHunk || line == "" {
+			continue
+		}
+		firstChar := line[0]
+		if firstChar != '+' && firstChar != '-' && firstChar != ' ' {
+			continue
+		}
+		if !f(lineIdx, firstChar, lineNumber) {
+			return
+		}
+		if firstChar != '-' {
+			lineNumber++
+		}
+	}
+}
+
+// hunkRangeLength parses the optional length of a hunk header range, which
+// git omits when it is 1
+func hunkRangeLength(length string) int {
+	if length == "" {
+		return 1
+	}
+	result, _ := strconv.Atoi(length)
+	return result
+}
+
+func (c *GitCommand) ApplyPatch(patch string, flags ...string) error {
+	c.Log.Warn(patch)
+	filepath := filepath.Join(c.Config.GetUserConfigDir(), utils.GetCurrentRepoName(), time.Now().Format(time.StampNano)+".patch")
+	if err := c.OSCommand.CreateFileWithContent(filepath, patch); err != nil {
+		return err
+	}
+
+	flagStr := ""
+	for _, flag := range flags {
+		flagStr += " --" + flag
+	}
+
+	return c.OSCommand.RunCommand(fmt.Sprintf("git apply %s %s", flagStr, c.OSCommand.Quote(filepath)))
+}
+
+// UnstageHunk unstages the hunk at the given index of the file's staged diff
+// by applying that hunk to the index in reverse
+func (c *GitCommand) UnstageHunk(file *File, hunkIndex int) error {
+	diff := c.Diff(file, true, true)
+	hunks := GetHunksFromDiff(diff)
+	if hunkIndex < 0 || hunkIndex >= len(hunks) {
+		return errors.New(c.Tr.SLocalize("NoSuchHunk"))
+	}
+
+	split := strings.Split(file.Name, " -> ") // in case of a renamed file we want the new filename
+	patch := hunks[hunkIndex].patch(split[len(split)-1])
+
+	return c.ApplyPatch(patch, "cached", "reverse")
+}
+
+// ApplyMailboxPatch applies a patch made by git format-patch as a commit,
+// keeping the author and message from the patch
+func (c *GitCommand) ApplyMailboxPatch(path string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git am %s", c.OSCommand.Quote(path)))
 }
 
-// Diff returns the diff of a file
-func (c *GitCommand) Diff(file *File, plain bool, cached bool) string {
+// ApplyPatchFile applies a patch file from disk, to the index if cached is true
+// or otherwise to the working tree. We check that the patch applies cleanly
+// first so that the user can see why it doesn't
+func (c *GitCommand) ApplyPatchFile(path string, cached bool) error {
 	cachedArg := ""
-	trackedArg := "--"
-	colorArg := "--color"
-	split := strings.Split(file.Name, " -> ") // in case of a renamed file we get the new filename
-	fileName := c.OSCommand.Quote(split[len(split)-1])
 	if cached {
-		cachedArg = "--cached"
+		cachedArg = "--cached "
 	}
-	if !file.Tracked && !file.HasStagedChanges {
-		trackedArg = "--no-index /dev/null"
-	}
-	if plain {
-		colorArg = ""
+	quotedPath := c.OSCommand.Quote(path)
+
+	if err := c.OSCommand.RunCommand(fmt.Sprintf("git apply --check %s%s", cachedArg, quotedPath)); err != nil {
+		return err
 	}
 
-	command := fmt.Sprintf("git diff %s %s %s %s", colorArg, cachedArg, trackedArg, fileName)
+	return c.OSCommand.RunCommand(fmt.Sprintf("git apply %s%s", cachedArg, quotedPath))
+}
+
+// EditHunk writes a hunk patch to a temp file for the user to edit, returning
+// the path of that file along with a subprocess which opens it in their editor
+func (c *GitCommand) EditHunk(hunkPatch string) (string, *exec.Cmd, error) {
+	path, err := c.OSCommand.CreateTempFile("lazygit-hunk.patch", hunkPatch+c.Tr.SLocalize("EditHunkInstructions"))
+	if err != nil {
+		return "", nil, err
+	}
 
-	// for now we assume an error means the file was deleted
-	s, _ := c.OSCommand.RunCommandWithOutput(command)
-	return s
+	cmd, err := c.OSCommand.EditFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+	return path, cmd, nil
 }
 
-func (c *GitCommand) ApplyPatch(patch string, flags ...string) error {
-	c.Log.Warn(patch)
-	filepath := filepath.Join(c.Config.GetUserConfigDir(), utils.GetCurrentRepoName(), time.Now().Format(time.StampNano)+".patch")
-	if err := c.OSCommand.CreateFileWithContent(filepath, patch); err != nil {
+// ApplyEditedHunk stages the hunk patch at the given path once the user has
+// finished editing it. If they've deleted the hunk entirely we do nothing
+func (c *GitCommand) ApplyEditedHunk(path string) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
 		return err
 	}
+	defer c.OSCommand.Remove(path)
 
-	flagStr := ""
-	for _, flag := range flags {
-		flagStr += " --" + flag
+	patch := RecountHunkHeaders(string(content))
+	if !hunkHeaderRegexp.MatchString(patch) {
+		return nil
 	}
 
-	return c.OSCommand.RunCommand(fmt.Sprintf("git apply %s %s", flagStr, c.OSCommand.Quote(filepath)))
+	return c.ApplyPatch(patch, "cached")
 }
 
+// FastForward updates a branch other than the current one from the branch it
+// tracks, without having to check it out
 func (c *GitCommand) FastForward(branchName string) error {
-	upstream := "origin" // hardcoding for now
-	return c.OSCommand.RunCommand(fmt.Sprintf("git fetch %s %s:%s", upstream, branchName, branchName))
+	remote, remoteBranch, _ := c.GetUpstreamRemoteAndBranch(branchName)
+	return c.OSCommand.RunCommand(fmt.Sprintf("git fetch %s %s:%s", remote, remoteBranch, branchName))
 }
 
 func (c *GitCommand) RunSkipEditorCommand(command string) error {
@@ -705,6 +2169,122 @@ func (c *GitCommand) MoveCommitDown(commits []*Commit, index int) error {
 	return c.OSCommand.RunPreparedCommand(cmd)
 }
 
+// MoveCommitToTop moves the commit at the given index above every other commit
+// in a single rebase, rather than moving it up one step at a time
+func (c *GitCommand) MoveCommitToTop(commits []*Commit, index int) error {
+	todo, sha, err := c.generateMoveCommitToTopTodo(commits, index)
+	if err != nil {
+		return err
+	}
+
+	return c.runMoveCommitRebase(sha, todo)
+}
+
+// MoveCommitToBottom moves the commit at the given index down to just above
+// the oldest commit we have, which stays in place as the base of the rebase
+func (c *GitCommand) MoveCommitToBottom(commits []*Commit, index int) error {
+	todo, sha, err := c.generateMoveCommitToBottomTodo(commits, index)
+	if err != nil {
+		return err
+	}
+
+	return c.runMoveCommitRebase(sha, todo)
+}
+
+func (c *GitCommand) runMoveCommitRebase(baseSha string, todo string) error {
+	cmd, err := c.PrepareInteractiveRebaseCommand(baseSha, todo, true)
+	if err != nil {
+		return err
+	}
+
+	return c.OSCommand.RunPreparedCommand(cmd)
+}
+
+func (c *GitCommand) generateMoveCommitToTopTodo(commits []*Commit, index int) (string, string, error) {
+	// the commit below the selected one is our base
+	if len(commits) <= index+1 {
+		return "", "", errors.New(c.Tr.SLocalize("CannotRebaseOntoFirstCommit"))
+	}
+
+	orderedCommits := []*Commit{commits[index]}
+	orderedCommits = append(orderedCommits, commits[0:index]...)
+
+	return generatePickTodo(orderedCommits), commits[index+1].Sha, nil
+}
+
+func (c *GitCommand) generateMoveCommitToBottomTodo(commits []*Commit, index int) (string, string, error) {
+	// we need the selected commit plus at least one commit below it to act as the base
+	if len(commits) <= index+1 {
+		return "", "", errors.New(c.Tr.SLocalize("NoRoom"))
+	}
+
+	baseIndex := len(commits) - 1
+	orderedCommits := []*Commit{}
+	orderedCommits = append(orderedCommits, commits[0:index]...)
+	orderedCommits = append(orderedCommits, commits[index+1:baseIndex]...)
+	orderedCommits = append(orderedCommits, commits[index])
+
+	return generatePickTodo(orderedCommits), commits[baseIndex].Sha, nil
+}
+
+// generatePickTodo takes commits ordered newest first and returns a todo
+// picking them oldest first, as git expects
+func generatePickTodo(commits []*Commit) string {
+	todo := ""
+	for _, commit := range commits {
+		todo = "pick " + commit.Sha + " " + commit.Name + "\n" + todo
+	}
+	return todo
+}
+
+// SquashMarkedCommits squashes all the marked commits into the oldest marked
+// one, moving the others down to sit on top of it first
+func (c *GitCommand) SquashMarkedCommits(commits []*Commit, markedIndices []int) error {
+	todo, sha, err := c.generateSquashMarkedTodo(commits, markedIndices)
+	if err != nil {
+		return err
+	}
+
+	return c.runMoveCommitRebase(sha, todo)
+}
+
+func (c *GitCommand) generateSquashMarkedTodo(commits []*Commit, markedIndices []int) (string, string, error) {
+	if len(markedIndices) < 2 {
+		return "", "", errors.New(c.Tr.SLocalize("MarkAtLeastTwoCommits"))
+	}
+
+	marked := map[int]bool{}
+	baseIndex := 0
+	for _, index := range markedIndices {
+		marked[index] = true
+		if index+1 > baseIndex {
+			baseIndex = index + 1
+		}
+	}
+	if len(commits) <= baseIndex {
+		return "", "", errors.New(c.Tr.SLocalize("CannotRebaseOntoFirstCommit"))
+	}
+
+	// we walk from the oldest commit to the newest, so the first marked commit
+	// we see is the one everything gets squashed into
+	todo := ""
+	squashes := ""
+	rest := ""
+	for i := baseIndex - 1; i >= 0; i-- {
+		commit := commits[i]
+		switch {
+		case !marked[i]:
+			rest += "pick " + commit.Sha + " " + commit.Name + "\n"
+		case todo == "":
+			todo = "pick " + commit.Sha + " " + commit.Name + "\n"
+		default:
+			squashes += "squash " + commit.Sha + " " + commit.Name + "\n"
+		}
+	}
+
+	return todo + squashes + rest, commits[baseIndex].Sha, nil
+}
+
 func (c *GitCommand) InteractiveRebase(commits []*Commit, index int, action string) error {
 	todo, sha, err := c.GenerateGenericRebaseTodo(commits, index, action)
 	if err != nil {
@@ -854,7 +2434,79 @@ func (c *GitCommand) MoveTodoDown(index int) error {
 
 // Revert reverts the selected commit by sha
 func (c *GitCommand) Revert(sha string) error {
-	return c.OSCommand.RunCommand(fmt.Sprintf("git revert %s", sha))
+	return c.OSCommand.RunCommand(fmt.Sprintf("git revert --no-edit %s", sha))
+}
+
+// RevertRange reverts each commit from startSha up to and including endSha,
+// newest first, with one revert commit for each
+func (c *GitCommand) RevertRange(startSha, endSha string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git revert --no-edit %s^..%s", startSha, endSha))
+}
+
+// GetHeadSha returns the full sha of HEAD
+func (c *GitCommand) GetHeadSha() (string, error) {
+	output, err := c.OSCommand.RunCommandWithOutput("git rev-parse HEAD")
+	return strings.TrimSpace(output), err
+}
+
+// RevertHead undoes the last commit by adding a commit that reverts it, which
+// unlike a reset is safe to do once the commit has been pushed
+func (c *GitCommand) RevertHead() error {
+	sha, err := c.GetHeadSha()
+	if err != nil {
+		return err
+	}
+	return c.Revert(sha)
+}
+
+// GetHooks returns the hooks installed in the repo, skipping the .sample files
+// git creates for reference
+func (c *GitCommand) GetHooks() ([]*Hook, error) {
+	hooks := []*Hook{}
+	hooksDir := filepath.Join(c.DotGitDir, "hooks")
+	exists, err := c.OSCommand.FileExists(hooksDir)
+	if err != nil || !exists {
+		return hooks, err
+	}
+
+	entries, err := ioutil.ReadDir(hooksDir)
+	if err != nil {
+		return nil, WrapError(err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), ".sample") {
+			continue
+		}
+		path := filepath.Join(hooksDir, entry.Name())
+		enabled, err := c.OSCommand.IsExecutable(path)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, &Hook{Name: entry.Name(), Path: path, Enabled: enabled})
+	}
+	return hooks, nil
+}
+
+// SetHookEnabled enables or disables a hook by toggling whether it's executable
+func (c *GitCommand) SetHookEnabled(hook *Hook, enabled bool) error {
+	return c.OSCommand.SetExecutable(hook.Path, enabled)
+}
+
+// BisectRun lets git find the first bad commit of the current bisect session
+// by running the given command against each candidate commit
+func (c *GitCommand) BisectRun(command string) (string, error) {
+	bisecting, err := c.BisectMode()
+	if err != nil {
+		return "", err
+	}
+	if !bisecting {
+		return "", errors.New(c.Tr.SLocalize("NotBisecting"))
+	}
+
+	platform := c.OSCommand.Platform
+	return c.OSCommand.RunCommandWithOutput(
+		fmt.Sprintf("git bisect run %s %s %s", platform.shell, platform.shellArg, c.OSCommand.Quote(command)),
+	)
 }
 
 // CherryPickCommits begins an interactive rebase with the given shas being cherry picked onto HEAD
@@ -872,8 +2524,48 @@ func (c *GitCommand) CherryPickCommits(commits []*Commit) error {
 	return c.OSCommand.RunPreparedCommand(cmd)
 }
 
+// CherryPick cherry-picks the given commits onto HEAD in the order given, so
+// they should be oldest first. Unlike CherryPickCommits this leaves HEAD's
+// history alone, and conflicts are resolved with CherryPickContinue/Abort
+func (c *GitCommand) CherryPick(shas []string) error {
+	return c.RunSkipEditorCommand(fmt.Sprintf("git cherry-pick %s", strings.Join(shas, " ")))
+}
+
+// CherryPickContinue continues a cherry-pick that stopped on a conflict
+func (c *GitCommand) CherryPickContinue() error {
+	return c.GenericMerge("cherry-pick", "continue")
+}
+
+// CherryPickAbort abandons a cherry-pick that stopped on a conflict, putting
+// HEAD back where it was before
+func (c *GitCommand) CherryPickAbort() error {
+	return c.GenericMerge("cherry-pick", "abort")
+}
+
+// CherryPickRange cherry-picks every commit from startSha to endSha inclusive,
+// hence the '^' on the start of the range. Unlike CherryPickCommits this is a
+// true cherry-pick, so conflicts are resolved with cherry-pick --continue/--abort
+func (c *GitCommand) CherryPickRange(startSha, endSha string) error {
+	return c.RunSkipEditorCommand(fmt.Sprintf("git cherry-pick %s^..%s", startSha, endSha))
+}
+
 // GetCommitFiles get the specified commit files
 func (c *GitCommand) GetCommitFiles(commitSha string, patchManager *PatchManager) ([]*CommitFile, error) {
+	if c.Config.GetUserConfig().GetBool("git.detectRenames") {
+		// with rename detection on we need the statuses to know which entries
+		// are renames
+		output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git show --pretty= --name-status %s", commitSha))
+		if err != nil {
+			return nil, err
+		}
+		commitFiles := parseDiffNameStatus(output)
+		for _, commitFile := range commitFiles {
+			commitFile.Sha = commitSha
+			commitFile.Status = getCommitFileStatus(commitSha, commitFile.Name, patchManager)
+		}
+		return commitFiles, nil
+	}
+
 	cmd := fmt.Sprintf("git show --pretty= --name-only --no-renames %s", commitSha)
 	files, err := c.OSCommand.RunCommandWithOutput(cmd)
 	if err != nil {
@@ -883,29 +2575,99 @@ func (c *GitCommand) GetCommitFiles(commitSha string, patchManager *PatchManager
 	commitFiles := make([]*CommitFile, 0)
 
 	for _, file := range strings.Split(strings.TrimRight(files, "\n"), "\n") {
-		status := UNSELECTED
-		if patchManager != nil && patchManager.CommitSha == commitSha {
-			status = patchManager.GetFileStatus(file)
-		}
-
 		commitFiles = append(commitFiles, &CommitFile{
 			Sha:           commitSha,
 			Name:          file,
 			DisplayString: file,
-			Status:        status,
+			Status:        getCommitFileStatus(commitSha, file, patchManager),
 		})
 	}
 
 	return commitFiles, nil
 }
 
-// ShowCommitFile get the diff of specified commit file
-func (c *GitCommand) ShowCommitFile(commitSha, fileName string, plain bool) (string, error) {
+func getCommitFileStatus(commitSha string, fileName string, patchManager *PatchManager) int {
+	if patchManager != nil && patchManager.CommitSha == commitSha {
+		return patchManager.GetFileStatus(fileName)
+	}
+	return UNSELECTED
+}
+
+// GetDiffFiles returns the files that differ between ref2 and the point at
+// which it diverged from ref1
+func (c *GitCommand) GetDiffFiles(ref1, ref2 string) ([]*CommitFile, error) {
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git diff --name-status %s...%s", ref1, ref2))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseDiffNameStatus(output), nil
+}
+
+// parseDiffNameStatus parses the output of `git diff --name-status`. Renames
+// and copies come through as e.g. "R100\told\tnew", in which case we take the
+// new name as the file name
+func parseDiffNameStatus(output string) []*CommitFile {
+	files := make([]*CommitFile, 0)
+
+	for _, line := range utils.SplitLines(output) {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 2 {
+			continue
+		}
+
+		changeStatus := fields[0][:1]
+		name := fields[len(fields)-1]
+		previousName := ""
+		displayString := fmt.Sprintf("%s %s", changeStatus, name)
+		if len(fields) > 2 {
+			previousName = fields[1]
+			displayString = fmt.Sprintf("%s %s -> %s", changeStatus, previousName, name)
+		}
+
+		files = append(files, &CommitFile{
+			Name:          name,
+			PreviousName:  previousName,
+			DisplayString: displayString,
+			ChangeStatus:  changeStatus,
+		})
+	}
+
+	return files
+}
+
+// ShowCommitFile get the diff of specified commit file. If the file was renamed
+// in the commit, previousName is its old name, which needs to be in the
+// pathspec alongside the new one for git to detect the rename
+func (c *GitCommand) ShowCommitFile(commitSha, fileName, previousName string, plain bool) (string, error) {
 	colorArg := "--color"
 	if plain {
 		colorArg = ""
 	}
-	cmd := fmt.Sprintf("git show --no-renames %s %s -- %s", colorArg, commitSha, fileName)
+	// the plain diff is used for building custom patches, which rely on renames
+	// being split into a deletion and an addition
+	renamesArg := "--no-renames"
+	pathspec := fileName
+	if !plain && c.Config.GetUserConfig().GetBool("git.detectRenames") {
+		renamesArg = ""
+		if previousName != "" {
+			pathspec = previousName + " " + fileName
+		}
+	}
+	// likewise the submodule format would stop the patch from applying
+	submoduleArg := ""
+	if !plain {
+		submoduleArg = c.submoduleArg()
+	}
+	cmd := fmt.Sprintf("git show %s %s %s%s%s -- %s", renamesArg, colorArg, c.diffAlgorithmArg(), submoduleArg, commitSha, pathspec)
+	return c.OSCommand.RunCommandWithOutput(cmd)
+}
+
+// DiffFileAgainstRevision returns a plain diff which takes the file in the
+// working tree to its content at the given commit (i.e. `git show sha:file`).
+// Applying some of its lines restores just those lines from the old version
+func (c *GitCommand) DiffFileAgainstRevision(commitSha, fileName string) (string, error) {
+	cmd := fmt.Sprintf("git diff --no-color --no-renames -R %s%s -- %s", c.diffAlgorithmArg(), commitSha, c.OSCommand.Quote(fileName))
 	return c.OSCommand.RunCommandWithOutput(cmd)
 }
 
@@ -921,15 +2683,76 @@ func (c *GitCommand) DiscardOldFileChanges(commits []*Commit, commitIndex int, f
 		return err
 	}
 
-	// check if file exists in previous commit (this command returns an error if the file doesn't exist)
-	if err := c.OSCommand.RunCommand(fmt.Sprintf("git cat-file -e HEAD^:%s", fileName)); err != nil {
+	return c.discardFileChangesSinceAndContinue("HEAD^", fileName)
+}
+
+// DiscardFileChangesInRange discards the changes to a file made in the commits
+// from startIndex to endIndex (inclusive, newest first) in a single rebase, by
+// restoring the file to how it was before the range in each of those commits
+func (c *GitCommand) DiscardFileChangesInRange(commits []*Commit, startIndex, endIndex int, fileName string) error {
+	todo, baseSha, err := c.generateEditRangeTodo(commits, startIndex, endIndex)
+	if err != nil {
+		return err
+	}
+
+	// as with BeginInteractiveRebaseForCommit, we'd need to handle the
+	// credential request for each amend to support this
+	if c.usingGpg() {
+		return errors.New(c.Tr.SLocalize("DisabledForGPG"))
+	}
+
+	cmd, err := c.PrepareInteractiveRebaseCommand(baseSha, todo, true)
+	if err != nil {
+		return err
+	}
+	if err := c.OSCommand.RunPreparedCommand(cmd); err != nil {
+		return err
+	}
+
+	// we stop once for each commit in the range
+	for i := startIndex; i <= endIndex; i++ {
+		if err := c.discardFileChangesSinceAndContinue(baseSha, fileName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateEditRangeTodo returns a todo which stops to edit each commit from
+// startIndex to endIndex, along with the sha to rebase onto
+func (c *GitCommand) generateEditRangeTodo(commits []*Commit, startIndex, endIndex int) (string, string, error) {
+	if startIndex < 0 || startIndex > endIndex || len(commits)-1 < endIndex {
+		return "", "", errors.New("index outside of range of commits")
+	}
+	if len(commits) <= endIndex+1 {
+		return "", "", errors.New(c.Tr.SLocalize("CannotRebaseOntoFirstCommit"))
+	}
+
+	todo := ""
+	for i, commit := range commits[0 : endIndex+1] {
+		action := "pick"
+		if i >= startIndex {
+			action = "edit"
+		}
+		todo = action + " " + commit.Sha + " " + commit.Name + "\n" + todo
+	}
+
+	return todo, commits[endIndex+1].Sha, nil
+}
+
+// discardFileChangesSinceAndContinue is for when we've stopped to edit a commit
+// mid-rebase: it restores the file to how it was at the given ref, amends the
+// commit and carries on
+func (c *GitCommand) discardFileChangesSinceAndContinue(ref string, fileName string) error {
+	// check if file exists at the ref (this command returns an error if the file doesn't exist)
+	if err := c.OSCommand.RunCommand(fmt.Sprintf("git cat-file -e %s:%s", ref, fileName)); err != nil {
 		if err := c.OSCommand.Remove(fileName); err != nil {
 			return err
 		}
 		if err := c.StageFile(fileName); err != nil {
 			return err
 		}
-	} else if err := c.CheckoutFile("HEAD^", fileName); err != nil {
+	} else if err := c.CheckoutFile(ref, fileName); err != nil {
 		return err
 	}
 
@@ -978,7 +2801,28 @@ func (c *GitCommand) CreateFixupCommit(sha string) error {
 	return c.OSCommand.RunCommand(cmd)
 }
 
-// SquashAllAboveFixupCommits squashes all fixup! commits above the given one
+// CreateSquashCommit creates a squash! commit for a previous commit. Unlike a
+// fixup! commit, its message is kept alongside the target's when autosquashing.
+// git would open an editor for the message here, but the 'squash!' subject it
+// starts with is all we need
+func (c *GitCommand) CreateSquashCommit(sha string) error {
+	return c.RunSkipEditorCommand(fmt.Sprintf("git commit --squash=%s", sha))
+}
+
+// PrepareFixupCommit returns the diff of the commit that a fixup would target
+// so it can be previewed, along with a function that actually creates the
+// fixup commit once the user has confirmed it's the right one
+func (c *GitCommand) PrepareFixupCommit(sha string) (string, func() error, error) {
+	preview, err := c.Show(sha)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return preview, func() error { return c.CreateFixupCommit(sha) }, nil
+}
+
+// SquashAllAboveFixupCommits squashes all fixup! and squash! commits above the
+// given one
 func (c *GitCommand) SquashAllAboveFixupCommits(sha string) error {
 	return c.RunSkipEditorCommand(
 		fmt.Sprintf(
@@ -988,6 +2832,158 @@ func (c *GitCommand) SquashAllAboveFixupCommits(sha string) error {
 	)
 }
 
+// AutosquashRange is like SquashAllAboveFixupCommits except that only fixup!
+// and squash! commits from baseSha up to and including topSha are squashed.
+// We can't ask git to stop autosquashing partway through, so we generate the
+// todo ourselves and leave everything above topSha untouched
+func (c *GitCommand) AutosquashRange(baseSha, topSha string) error {
+	commits, err := c.getCommitsSince(baseSha)
+	if err != nil {
+		return err
+	}
+
+	todo, err := c.generateAutosquashRangeTodo(commits, topSha)
+	if err != nil {
+		return err
+	}
+
+	cmd, err := c.PrepareInteractiveRebaseCommand(baseSha, todo, true)
+	if err != nil {
+		return err
+	}
+
+	return c.OSCommand.RunPreparedCommand(cmd)
+}
+
+// getCommitsSince returns the commits between baseSha and HEAD, oldest first,
+// the way they'd appear in a rebase todo
+func (c *GitCommand) getCommitsSince(baseSha string) ([]*Commit, error) {
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git log --reverse --format=\"%%H %%s\" %s..HEAD", baseSha))
+	if err != nil {
+		return nil, err
+	}
+
+	commits := []*Commit{}
+	for _, line := range utils.SplitLines(output) {
+		split := strings.SplitN(line, " ", 2)
+		if len(split) < 2 {
+			split = append(split, "")
+		}
+		commits = append(commits, &Commit{Sha: split[0], Name: split[1]})
+	}
+	return commits, nil
+}
+
+// RebaseWithExec rebases every commit after baseSha, running the given shell
+// command after each one like `git rebase --exec` would. If the command fails
+// the rebase stops at that commit
+func (c *GitCommand) RebaseWithExec(baseSha, command string) error {
+	commits, err := c.getCommitsSince(baseSha)
+	if err != nil {
+		return err
+	}
+
+	return c.runMoveCommitRebase(baseSha, generateExecTodo(commits, command))
+}
+
+// generateExecTodo takes commits ordered oldest first and adds an exec line
+// after each pick
+func generateExecTodo(commits []*Commit, command string) string {
+	execLine := "exec " + execTodoCommand(command) + "\n"
+
+	todo := ""
+	for _, commit := range commits {
+		todo += "pick " + commit.Sha + " " + commit.Name + "\n" + execLine
+	}
+	return todo
+}
+
+// execTodoCommand fits a command onto a single todo line. A todo entry can't
+// contain newlines, so a multi-line command has them written as '\n' escapes
+// which printf turns back into newlines before the shell runs it, meaning
+// things like if blocks and heredocs still work. Backslashes are escaped so
+// that printf leaves them as they are, and single quotes are closed and
+// reopened around an escaped quote
+func execTodoCommand(command string) string {
+	command = strings.TrimRight(command, "\n")
+	if !strings.Contains(command, "\n") {
+		return command
+	}
+
+	escaped := strings.NewReplacer(
+		`\`, `\\`,
+		"\n", `\n`,
+		"'", `'\''`,
+	).Replace(command)
+	return fmt.Sprintf(`sh -c "$(printf '%%b' '%s')"`, escaped)
+}
+
+// generateAutosquashRangeTodo takes commits ordered oldest first and moves each
+// fixup!/squash! commit up to topSha to just after the commit it targets, the
+// same way `git rebase --autosquash` would
+func (c *GitCommand) generateAutosquashRangeTodo(commits []*Commit, topSha string) (string, error) {
+	topIndex := -1
+	for i, commit := range commits {
+		if strings.HasPrefix(commit.Sha, topSha) {
+			topIndex = i
+			break
+		}
+	}
+	if topIndex == -1 {
+		return "", errors.New(c.Tr.SLocalize("CommitNotInRange"))
+	}
+
+	type todoEntry struct {
+		commit    *Commit
+		followers []string
+	}
+	entries := []*todoEntry{}
+
+outer:
+	for _, commit := range commits[0 : topIndex+1] {
+		action, subject := autosquashAction(commit.Name)
+		if action != "" {
+			for _, entry := range entries {
+				if entry.commit.Name == subject || strings.HasPrefix(entry.commit.Sha, subject) {
+					entry.followers = append(entry.followers, action+" "+commit.Sha+" "+commit.Name)
+					continue outer
+				}
+			}
+		}
+		entries = append(entries, &todoEntry{commit: commit})
+	}
+
+	todo := ""
+	for _, entry := range entries {
+		todo += "pick " + entry.commit.Sha + " " + entry.commit.Name + "\n"
+		for _, follower := range entry.followers {
+			todo += follower + "\n"
+		}
+	}
+	for _, commit := range commits[topIndex+1:] {
+		todo += "pick " + commit.Sha + " " + commit.Name + "\n"
+	}
+
+	return todo, nil
+}
+
+// autosquashAction returns 'fixup' or 'squash' along with the subject being
+// targeted if the commit name has a fixup!/squash! prefix
+func autosquashAction(name string) (string, string) {
+	for _, action := range []string{"fixup", "squash"} {
+		prefix := action + "! "
+		if strings.HasPrefix(name, prefix) {
+			subject := name
+			// a fixup of a fixup targets the original commit
+			for strings.HasPrefix(subject, "fixup! ") || strings.HasPrefix(subject, "squash! ") {
+				subject = subject[strings.Index(subject, " ")+1:]
+			}
+			return action, subject
+		}
+	}
+	return "", ""
+}
+
 // StashSaveStagedChanges stashes only the currently staged changes. This takes a few steps
 // shoutouts to Joe on https://stackoverflow.com/questions/14759748/stashing-only-staged-changes-in-git-is-it-possible
 func (c *GitCommand) StashSaveStagedChanges(message string) error {
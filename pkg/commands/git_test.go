@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -294,11 +295,13 @@ func TestGitCommandGetStashEntries(t *testing.T) {
 						0,
 						"WIP on add-pkg-commands-test: 55c6af2 increase parallel build",
 						"WIP on add-pkg-commands-test: 55c6af2 increase parallel build",
+						"add-pkg-commands-test",
 					},
 					{
 						1,
 						"WIP on master: bb86a3f update github template",
 						"WIP on master: bb86a3f update github template",
+						"master",
 					},
 				}
 
@@ -318,6 +321,44 @@ func TestGitCommandGetStashEntries(t *testing.T) {
 	}
 }
 
+// TestStashBranch is a function.
+func TestStashBranch(t *testing.T) {
+	type scenario struct {
+		testName string
+		message  string
+		expected string
+	}
+
+	scenarios := []scenario{
+		{
+			"stash without a message",
+			"WIP on master: bb86a3f update github template",
+			"master",
+		},
+		{
+			"stash with a message",
+			"On feature/my-branch: my stash message",
+			"feature/my-branch",
+		},
+		{
+			"message containing a colon",
+			"On master: fix: the thing",
+			"master",
+		},
+		{
+			"unrecognised message",
+			"autostash",
+			"",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, stashBranch(s.message))
+		})
+	}
+}
+
 // TestGitCommandGetStashEntryDiff is a function.
 func TestGitCommandGetStashEntryDiff(t *testing.T) {
 	gitCmd := NewDummyGitCommand()
@@ -333,6 +374,36 @@ func TestGitCommandGetStashEntryDiff(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+// TestGitCommandGetStashStat is a function.
+func TestGitCommandGetStashStat(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"stash", "show", "--stat", "stash@{2}"}, args)
+
+		return exec.Command("echo")
+	}
+
+	_, err := gitCmd.GetStashStat(2)
+
+	assert.NoError(t, err)
+}
+
+// TestGitCommandDiffStashAgainstWorkingTree is a function.
+func TestGitCommandDiffStashAgainstWorkingTree(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"diff", "--color", "stash@{2}"}, args)
+
+		return exec.Command("echo")
+	}
+
+	_, err := gitCmd.DiffStashAgainstWorkingTree(2)
+
+	assert.NoError(t, err)
+}
+
 // TestGitCommandGetStatusFiles is a function.
 func TestGitCommandGetStatusFiles(t *testing.T) {
 	type scenario struct {
@@ -466,6 +537,42 @@ func TestGitCommandStashSave(t *testing.T) {
 	assert.NoError(t, gitCmd.StashSave("A stash message"))
 }
 
+// TestGitCommandStashSaveWithUntracked is a function.
+func TestGitCommandStashSaveWithUntracked(t *testing.T) {
+	type scenario struct {
+		testName     string
+		message      string
+		expectedArgs []string
+	}
+
+	scenarios := []scenario{
+		{
+			"with a message",
+			"my stash",
+			[]string{"stash", "save", "--include-untracked", "my stash"},
+		},
+		{
+			"without a message",
+			"",
+			[]string{"stash", "-u"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, s.expectedArgs, args)
+
+				return exec.Command("echo")
+			}
+
+			assert.NoError(t, gitCmd.StashSaveWithUntracked(s.message))
+		})
+	}
+}
+
 // TestGitCommandCommitAmend is a function.
 func TestGitCommandCommitAmend(t *testing.T) {
 	gitCmd := NewDummyGitCommand()
@@ -619,6 +726,196 @@ func TestGitCommandGetCommitDifferences(t *testing.T) {
 	}
 }
 
+// TestGitCommandGetUpstreamRemoteAndBranch is a function.
+func TestGitCommandGetUpstreamRemoteAndBranch(t *testing.T) {
+	type scenario struct {
+		testName             string
+		remote               *exec.Cmd
+		merge                *exec.Cmd
+		expectedRemote       string
+		expectedRemoteBranch string
+		expectedOk           bool
+	}
+
+	scenarios := []scenario{
+		{
+			"origin",
+			exec.Command("echo", "origin"),
+			exec.Command("echo", "refs/heads/master"),
+			"origin",
+			"master",
+			true,
+		},
+		{
+			"remote name with a slash",
+			exec.Command("echo", "team/fork"),
+			exec.Command("echo", "refs/heads/feature/login"),
+			"team/fork",
+			"feature/login",
+			true,
+		},
+		{
+			"local upstream",
+			exec.Command("echo", "."),
+			exec.Command("echo", "refs/heads/master"),
+			".",
+			"master",
+			true,
+		},
+		{
+			"no upstream",
+			exec.Command("test"),
+			exec.Command("test"),
+			"origin",
+			"feature",
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "config", args[0])
+				switch args[2] {
+				case "branch.feature.remote":
+					return s.remote
+				case "branch.feature.merge":
+					return s.merge
+				}
+				t.Errorf("unexpected args: %v", args)
+				return nil
+			}
+			remote, remoteBranch, ok := gitCmd.GetUpstreamRemoteAndBranch("feature")
+			assert.EqualValues(t, s.expectedRemote, remote)
+			assert.EqualValues(t, s.expectedRemoteBranch, remoteBranch)
+			assert.EqualValues(t, s.expectedOk, ok)
+		})
+	}
+}
+
+// TestGitCommandGetBranchUpstreamDifferenceCount is a function.
+func TestGitCommandGetBranchUpstreamDifferenceCount(t *testing.T) {
+	type scenario struct {
+		testName          string
+		command           func(string, ...string) *exec.Cmd
+		expectedPushables string
+		expectedPullables string
+	}
+
+	scenarios := []scenario{
+		{
+			"branch tracking a remote other than origin",
+			func(cmd string, args ...string) *exec.Cmd {
+				switch args[0] {
+				case "config":
+					if args[2] == "branch.feature.remote" {
+						return exec.Command("echo", "fork")
+					}
+					return exec.Command("echo", "refs/heads/feature-upstream")
+				case "rev-list":
+					if args[1] == "fork/feature-upstream..feature" {
+						return exec.Command("echo", "2")
+					}
+					assert.EqualValues(t, []string{"rev-list", "feature..fork/feature-upstream", "--count"}, args)
+					return exec.Command("echo", "3")
+				}
+				return nil
+			},
+			"2",
+			"3",
+		},
+		{
+			"branch tracking a local branch",
+			func(cmd string, args ...string) *exec.Cmd {
+				switch args[0] {
+				case "config":
+					if args[2] == "branch.feature.remote" {
+						return exec.Command("echo", ".")
+					}
+					return exec.Command("echo", "refs/heads/master")
+				case "rev-list":
+					if args[1] == "master..feature" {
+						return exec.Command("echo", "1")
+					}
+					assert.EqualValues(t, []string{"rev-list", "feature..master", "--count"}, args)
+					return exec.Command("echo", "0")
+				}
+				return nil
+			},
+			"1",
+			"0",
+		},
+		{
+			"branch with no upstream",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "config", args[0])
+				return exec.Command("test")
+			},
+			"?",
+			"?",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			pushables, pullables := gitCmd.GetBranchUpstreamDifferenceCount("feature")
+			assert.EqualValues(t, s.expectedPushables, pushables)
+			assert.EqualValues(t, s.expectedPullables, pullables)
+		})
+	}
+}
+
+// TestGitCommandFastForward is a function.
+func TestGitCommandFastForward(t *testing.T) {
+	type scenario struct {
+		testName     string
+		remote       *exec.Cmd
+		merge        *exec.Cmd
+		expectedArgs []string
+	}
+
+	scenarios := []scenario{
+		{
+			"fetches from the tracked remote and branch",
+			exec.Command("echo", "fork"),
+			exec.Command("echo", "refs/heads/main"),
+			[]string{"fetch", "fork", "main:feature"},
+		},
+		{
+			"fetches from the repo itself when tracking a local branch",
+			exec.Command("echo", "."),
+			exec.Command("echo", "refs/heads/main"),
+			[]string{"fetch", ".", "main:feature"},
+		},
+		{
+			"falls back to origin when there is no upstream",
+			exec.Command("test"),
+			exec.Command("test"),
+			[]string{"fetch", "origin", "feature:feature"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				if args[0] == "config" {
+					if args[2] == "branch.feature.remote" {
+						return s.remote
+					}
+					return s.merge
+				}
+				assert.EqualValues(t, s.expectedArgs, args)
+				return exec.Command("echo")
+			}
+			assert.NoError(t, gitCmd.FastForward("feature"))
+		})
+	}
+}
+
 // TestGitCommandRenameCommit is a function.
 func TestGitCommandRenameCommit(t *testing.T) {
 	gitCmd := NewDummyGitCommand()
@@ -632,6 +929,21 @@ func TestGitCommandRenameCommit(t *testing.T) {
 	assert.NoError(t, gitCmd.RenameCommit("test"))
 }
 
+// TestGitCommandGetCommitMessage is a function.
+func TestGitCommandGetCommitMessage(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"log", "--format=%B", "-n", "1", "HEAD"}, args)
+
+		return exec.Command("echo", "add feature\n\nwith a body\n")
+	}
+
+	message, err := gitCmd.GetCommitMessage("HEAD")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "add feature\n\nwith a body", message)
+}
+
 // TestGitCommandResetToCommit is a function.
 func TestGitCommandResetToCommit(t *testing.T) {
 	gitCmd := NewDummyGitCommand()
@@ -708,99 +1020,50 @@ func TestGitCommandDeleteBranch(t *testing.T) {
 	}
 }
 
-// TestGitCommandMerge is a function.
-func TestGitCommandMerge(t *testing.T) {
-	gitCmd := NewDummyGitCommand()
-	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
-		assert.EqualValues(t, "git", cmd)
-		assert.EqualValues(t, []string{"merge", "--no-edit", "test"}, args)
-
-		return exec.Command("echo")
-	}
-
-	assert.NoError(t, gitCmd.Merge("test"))
-}
-
-// TestGitCommandUsingGpg is a function.
-func TestGitCommandUsingGpg(t *testing.T) {
+// TestGitCommandGetTags is a function.
+func TestGitCommandGetTags(t *testing.T) {
 	type scenario struct {
-		testName           string
-		getLocalGitConfig  func(string) (string, error)
-		getGlobalGitConfig func(string) (string, error)
-		test               func(bool)
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func([]*Tag, error)
 	}
 
 	scenarios := []scenario{
 		{
-			"Option global and local config commit.gpgsign is not set",
-			func(string) (string, error) {
-				return "", nil
-			},
-			func(string) (string, error) {
-				return "", nil
-			},
-			func(gpgEnabled bool) {
-				assert.False(t, gpgEnabled)
-			},
-		},
-		{
-			"Option global config commit.gpgsign is not set, fallback on local config",
-			func(string) (string, error) {
-				return "", nil
-			},
-			func(string) (string, error) {
-				return "true", nil
-			},
-			func(gpgEnabled bool) {
-				assert.True(t, gpgEnabled)
-			},
-		},
-		{
-			"Option commit.gpgsign is true",
-			func(string) (string, error) {
-				return "True", nil
-			},
-			func(string) (string, error) {
-				return "", nil
-			},
-			func(gpgEnabled bool) {
-				assert.True(t, gpgEnabled)
-			},
-		},
-		{
-			"Option commit.gpgsign is on",
-			func(string) (string, error) {
-				return "ON", nil
-			},
-			func(string) (string, error) {
-				return "", nil
+			"annotated and lightweight tags",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"tag", "--list", "--sort=-creatordate", "--format=%(refname:short)%09%(objecttype)%09%(contents:subject)"}, args)
+
+				return exec.Command("printf", "v1.1\\ttag\\tsecond release\\nnightly\\tcommit\\tfix the build\\nv1.0\\ttag\\t\\n")
 			},
-			func(gpgEnabled bool) {
-				assert.True(t, gpgEnabled)
+			func(tags []*Tag, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, []*Tag{
+					{Name: "v1.1", Message: "second release"},
+					{Name: "nightly", Message: ""},
+					{Name: "v1.0", Message: ""},
+				}, tags)
 			},
 		},
 		{
-			"Option commit.gpgsign is yes",
-			func(string) (string, error) {
-				return "YeS", nil
-			},
-			func(string) (string, error) {
-				return "", nil
+			"no tags",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("echo", "-n")
 			},
-			func(gpgEnabled bool) {
-				assert.True(t, gpgEnabled)
+			func(tags []*Tag, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, tags, 0)
 			},
 		},
 		{
-			"Option commit.gpgsign is 1",
-			func(string) (string, error) {
-				return "1", nil
-			},
-			func(string) (string, error) {
-				return "", nil
+			"an error occurred",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("test")
 			},
-			func(gpgEnabled bool) {
-				assert.True(t, gpgEnabled)
+			func(tags []*Tag, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, tags)
 			},
 		},
 	}
@@ -808,9 +1071,334 @@ func TestGitCommandUsingGpg(t *testing.T) {
 	for _, s := range scenarios {
 		t.Run(s.testName, func(t *testing.T) {
 			gitCmd := NewDummyGitCommand()
-			gitCmd.getGlobalGitConfig = s.getGlobalGitConfig
-			gitCmd.getLocalGitConfig = s.getLocalGitConfig
-			s.test(gitCmd.usingGpg())
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.GetTags())
+		})
+	}
+}
+
+// TestGitCommandTagCommands is a function.
+func TestGitCommandTagCommands(t *testing.T) {
+	type scenario struct {
+		testName     string
+		run          func(*GitCommand) error
+		expectedArgs []string
+	}
+
+	scenarios := []scenario{
+		{
+			"Create a lightweight tag",
+			func(gitCmd *GitCommand) error {
+				return gitCmd.CreateLightweightTag("v1.0", "1234567890")
+			},
+			[]string{"tag", "v1.0", "1234567890"},
+		},
+		{
+			"Create an annotated tag",
+			func(gitCmd *GitCommand) error {
+				return gitCmd.CreateAnnotatedTag("v1.0", "first release")
+			},
+			[]string{"tag", "-a", "v1.0", "-m", "first release"},
+		},
+		{
+			"Delete a tag",
+			func(gitCmd *GitCommand) error {
+				return gitCmd.DeleteTag("v1.0")
+			},
+			[]string{"tag", "-d", "v1.0"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, s.expectedArgs, args)
+
+				return exec.Command("echo")
+			}
+			assert.NoError(t, s.run(gitCmd))
+		})
+	}
+}
+
+// TestGitCommandDiffTags is a function.
+func TestGitCommandDiffTags(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"diff", "--color", "v1.0..v1.1"}, args)
+
+		return exec.Command("echo", "diff")
+	}
+
+	output, err := gitCmd.DiffTags("v1.0", "v1.1")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "diff\n", output)
+}
+
+// TestGitCommandGetCommitsBetweenTags is a function.
+func TestGitCommandGetCommitsBetweenTags(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func([]*Commit, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"Commits between the tags",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"log", "--oneline", "v1.0..v1.1"}, args)
+
+				return exec.Command("printf", "abc123 second commit\ndef456 first commit")
+			},
+			func(commits []*Commit, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, commits, 2)
+				assert.EqualValues(t, "abc123", commits[0].Sha)
+				assert.EqualValues(t, "second commit", commits[0].Name)
+				assert.EqualValues(t, "def456", commits[1].Sha)
+			},
+		},
+		{
+			"No commits between the tags",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("echo", "-n")
+			},
+			func(commits []*Commit, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, commits, 0)
+			},
+		},
+		{
+			"An error occurred",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("test")
+			},
+			func(commits []*Commit, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, commits)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.GetCommitsBetweenTags("v1.0", "v1.1"))
+		})
+	}
+}
+
+// TestGitCommandDeleteRemoteBranch is a function.
+func TestGitCommandDeleteRemoteBranch(t *testing.T) {
+	type scenario struct {
+		testName     string
+		remote       string
+		remoteBranch string
+		command      func(string, ...string) *exec.Cmd
+		test         func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"Delete a remote branch",
+			"upstream",
+			"feature/x",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"push", "upstream", "--delete", "feature/x"}, args)
+
+				return exec.Command("echo")
+			},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"Delete a remote branch from a remote with a space in its name",
+			"my fork",
+			"feature/x",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"push", "my fork", "--delete", "feature/x"}, args)
+
+				return exec.Command("echo")
+			},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"Delete a remote branch with an error occurring",
+			"upstream",
+			"feature/x",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("test")
+			},
+			func(err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.DeleteRemoteBranch(s.remote, s.remoteBranch, func(passOrUname string) string {
+				return "\n"
+			}))
+		})
+	}
+}
+
+// TestGitCommandRenameBranch is a function.
+func TestGitCommandRenameBranch(t *testing.T) {
+	type scenario struct {
+		testName     string
+		run          func(*GitCommand) error
+		expectedArgs []string
+	}
+
+	scenarios := []scenario{
+		{
+			"Rename a branch",
+			func(gitCmd *GitCommand) error {
+				return gitCmd.RenameBranch("old-name", "new-name")
+			},
+			[]string{"branch", "-m", "old-name", "new-name"},
+		},
+		{
+			"Rename the current branch",
+			func(gitCmd *GitCommand) error {
+				return gitCmd.RenameCurrentBranch("new-name")
+			},
+			[]string{"branch", "-m", "new-name"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, s.expectedArgs, args)
+
+				return exec.Command("echo")
+			}
+			assert.NoError(t, s.run(gitCmd))
+		})
+	}
+}
+
+// TestGitCommandMerge is a function.
+func TestGitCommandMerge(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"merge", "--no-edit", "test"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.Merge("test"))
+}
+
+// TestGitCommandUsingGpg is a function.
+func TestGitCommandUsingGpg(t *testing.T) {
+	type scenario struct {
+		testName           string
+		getLocalGitConfig  func(string) (string, error)
+		getGlobalGitConfig func(string) (string, error)
+		test               func(bool)
+	}
+
+	scenarios := []scenario{
+		{
+			"Option global and local config commit.gpgsign is not set",
+			func(string) (string, error) {
+				return "", nil
+			},
+			func(string) (string, error) {
+				return "", nil
+			},
+			func(gpgEnabled bool) {
+				assert.False(t, gpgEnabled)
+			},
+		},
+		{
+			"Option global config commit.gpgsign is not set, fallback on local config",
+			func(string) (string, error) {
+				return "", nil
+			},
+			func(string) (string, error) {
+				return "true", nil
+			},
+			func(gpgEnabled bool) {
+				assert.True(t, gpgEnabled)
+			},
+		},
+		{
+			"Option commit.gpgsign is true",
+			func(string) (string, error) {
+				return "True", nil
+			},
+			func(string) (string, error) {
+				return "", nil
+			},
+			func(gpgEnabled bool) {
+				assert.True(t, gpgEnabled)
+			},
+		},
+		{
+			"Option commit.gpgsign is on",
+			func(string) (string, error) {
+				return "ON", nil
+			},
+			func(string) (string, error) {
+				return "", nil
+			},
+			func(gpgEnabled bool) {
+				assert.True(t, gpgEnabled)
+			},
+		},
+		{
+			"Option commit.gpgsign is yes",
+			func(string) (string, error) {
+				return "YeS", nil
+			},
+			func(string) (string, error) {
+				return "", nil
+			},
+			func(gpgEnabled bool) {
+				assert.True(t, gpgEnabled)
+			},
+		},
+		{
+			"Option commit.gpgsign is 1",
+			func(string) (string, error) {
+				return "1", nil
+			},
+			func(string) (string, error) {
+				return "", nil
+			},
+			func(gpgEnabled bool) {
+				assert.True(t, gpgEnabled)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.getGlobalGitConfig = s.getGlobalGitConfig
+			gitCmd.getLocalGitConfig = s.getLocalGitConfig
+			s.test(gitCmd.usingGpg())
 		})
 	}
 }
@@ -976,21 +1564,247 @@ func TestGitCommandAmendHead(t *testing.T) {
 	}
 }
 
-// TestGitCommandPush is a function.
-func TestGitCommandPush(t *testing.T) {
+// TestGitCommandAmendAndContinueRebase is a function.
+func TestGitCommandAmendAndContinueRebase(t *testing.T) {
 	type scenario struct {
-		testName  string
-		command   func(string, ...string) *exec.Cmd
-		forcePush bool
-		test      func(error)
+		testName           string
+		command            func(string, ...string) *exec.Cmd
+		getGlobalGitConfig func(string) (string, error)
+		test               func(*exec.Cmd, error)
 	}
 
 	scenarios := []scenario{
 		{
-			"Push with force disabled",
+			"Amend and continue using gpg",
 			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"push"}, args)
+				assert.EqualValues(t, "bash", cmd)
+				assert.EqualValues(t, []string{"-c", "git commit --amend --no-edit --allow-empty && git rebase --continue"}, args)
+
+				return exec.Command("echo")
+			},
+			func(string) (string, error) {
+				return "true", nil
+			},
+			func(cmd *exec.Cmd, err error) {
+				assert.NotNil(t, cmd)
+				assert.Nil(t, err)
+			},
+		},
+		{
+			"Amend and continue without using gpg",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git commit --amend --no-edit --allow-empty",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git rebase --continue",
+					Replace: "echo",
+				},
+			}),
+			func(string) (string, error) {
+				return "false", nil
+			},
+			func(cmd *exec.Cmd, err error) {
+				assert.Nil(t, cmd)
+				assert.Nil(t, err)
+			},
+		},
+		{
+			"Amend fails so we don't continue",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git commit --amend --no-edit --allow-empty",
+					Replace: "test",
+				},
+			}),
+			func(string) (string, error) {
+				return "false", nil
+			},
+			func(cmd *exec.Cmd, err error) {
+				assert.Nil(t, cmd)
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.getGlobalGitConfig = s.getGlobalGitConfig
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.AmendAndContinueRebase())
+		})
+	}
+}
+
+// TestGitCommandAmendHeadWithFiles is a function.
+func TestGitCommandAmendHeadWithFiles(t *testing.T) {
+	type scenario struct {
+		testName           string
+		fileNames          []string
+		command            func(string, ...string) *exec.Cmd
+		getGlobalGitConfig func(string) (string, error)
+		test               func(*exec.Cmd, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"Stages only the marked files before amending with them",
+			[]string{"a.txt", "my file.txt"},
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git add -- 'a.txt' 'my file.txt'",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git commit --amend --no-edit --only -- 'a.txt' 'my file.txt'",
+					Replace: "echo",
+				},
+			}),
+			func(string) (string, error) {
+				return "", nil
+			},
+			func(cmd *exec.Cmd, err error) {
+				assert.Nil(t, cmd)
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"Amend using gpg",
+			[]string{"a.txt"},
+			func(cmd string, args ...string) *exec.Cmd {
+				if cmd == "git" {
+					assert.EqualValues(t, []string{"add", "--", "a.txt"}, args)
+				} else {
+					assert.EqualValues(t, "bash", cmd)
+					assert.EqualValues(t, []string{"-c", "git commit --amend --no-edit --only -- 'a.txt'"}, args)
+				}
+
+				return exec.Command("echo")
+			},
+			func(string) (string, error) {
+				return "true", nil
+			},
+			func(cmd *exec.Cmd, err error) {
+				assert.NotNil(t, cmd)
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"Doesn't amend if staging fails",
+			[]string{"a.txt"},
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git add -- 'a.txt'",
+					Replace: "test",
+				},
+			}),
+			func(string) (string, error) {
+				return "", nil
+			},
+			func(cmd *exec.Cmd, err error) {
+				assert.Nil(t, cmd)
+				assert.Error(t, err)
+			},
+		},
+		{
+			"No files marked",
+			[]string{},
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.Fail(t, "no command should be run")
+				return nil
+			},
+			func(string) (string, error) {
+				return "", nil
+			},
+			func(cmd *exec.Cmd, err error) {
+				assert.Nil(t, cmd)
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.Platform.shell = "bash"
+			gitCmd.OSCommand.command = s.command
+			gitCmd.getGlobalGitConfig = s.getGlobalGitConfig
+			s.test(gitCmd.AmendHeadWithFiles(s.fileNames))
+		})
+	}
+}
+
+// TestGitCommandAmendFileToHead is a function.
+func TestGitCommandAmendFileToHead(t *testing.T) {
+	type scenario struct {
+		testName           string
+		command            func(string, ...string) *exec.Cmd
+		getGlobalGitConfig func(string) (string, error)
+		test               func(*exec.Cmd, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"Amend file using gpg",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "bash", cmd)
+				assert.EqualValues(t, []string{"-c", "git commit --amend --no-edit --only -- 'my file.txt'"}, args)
+
+				return exec.Command("echo")
+			},
+			func(string) (string, error) {
+				return "true", nil
+			},
+			func(cmd *exec.Cmd, err error) {
+				assert.NotNil(t, cmd)
+				assert.Nil(t, err)
+			},
+		},
+		{
+			"Amend file without using gpg",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"commit", "--amend", "--no-edit", "--only", "--", "my file.txt"}, args)
+
+				return exec.Command("echo")
+			},
+			func(string) (string, error) {
+				return "false", nil
+			},
+			func(cmd *exec.Cmd, err error) {
+				assert.Nil(t, cmd)
+				assert.Nil(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.getGlobalGitConfig = s.getGlobalGitConfig
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.AmendFileToHead("my file.txt"))
+		})
+	}
+}
+
+// TestGitCommandPush is a function.
+func TestGitCommandPush(t *testing.T) {
+	type scenario struct {
+		testName  string
+		command   func(string, ...string) *exec.Cmd
+		forcePush bool
+		test      func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"Push with force disabled",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"push"}, args)
 
 				return exec.Command("echo")
 			},
@@ -1038,6 +1852,86 @@ func TestGitCommandPush(t *testing.T) {
 	}
 }
 
+// TestGitCommandFetch is a function.
+func TestGitCommandFetch(t *testing.T) {
+	type scenario struct {
+		testName     string
+		fetchTags    bool
+		expectedArgs []string
+	}
+
+	scenarios := []scenario{
+		{
+			"Fetch with tags",
+			true,
+			[]string{"fetch", "--tags"},
+		},
+		{
+			"Fetch without tags",
+			false,
+			[]string{"fetch"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.Config.GetUserConfig().Set("git.fetch.tags", s.fetchTags)
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, s.expectedArgs, args)
+
+				return exec.Command("echo")
+			}
+			assert.NoError(t, gitCmd.Fetch(func(string) string { return "\n" }, false))
+		})
+	}
+}
+
+// TestGitCommandPushProtectedBranch is a function.
+func TestGitCommandPushProtectedBranch(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.Config.GetUserConfig().Set("git.protectedBranches", []string{"master", "release/*"})
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"push"}, args)
+
+		return exec.Command("echo")
+	}
+	ask := func(passOrUname string) string {
+		return "\n"
+	}
+
+	assert.Error(t, gitCmd.Push("release/1.0", true, "", ask))
+	assert.NoError(t, gitCmd.Push("release/1.0", false, "", ask))
+}
+
+// TestIsProtectedBranch is a function.
+func TestIsProtectedBranch(t *testing.T) {
+	type scenario struct {
+		testName  string
+		name      string
+		protected []string
+		expected  bool
+	}
+
+	scenarios := []scenario{
+		{"No protected branches", "master", []string{}, false},
+		{"Exact match", "master", []string{"develop", "master"}, true},
+		{"No match", "feature/login", []string{"develop", "master"}, false},
+		{"Glob match", "release/1.0", []string{"release/*"}, true},
+		{"Glob doesn't match across slashes", "release/1.0/hotfix", []string{"release/*"}, false},
+		{"Glob doesn't match the prefix alone", "release", []string{"release/*"}, false},
+		{"Malformed pattern is ignored", "master", []string{"[master"}, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, isProtectedBranch(s.name, s.protected))
+		})
+	}
+}
+
 // TestGitCommandCatFile is a function.
 func TestGitCommandCatFile(t *testing.T) {
 	gitCmd := NewDummyGitCommand()
@@ -1066,6 +1960,32 @@ func TestGitCommandStageFile(t *testing.T) {
 	assert.NoError(t, gitCmd.StageFile("test.txt"))
 }
 
+// TestGitCommandUnstageFiles is a function.
+func TestGitCommandUnstageFiles(t *testing.T) {
+	calls := [][]string{}
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		calls = append(calls, args)
+
+		return exec.Command("echo")
+	}
+
+	files := []*File{
+		{Name: "tracked.txt", Tracked: true, HasStagedChanges: true},
+		{Name: "new file.txt", Tracked: false, HasStagedChanges: true},
+		{Name: "before.txt -> after.txt", Tracked: true, HasStagedChanges: true},
+	}
+
+	assert.NoError(t, gitCmd.UnstageFiles(files))
+	assert.EqualValues(t, [][]string{
+		{"reset", "HEAD", "tracked.txt"},
+		{"rm", "--cached", "new file.txt"},
+		{"reset", "HEAD", "before.txt"},
+		{"reset", "HEAD", "after.txt"},
+	}, calls)
+}
+
 // TestGitCommandUnstageFile is a function.
 func TestGitCommandUnstageFile(t *testing.T) {
 	type scenario struct {
@@ -1523,20 +2443,34 @@ func TestGitCommandGetBranchGraph(t *testing.T) {
 	gitCmd := NewDummyGitCommand()
 	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
 		assert.EqualValues(t, "git", cmd)
-		assert.EqualValues(t, []string{"log", "--graph", "--color", "--abbrev-commit", "--decorate", "--date=relative", "--pretty=medium", "-100", "test"}, args)
+		assert.EqualValues(t, []string{"log", "--graph", "--color", "--abbrev-commit", "--decorate", "--date=relative", "--pretty=medium", "-n", "100", "test"}, args)
 
 		return exec.Command("echo")
 	}
 
-	_, err := gitCmd.GetBranchGraph("test")
+	_, err := gitCmd.GetBranchGraph("test", 100)
 	assert.NoError(t, err)
 }
 
-// TestGitCommandDiff is a function.
-func TestGitCommandDiff(t *testing.T) {
-	type scenario struct {
-		testName string
-		command  func(string, ...string) *exec.Cmd
+// TestGitCommandGetBranchGraphFrom is a function.
+func TestGitCommandGetBranchGraphFrom(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"log", "--graph", "--color", "--abbrev-commit", "--decorate", "--date=relative", "--pretty=medium", "--skip=100", "-n", "50", "test"}, args)
+
+		return exec.Command("echo")
+	}
+
+	_, err := gitCmd.GetBranchGraphFrom("test", 100, 50)
+	assert.NoError(t, err)
+}
+
+// TestGitCommandDiff is a function.
+func TestGitCommandDiff(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
 		file     *File
 		plain    bool
 		cached   bool
@@ -1786,6 +2720,79 @@ func TestGitCommandRebaseBranch(t *testing.T) {
 	}
 }
 
+// TestGitCommandMoveFile is a function.
+func TestGitCommandMoveFile(t *testing.T) {
+	type scenario struct {
+		testName string
+		setup    func(dir string) (string, string)
+		command  func(string, ...string) *exec.Cmd
+		test     func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"moves the file",
+			func(dir string) (string, string) {
+				return "old name.txt", filepath.Join(dir, "new name.txt")
+			},
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.Len(t, args, 3)
+				assert.EqualValues(t, []string{"mv", "old name.txt"}, args[:2])
+				assert.Contains(t, args[2], "new name.txt")
+
+				return exec.Command("echo")
+			},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"moves the file into an existing directory",
+			func(dir string) (string, string) {
+				return "file.txt", dir
+			},
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, []string{"mv", "file.txt"}, args[:2])
+
+				return exec.Command("echo")
+			},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"refuses to overwrite an existing file",
+			func(dir string) (string, string) {
+				to := filepath.Join(dir, "taken.txt")
+				assert.NoError(t, ioutil.WriteFile(to, []byte{}, 0644))
+				return "file.txt", to
+			},
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.Fail(t, "git mv should not be run")
+
+				return exec.Command("echo")
+			},
+			func(err error) {
+				assert.EqualError(t, err, "A file already exists at that path")
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "lazygit-test")
+			assert.NoError(t, err)
+			defer os.RemoveAll(dir)
+			from, to := s.setup(dir)
+
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.MoveFile(from, to))
+		})
+	}
+}
+
 // TestGitCommandCheckoutFile is a function.
 func TestGitCommandCheckoutFile(t *testing.T) {
 	type scenario struct {
@@ -1928,6 +2935,20 @@ func TestGitCommandDiscardOldFileChanges(t *testing.T) {
 	}
 }
 
+// TestGitCommandDiffFileAgainstRevision is a function.
+func TestGitCommandDiffFileAgainstRevision(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"diff", "--no-color", "--no-renames", "-R", "1234567890", "--", "my file.txt"}, args)
+
+		return exec.Command("echo")
+	}
+
+	_, err := gitCmd.DiffFileAgainstRevision("1234567890", "my file.txt")
+	assert.NoError(t, err)
+}
+
 // TestGitCommandShowCommitFile is a function.
 func TestGitCommandShowCommitFile(t *testing.T) {
 	type scenario struct {
@@ -1961,7 +2982,131 @@ func TestGitCommandShowCommitFile(t *testing.T) {
 	for _, s := range scenarios {
 		t.Run(s.testName, func(t *testing.T) {
 			gitCmd.OSCommand.command = s.command
-			s.test(gitCmd.ShowCommitFile(s.commitSha, s.fileName, true))
+			s.test(gitCmd.ShowCommitFile(s.commitSha, s.fileName, "", true))
+		})
+	}
+}
+
+// TestGitCommandShowCommitFileRenames is a function.
+func TestGitCommandShowCommitFileRenames(t *testing.T) {
+	type scenario struct {
+		testName      string
+		detectRenames bool
+		plain         bool
+		expectedArgs  []string
+	}
+
+	scenarios := []scenario{
+		{
+			"renames detected",
+			true,
+			false,
+			[]string{"show", "--color", "123456", "--", "old.txt", "new.txt"},
+		},
+		{
+			"renames not detected",
+			false,
+			false,
+			[]string{"show", "--no-renames", "--color", "123456", "--", "new.txt"},
+		},
+		{
+			"plain diff for building a patch",
+			true,
+			true,
+			[]string{"show", "--no-renames", "123456", "--", "new.txt"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.Config.GetUserConfig().Set("git.detectRenames", s.detectRenames)
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, s.expectedArgs, args)
+				return exec.Command("echo")
+			}
+			_, err := gitCmd.ShowCommitFile("123456", "new.txt", "old.txt", s.plain)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// TestGitCommandSubmoduleDiffFormat is a function.
+func TestGitCommandSubmoduleDiffFormat(t *testing.T) {
+	type scenario struct {
+		testName string
+		format   string
+		run      func(*GitCommand) (string, error)
+		command  func(string, ...string) *exec.Cmd
+	}
+
+	scenarios := []scenario{
+		{
+			"show with the log format",
+			"log",
+			func(gitCmd *GitCommand) (string, error) { return gitCmd.Show("456abcde") },
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git show --color --no-renames --submodule=log 456abcde",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git rev-list -1 --merges 456abcde^...456abcde",
+					Replace: "echo",
+				},
+			}),
+		},
+		{
+			"show with the default format",
+			"short",
+			func(gitCmd *GitCommand) (string, error) { return gitCmd.Show("456abcde") },
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git show --color --no-renames 456abcde",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git rev-list -1 --merges 456abcde^...456abcde",
+					Replace: "echo",
+				},
+			}),
+		},
+		{
+			"commit file with the log format",
+			"log",
+			func(gitCmd *GitCommand) (string, error) {
+				return gitCmd.ShowCommitFile("123456", "hello.txt", "", false)
+			},
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"show", "--no-renames", "--color", "--submodule=log", "123456", "--", "hello.txt"}, args)
+
+				return exec.Command("echo")
+			},
+		},
+		{
+			"plain commit file ignores the format",
+			"log",
+			func(gitCmd *GitCommand) (string, error) {
+				return gitCmd.ShowCommitFile("123456", "hello.txt", "", true)
+			},
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"show", "--no-renames", "123456", "--", "hello.txt"}, args)
+
+				return exec.Command("echo")
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.Config.GetUserConfig().Set("git.submoduleDiffFormat", s.format)
+			gitCmd.OSCommand.command = s.command
+			_, err := s.run(gitCmd)
+			assert.NoError(t, err)
 		})
 	}
 }
@@ -1969,16 +3114,18 @@ func TestGitCommandShowCommitFile(t *testing.T) {
 // TestGitCommandGetCommitFiles is a function.
 func TestGitCommandGetCommitFiles(t *testing.T) {
 	type scenario struct {
-		testName  string
-		commitSha string
-		command   func(string, ...string) *exec.Cmd
-		test      func([]*CommitFile, error)
+		testName      string
+		commitSha     string
+		detectRenames bool
+		command       func(string, ...string) *exec.Cmd
+		test          func([]*CommitFile, error)
 	}
 
 	scenarios := []scenario{
 		{
 			"valid case",
 			"123456",
+			false,
 			test.CreateMockCommand(t, []*test.CommandSwapper{
 				{
 					Expect:  "git show --pretty= --name-only --no-renames 123456",
@@ -1993,12 +3140,30 @@ func TestGitCommandGetCommitFiles(t *testing.T) {
 				}, commitFiles)
 			},
 		},
+		{
+			"renames detected",
+			"123456",
+			true,
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git show --pretty= --name-status 123456",
+					Replace: "printf 'M\thello\nR100\told.txt\tnew.txt\n'",
+				},
+			}),
+			func(commitFiles []*CommitFile, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []*CommitFile{
+					{Sha: "123456", Name: "hello", DisplayString: "M hello", ChangeStatus: "M"},
+					{Sha: "123456", Name: "new.txt", PreviousName: "old.txt", DisplayString: "R old.txt -> new.txt", ChangeStatus: "R"},
+				}, commitFiles)
+			},
+		},
 	}
 
-	gitCmd := NewDummyGitCommand()
-
 	for _, s := range scenarios {
 		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.Config.GetUserConfig().Set("git.detectRenames", s.detectRenames)
 			gitCmd.OSCommand.command = s.command
 			s.test(gitCmd.GetCommitFiles(s.commitSha, nil))
 		})
@@ -2040,6 +3205,53 @@ func TestGitCommandDiscardUnstagedFileChanges(t *testing.T) {
 	}
 }
 
+// TestGitCommandResetFileToHead is a function.
+func TestGitCommandResetFileToHead(t *testing.T) {
+	type scenario struct {
+		testName string
+		file     *File
+		command  func(string, ...string) *exec.Cmd
+		test     func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"valid case",
+			&File{Name: "test.txt", HasStagedChanges: true, HasUnstagedChanges: true, Tracked: true},
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				// unlike DiscardUnstagedFileChanges we check out from HEAD so that
+				// the index is reset too
+				assert.EqualValues(t, []string{"checkout", "HEAD", "--", "test.txt"}, args)
+
+				return exec.Command("echo")
+			},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"an error occurred",
+			&File{Name: "test.txt"},
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("test")
+			},
+			func(err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.ResetFileToHead(s.file))
+		})
+	}
+}
+
 // TestGitCommandDiscardAnyUnstagedFileChanges is a function.
 func TestGitCommandDiscardAnyUnstagedFileChanges(t *testing.T) {
 	type scenario struct {
@@ -2174,6 +3386,62 @@ func TestGitCommandCreateFixupCommit(t *testing.T) {
 	}
 }
 
+// TestIsBinaryDiff is a function.
+func TestIsBinaryDiff(t *testing.T) {
+	type scenario struct {
+		testName string
+		diff     string
+		expected bool
+	}
+
+	scenarios := []scenario{
+		{
+			"binary file",
+			"diff --git a/logo.png b/logo.png\nindex 1a2b3c4..5d6e7f8 100644\nBinary files a/logo.png and b/logo.png differ\n",
+			true,
+		},
+		{
+			"new untracked binary file",
+			"diff --git a/logo.png b/logo.png\nnew file mode 100644\nindex 0000000..5d6e7f8\nBinary files /dev/null and b/logo.png differ\n",
+			true,
+		},
+		{
+			"coloured binary diff",
+			"\x1b[1mdiff --git a/logo.png b/logo.png\x1b[m\n\x1b[1mBinary files a/logo.png and b/logo.png differ\x1b[m\n",
+			true,
+		},
+		{
+			"text file",
+			"diff --git a/README.md b/README.md\nindex 1a2b3c4..5d6e7f8 100644\n--- a/README.md\n+++ b/README.md\n@@ -1 +1 @@\n-Binary files are fun\n+Binary files a and b differ\n",
+			false,
+		},
+		{
+			"empty diff",
+			"",
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, IsBinaryDiff(s.diff))
+		})
+	}
+}
+
+// TestGitCommandCreateSquashCommit is a function.
+func TestGitCommandCreateSquashCommit(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"commit", "--squash=12345"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.CreateSquashCommit("12345"))
+}
+
 func TestFindDotGitDir(t *testing.T) {
 	type scenario struct {
 		testName string
@@ -2248,3 +3516,3899 @@ func TestFindDotGitDir(t *testing.T) {
 		})
 	}
 }
+
+// TestCanContinueRebase is a function.
+func TestCanContinueRebase(t *testing.T) {
+	type scenario struct {
+		testName     string
+		rebaseMode   string
+		inMergeState bool
+		expected     bool
+	}
+
+	scenarios := []scenario{
+		{
+			"not rebasing",
+			"",
+			false,
+			false,
+		},
+		{
+			"not rebasing but merging",
+			"",
+			true,
+			false,
+		},
+		{
+			"normal rebase with conflicts resolved",
+			"normal",
+			false,
+			true,
+		},
+		{
+			"interactive rebase with conflicts resolved",
+			"interactive",
+			false,
+			true,
+		},
+		{
+			"interactive rebase with unresolved conflicts",
+			"interactive",
+			true,
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, canContinueRebase(s.rebaseMode, s.inMergeState))
+		})
+	}
+}
+
+// TestGitCommandShowFileAtRevision is a function.
+func TestGitCommandShowFileAtRevision(t *testing.T) {
+	type scenario struct {
+		testName string
+		rev      string
+		fileName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(string, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"file at a relative revision",
+			"HEAD~5",
+			"pkg/foo.go",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"show", "HEAD~5:pkg/foo.go"}, args)
+
+				return exec.Command("echo", "content")
+			},
+			func(output string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, "content\n", output)
+			},
+		},
+		{
+			"file name with spaces",
+			"1234567",
+			"my file.txt",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"show", "1234567:my file.txt"}, args)
+
+				return exec.Command("echo")
+			},
+			func(output string, err error) {
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.ShowFileAtRevision(s.rev, s.fileName))
+		})
+	}
+}
+
+// TestGetAbortCommandType is a function.
+func TestGetAbortCommandType(t *testing.T) {
+	type scenario struct {
+		testName      string
+		rebaseMode    string
+		cherryPicking bool
+		reverting     bool
+		merging       bool
+		expected      string
+	}
+
+	scenarios := []scenario{
+		{
+			"nothing in progress",
+			"",
+			false,
+			false,
+			false,
+			"",
+		},
+		{
+			"rebasing",
+			"interactive",
+			false,
+			false,
+			false,
+			"rebase",
+		},
+		{
+			"rebasing with conflicts",
+			"normal",
+			false,
+			false,
+			true,
+			"rebase",
+		},
+		{
+			"cherry-picking with conflicts",
+			"",
+			true,
+			false,
+			true,
+			"cherry-pick",
+		},
+		{
+			"reverting with conflicts",
+			"",
+			false,
+			true,
+			true,
+			"revert",
+		},
+		{
+			"merging",
+			"",
+			false,
+			false,
+			true,
+			"merge",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, getAbortCommandType(s.rebaseMode, s.cherryPicking, s.reverting, s.merging))
+		})
+	}
+}
+
+// TestGitCommandAbortAll is a function.
+func TestGitCommandAbortAll(t *testing.T) {
+	type scenario struct {
+		testName  string
+		dotGitDir func(string)
+		command   func(string, ...string) *exec.Cmd
+		test      func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"aborts a cherry-pick",
+			func(dir string) {
+				assert.NoError(t, ioutil.WriteFile(dir+"/CHERRY_PICK_HEAD", []byte{}, 0644))
+			},
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git status --untracked-files=all",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git cherry-pick --abort",
+					Replace: "echo",
+				},
+			}),
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"aborts a rebase",
+			func(dir string) {
+				assert.NoError(t, os.Mkdir(dir+"/rebase-merge", 0755))
+			},
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git status --untracked-files=all",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git rebase --abort",
+					Replace: "echo",
+				},
+			}),
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"aborts a merge with a MERGE_HEAD",
+			func(dir string) {
+				assert.NoError(t, ioutil.WriteFile(dir+"/MERGE_HEAD", []byte{}, 0644))
+			},
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git status --untracked-files=all",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git merge --abort",
+					Replace: "echo",
+				},
+			}),
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"aborts a git am rather than treating it as a rebase",
+			func(dir string) {
+				assert.NoError(t, os.MkdirAll(dir+"/rebase-apply", 0755))
+				assert.NoError(t, ioutil.WriteFile(dir+"/rebase-apply/applying", []byte{}, 0644))
+			},
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git am --abort",
+					Replace: "echo",
+				},
+			}),
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"nothing to abort",
+			func(dir string) {},
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git status --untracked-files=all",
+					Replace: "echo",
+				},
+			}),
+			func(err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "lazygit-test")
+			assert.NoError(t, err)
+			defer os.RemoveAll(dir)
+			s.dotGitDir(dir)
+
+			gitCmd := NewDummyGitCommand()
+			gitCmd.DotGitDir = dir
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.AbortAll())
+		})
+	}
+}
+
+// TestParseDiffNameStatus is a function.
+func TestParseDiffNameStatus(t *testing.T) {
+	type scenario struct {
+		testName string
+		output   string
+		expected []*CommitFile
+	}
+
+	scenarios := []scenario{
+		{
+			"no files",
+			"",
+			[]*CommitFile{},
+		},
+		{
+			"modified, added and deleted files",
+			"M\tpkg/gui/gui.go\nA\tREADME.md\nD\told.txt\n",
+			[]*CommitFile{
+				{Name: "pkg/gui/gui.go", DisplayString: "M pkg/gui/gui.go", ChangeStatus: "M"},
+				{Name: "README.md", DisplayString: "A README.md", ChangeStatus: "A"},
+				{Name: "old.txt", DisplayString: "D old.txt", ChangeStatus: "D"},
+			},
+		},
+		{
+			"renamed file",
+			"R100\tbefore.go\tafter.go\nM\tmy file.txt\n",
+			[]*CommitFile{
+				{Name: "after.go", PreviousName: "before.go", DisplayString: "R before.go -> after.go", ChangeStatus: "R"},
+				{Name: "my file.txt", DisplayString: "M my file.txt", ChangeStatus: "M"},
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, parseDiffNameStatus(s.output))
+		})
+	}
+}
+
+// TestGitCommandGetDiffFiles is a function.
+func TestGitCommandGetDiffFiles(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"diff", "--name-status", "master...feature"}, args)
+
+		return exec.Command("printf", "M\\tfile.go\\n")
+	}
+
+	files, err := gitCmd.GetDiffFiles("master", "feature")
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*CommitFile{{Name: "file.go", DisplayString: "M file.go", ChangeStatus: "M"}}, files)
+}
+
+// TestGitCommandGetDefaultBranch is a function.
+func TestGitCommandGetDefaultBranch(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(string, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"remote HEAD is set",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, []string{"symbolic-ref", "--short", "refs/remotes/origin/HEAD"}, args)
+				return exec.Command("echo", "origin/trunk")
+			},
+			func(branchName string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, "trunk", branchName)
+			},
+		},
+		{
+			"falls back to main",
+			func(cmd string, args ...string) *exec.Cmd {
+				switch args[0] {
+				case "symbolic-ref":
+					return exec.Command("test")
+				case "show-ref":
+					assert.EqualValues(t, []string{"show-ref", "--verify", "--quiet", "refs/heads/main"}, args)
+					return exec.Command("echo")
+				}
+				return nil
+			},
+			func(branchName string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, "main", branchName)
+			},
+		},
+		{
+			"falls back to master",
+			func(cmd string, args ...string) *exec.Cmd {
+				switch args[0] {
+				case "symbolic-ref":
+					return exec.Command("test")
+				case "show-ref":
+					if args[3] == "refs/heads/master" {
+						return exec.Command("echo")
+					}
+					return exec.Command("test")
+				}
+				return nil
+			},
+			func(branchName string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, "master", branchName)
+			},
+		},
+		{
+			"no default branch found",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("test")
+			},
+			func(branchName string, err error) {
+				assert.Error(t, err)
+				assert.EqualValues(t, "", branchName)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.GetDefaultBranch())
+		})
+	}
+}
+
+// TestGitCommandGetDefaultBranchIsCached is a function.
+func TestGitCommandGetDefaultBranchIsCached(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	callCount := 0
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		callCount++
+		return exec.Command("echo", "origin/main")
+	}
+
+	for i := 0; i < 2; i++ {
+		branchName, err := gitCmd.GetDefaultBranch()
+		assert.NoError(t, err)
+		assert.EqualValues(t, "main", branchName)
+	}
+	assert.EqualValues(t, 1, callCount)
+}
+
+// TestCommitFlags is a function.
+func TestCommitFlags(t *testing.T) {
+	type scenario struct {
+		testName       string
+		message        string
+		skipHookPrefix string
+		skipHooks      bool
+		expected       string
+	}
+
+	scenarios := []scenario{
+		{
+			"nothing to skip",
+			"test",
+			"WIP",
+			false,
+			"",
+		},
+		{
+			"message has skip-hook prefix",
+			"WIP test",
+			"WIP",
+			false,
+			"--no-verify",
+		},
+		{
+			"no skip-hook prefix configured",
+			"WIP test",
+			"",
+			false,
+			"",
+		},
+		{
+			"skipping hooks for this commit",
+			"test",
+			"WIP",
+			true,
+			"--no-verify",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, CommitFlags(s.message, s.skipHookPrefix, s.skipHooks))
+		})
+	}
+}
+
+// TestGitCommandCommitSkippingHooks is a function.
+func TestGitCommandCommitSkippingHooks(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"commit", "--no-verify", "-m", "test"}, args)
+
+		return exec.Command("echo")
+	}
+
+	_, err := gitCmd.Commit("test", CommitFlags("test", "", true))
+	assert.NoError(t, err)
+}
+
+// TestGitCommandGetDirectoryLog is a function.
+func TestGitCommandGetDirectoryLog(t *testing.T) {
+	type scenario struct {
+		testName string
+		dir      string
+		limit    int
+		command  func(string, ...string) *exec.Cmd
+		test     func(string, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"directory log",
+			"packages/foo",
+			50,
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"log", "--color", "--oneline", "-50", "--", "packages/foo"}, args)
+
+				return exec.Command("echo", "1234567 commit")
+			},
+			func(output string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, "1234567 commit\n", output)
+			},
+		},
+		{
+			"directory with spaces",
+			"my dir",
+			10,
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"log", "--color", "--oneline", "-10", "--", "my dir"}, args)
+
+				return exec.Command("echo")
+			},
+			func(output string, err error) {
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.GetDirectoryLog(s.dir, s.limit))
+		})
+	}
+}
+
+// TestGitCommandGetShortlog is a function.
+func TestGitCommandGetShortlog(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"shortlog", "-sn", "--all"}, args)
+
+		return exec.Command("echo", "    10\tJesse Duffield")
+	}
+
+	output, err := gitCmd.GetShortlog()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "    10\tJesse Duffield\n", output)
+}
+
+// TestGitCommandApplyEditedHunk is a function.
+func TestGitCommandApplyEditedHunk(t *testing.T) {
+	type scenario struct {
+		testName string
+		content  string
+		command  func(string, ...string) *exec.Cmd
+		test     func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"edited hunk is staged with recounted header",
+			"--- a/filename\n+++ b/filename\n@@ -1,5 +1,5 @@\n apple\n-orange\n ...\n ...\n ...\n# a comment\n",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"apply", "--cached"}, args[:2])
+
+				content, err := ioutil.ReadFile(args[2])
+				assert.NoError(t, err)
+				assert.EqualValues(t, "--- a/filename\n+++ b/filename\n@@ -1,5 +1,4 @@\n apple\n-orange\n ...\n ...\n ...\n", string(content))
+
+				return exec.Command("echo")
+			},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"hunk deleted by the user",
+			"--- a/filename\n+++ b/filename\n# a comment\n",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.Fail(t, "no command should be run")
+				return nil
+			},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			file, err := ioutil.TempFile("", "lazygit-hunk.patch")
+			assert.NoError(t, err)
+			_, err = file.WriteString(s.content)
+			assert.NoError(t, err)
+			assert.NoError(t, file.Close())
+
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.ApplyEditedHunk(file.Name()))
+
+			_, err = os.Stat(file.Name())
+			assert.True(t, os.IsNotExist(err))
+		})
+	}
+}
+
+// TestGitCommandGetCommitsNotIn is a function.
+func TestGitCommandGetCommitsNotIn(t *testing.T) {
+	type scenario struct {
+		testName string
+		getter   func(*GitCommand) ([]*Commit, error)
+		command  func(string, ...string) *exec.Cmd
+		test     func([]*Commit, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"commits on HEAD that are not on the base branch",
+			func(gitCmd *GitCommand) ([]*Commit, error) {
+				return gitCmd.GetCommitsNotIn("main")
+			},
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"log", "--oneline", "main..HEAD"}, args)
+
+				return exec.Command("echo", "8a2bb0e commit 1\n78976bc commit 2")
+			},
+			func(commits []*Commit, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, []*Commit{
+					{Sha: "8a2bb0e", Name: "commit 1", DisplayString: "8a2bb0e commit 1"},
+					{Sha: "78976bc", Name: "commit 2", DisplayString: "78976bc commit 2"},
+				}, commits)
+			},
+		},
+		{
+			"commits on the branch that are not on HEAD",
+			func(gitCmd *GitCommand) ([]*Commit, error) {
+				return gitCmd.GetCommitsNotInHead("main")
+			},
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"log", "--oneline", "HEAD..main"}, args)
+
+				return exec.Command("echo")
+			},
+			func(commits []*Commit, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, commits, 0)
+			},
+		},
+		{
+			"an error occurred",
+			func(gitCmd *GitCommand) ([]*Commit, error) {
+				return gitCmd.GetCommitsNotIn("main")
+			},
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("test")
+			},
+			func(commits []*Commit, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, commits)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(s.getter(gitCmd))
+		})
+	}
+}
+
+// TestGitCommandWithAutoStash is a function.
+func TestGitCommandWithAutoStash(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		f        func() error
+		test     func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"function succeeds",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git stash --include-untracked",
+					Replace: "echo Saved working directory",
+				},
+				{
+					Expect:  "git stash pop",
+					Replace: "echo",
+				},
+			}),
+			func() error {
+				return nil
+			},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"function fails but we still pop",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git stash --include-untracked",
+					Replace: "echo Saved working directory",
+				},
+				{
+					Expect:  "git stash pop",
+					Replace: "echo",
+				},
+			}),
+			func() error {
+				return errors.New("checkout failed")
+			},
+			func(err error) {
+				assert.EqualError(t, err, "checkout failed")
+			},
+		},
+		{
+			"function and pop both fail",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git stash --include-untracked",
+					Replace: "echo Saved working directory",
+				},
+				{
+					Expect:  "git stash pop",
+					Replace: "bash -c \"echo pop failed && exit 1\"",
+				},
+			}),
+			func() error {
+				return errors.New("checkout failed")
+			},
+			func(err error) {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "checkout failed")
+				assert.Contains(t, err.Error(), "pop failed")
+			},
+		},
+		{
+			"nothing to stash so we don't pop",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git stash --include-untracked",
+					Replace: "echo No local changes to save",
+				},
+			}),
+			func() error {
+				return nil
+			},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"stash fails so we don't run the function",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git stash --include-untracked",
+					Replace: "test",
+				},
+			}),
+			func() error {
+				assert.Fail(t, "function should not be called")
+				return nil
+			},
+			func(err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.WithAutoStash(s.f))
+		})
+	}
+}
+
+// TestBlameLineShas is a function.
+func TestBlameLineShas(t *testing.T) {
+	type scenario struct {
+		testName string
+		output   string
+		expected []string
+	}
+
+	scenarios := []scenario{
+		{
+			"no output",
+			"",
+			[]string{},
+		},
+		{
+			"committed lines",
+			"6f0b32f1 (Jesse Duffield 2019-01-01 1) package main\n9d9d7752 (Jesse Duffield 2019-02-01 2) \n6f0b32f1 (Jesse Duffield 2019-01-01 3) func main() {}",
+			[]string{"6f0b32f1", "9d9d7752", "6f0b32f1"},
+		},
+		{
+			"boundary commit",
+			"^6f0b32f (Jesse Duffield 2019-01-01 1) package main",
+			[]string{"6f0b32f"},
+		},
+		{
+			"file was renamed",
+			"6f0b32f1 old/main.go (Jesse Duffield 2019-01-01 1) package main",
+			[]string{"6f0b32f1"},
+		},
+		{
+			"uncommitted line",
+			"00000000 (Not Committed Yet 2019-03-01 1) package main\n6f0b32f1 (Jesse Duffield 2019-01-01 2) ",
+			[]string{"", "6f0b32f1"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, BlameLineShas(s.output))
+		})
+	}
+}
+
+// TestGitCommandBlame is a function.
+func TestGitCommandBlame(t *testing.T) {
+	type scenario struct {
+		testName string
+		fileName string
+		rev      string
+		command  func(string, ...string) *exec.Cmd
+		test     func(string, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"blame in working tree",
+			"my file.txt",
+			"",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"blame", "--date=short", "--", "my file.txt"}, args)
+
+				return exec.Command("echo", "blame output")
+			},
+			func(output string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, "blame output\n", output)
+			},
+		},
+		{
+			"blame at revision",
+			"my file.txt",
+			"1234567",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"blame", "1234567", "--date=short", "--", "my file.txt"}, args)
+
+				return exec.Command("echo", "blame output")
+			},
+			func(output string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, "blame output\n", output)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.Blame(s.fileName, s.rev))
+		})
+	}
+}
+
+// TestGitCommandUnstageHunk is a function.
+func TestGitCommandUnstageHunk(t *testing.T) {
+	diff := "diff --git a/test.txt b/test.txt\nindex 1234567..89abcde 100644\n--- a/test.txt\n+++ b/test.txt\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n@@ -10,3 +10,3 @@\n j\n-k\n+K\n l\n"
+
+	type scenario struct {
+		testName  string
+		hunkIndex int
+		test      func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"reverse applies the second hunk to the index",
+			1,
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"hunk out of range",
+			2,
+			func(err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				switch args[0] {
+				case "diff":
+					assert.EqualValues(t, []string{"diff", "--cached", "--", "test.txt"}, args)
+					return exec.Command("echo", "-n", diff)
+				case "apply":
+					assert.EqualValues(t, []string{"apply", "--cached", "--reverse"}, args[0:3])
+					content, err := ioutil.ReadFile(args[3])
+					assert.NoError(t, err)
+					assert.EqualValues(t, "--- a/test.txt\n+++ b/test.txt\n@@ -10,3 +10,3 @@\n j\n-k\n+K\n l\n", string(content))
+					return exec.Command("echo")
+				}
+				assert.Fail(t, "unexpected command")
+				return nil
+			}
+
+			s.test(gitCmd.UnstageHunk(&File{Name: "test.txt", Tracked: true, HasStagedChanges: true}, s.hunkIndex))
+		})
+	}
+}
+
+// TestGitCommandApplyPatchFile is a function.
+func TestGitCommandApplyPatchFile(t *testing.T) {
+	type scenario struct {
+		testName string
+		cached   bool
+		command  func(string, ...string) *exec.Cmd
+		test     func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"applies to working tree",
+			false,
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  `git apply --check "my fix.patch"`,
+					Replace: "echo",
+				},
+				{
+					Expect:  `git apply "my fix.patch"`,
+					Replace: "echo",
+				},
+			}),
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"applies to index",
+			true,
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  `git apply --check --cached "my fix.patch"`,
+					Replace: "echo",
+				},
+				{
+					Expect:  `git apply --cached "my fix.patch"`,
+					Replace: "echo",
+				},
+			}),
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"does not apply if the check fails",
+			false,
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  `git apply --check "my fix.patch"`,
+					Replace: "bash -c \"echo patch does not apply && exit 1\"",
+				},
+			}),
+			func(err error) {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "patch does not apply")
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.ApplyPatchFile("my fix.patch", s.cached))
+		})
+	}
+}
+
+// TestGitCommandApplyMailboxPatch is a function.
+func TestGitCommandApplyMailboxPatch(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"am", "0001-my fix.patch"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.ApplyMailboxPatch("0001-my fix.patch"))
+}
+
+// TestGitCommandAmMode is a function.
+func TestGitCommandAmMode(t *testing.T) {
+	type scenario struct {
+		testName           string
+		dotGitDir          func(string)
+		expectedAmMode     bool
+		expectedRebaseMode string
+	}
+
+	scenarios := []scenario{
+		{
+			"nothing in progress",
+			func(dir string) {},
+			false,
+			"",
+		},
+		{
+			"applying mailbox patches",
+			func(dir string) {
+				assert.NoError(t, os.MkdirAll(dir+"/rebase-apply", 0755))
+				assert.NoError(t, ioutil.WriteFile(dir+"/rebase-apply/applying", []byte{}, 0644))
+			},
+			true,
+			"",
+		},
+		{
+			"non-interactive rebase",
+			func(dir string) {
+				assert.NoError(t, os.MkdirAll(dir+"/rebase-apply", 0755))
+			},
+			false,
+			"normal",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "lazygit-test")
+			assert.NoError(t, err)
+			defer os.RemoveAll(dir)
+			s.dotGitDir(dir)
+
+			gitCmd := NewDummyGitCommand()
+			gitCmd.DotGitDir = dir
+
+			applying, err := gitCmd.AmMode()
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expectedAmMode, applying)
+
+			rebaseMode, err := gitCmd.RebaseMode()
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expectedRebaseMode, rebaseMode)
+		})
+	}
+}
+
+// TestEnsureBlankLineAfterSubject is a function.
+func TestEnsureBlankLineAfterSubject(t *testing.T) {
+	type scenario struct {
+		testName string
+		message  string
+		expected string
+	}
+
+	scenarios := []scenario{
+		{
+			"subject only",
+			"subject",
+			"subject",
+		},
+		{
+			"blank line already there",
+			"subject\n\nbody line one\nbody line two",
+			"subject\n\nbody line one\nbody line two",
+		},
+		{
+			"body straight after the subject",
+			"subject\nbody line one\nbody line two",
+			"subject\n\nbody line one\nbody line two",
+		},
+		{
+			"whitespace-only line counts as blank",
+			"subject\n  \nbody",
+			"subject\n  \nbody",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, ensureBlankLineAfterSubject(s.message))
+		})
+	}
+}
+
+// TestGitCommandFormatCommitMessage is a function.
+func TestGitCommandFormatCommitMessage(t *testing.T) {
+	type scenario struct {
+		testName              string
+		blankLineAfterSubject bool
+		expected              string
+	}
+
+	scenarios := []scenario{
+		{
+			"blank line enforced",
+			true,
+			"subject\n\nbody",
+		},
+		{
+			"blank line not enforced",
+			false,
+			"subject\nbody",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.Config.GetUserConfig().Set("git.commit.blankLineAfterSubject", s.blankLineAfterSubject)
+			assert.EqualValues(t, s.expected, gitCmd.FormatCommitMessage("subject\nbody"))
+		})
+	}
+}
+
+// TestWrapCommitBody is a function.
+func TestWrapCommitBody(t *testing.T) {
+	type scenario struct {
+		testName string
+		message  string
+		width    int
+		expected string
+	}
+
+	scenarios := []scenario{
+		{
+			"subject only",
+			"a subject line that is longer than the wrap width but should be left alone",
+			20,
+			"a subject line that is longer than the wrap width but should be left alone",
+		},
+		{
+			"long body is wrapped but subject and trailers are not",
+			"a long subject line which stays as it is\n\nthis body line is far too long to fit within the width\nshort line\nSigned-off-by: Jesse Duffield <jessedduffield@gmail.com>",
+			20,
+			"a long subject line which stays as it is\n\nthis body line is\nfar too long to fit\nwithin the width\nshort line\nSigned-off-by: Jesse Duffield <jessedduffield@gmail.com>",
+		},
+		{
+			"words longer than the width get their own line",
+			"subject\n\nsee https://github.com/jesseduffield/lazygit/issues/1",
+			20,
+			"subject\n\nsee\nhttps://github.com/jesseduffield/lazygit/issues/1",
+		},
+		{
+			"indented lines keep their indentation",
+			"subject\n\n    this indented line is too long to fit\n\t- a tabbed list item that is too long",
+			20,
+			"subject\n\n    this indented\n    line is too long\n    to fit\n\t- a tabbed\n\tlist item\n\tthat is too\n\tlong",
+		},
+		{
+			"double spaces inside a line",
+			"subject\n\n  first sentence.  second sentence.",
+			20,
+			"subject\n\n  first sentence.\n  second sentence.",
+		},
+		{
+			"wrapping disabled",
+			"subject\n\nthis body line is far too long to fit within the width",
+			0,
+			"subject\n\nthis body line is far too long to fit within the width",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, wrapCommitBody(s.message, s.width))
+		})
+	}
+}
+
+// TestGitCommandCherryPick is a function.
+func TestGitCommandCherryPick(t *testing.T) {
+	type scenario struct {
+		testName     string
+		run          func(*GitCommand) error
+		expectedArgs []string
+	}
+
+	scenarios := []scenario{
+		{
+			"Cherry-pick commits",
+			func(gitCmd *GitCommand) error {
+				return gitCmd.CherryPick([]string{"1234567", "abcdef0"})
+			},
+			[]string{"cherry-pick", "1234567", "abcdef0"},
+		},
+		{
+			"Continue a cherry-pick",
+			func(gitCmd *GitCommand) error {
+				return gitCmd.CherryPickContinue()
+			},
+			[]string{"cherry-pick", "--continue"},
+		},
+		{
+			"Abort a cherry-pick",
+			func(gitCmd *GitCommand) error {
+				return gitCmd.CherryPickAbort()
+			},
+			[]string{"cherry-pick", "--abort"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, s.expectedArgs, args)
+
+				return exec.Command("echo")
+			}
+			assert.NoError(t, s.run(gitCmd))
+		})
+	}
+}
+
+// TestGitCommandCherryPickRange is a function.
+func TestGitCommandCherryPickRange(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		// the ^ makes the start of the range inclusive
+		assert.EqualValues(t, []string{"cherry-pick", "1234567^..abcdef0"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.CherryPickRange("1234567", "abcdef0"))
+}
+
+// TestGitCommandShowTag is a function.
+func TestGitCommandShowTag(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"show", "--color", "v1.2.3"}, args)
+
+		return exec.Command("echo", "tag v1.2.3")
+	}
+
+	output, err := gitCmd.ShowTag("v1.2.3")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "tag v1.2.3\n", output)
+}
+
+// TestGitCommandGenerateMoveCommitTodo is a function.
+func TestGitCommandGenerateMoveCommitTodo(t *testing.T) {
+	commits := []*Commit{
+		{Sha: "a", Name: "newest"},
+		{Sha: "b", Name: "middle"},
+		{Sha: "c", Name: "older"},
+		{Sha: "d", Name: "oldest"},
+	}
+
+	type scenario struct {
+		testName         string
+		toTop            bool
+		index            int
+		expectedTodo     string
+		expectedBaseSha  string
+		expectedErrorMsg string
+	}
+
+	scenarios := []scenario{
+		{
+			"move to top",
+			true,
+			2,
+			"pick b middle\npick a newest\npick c older\n",
+			"d",
+			"",
+		},
+		{
+			"move to top from just below the top",
+			true,
+			1,
+			"pick a newest\npick b middle\n",
+			"c",
+			"",
+		},
+		{
+			"move oldest commit to top",
+			true,
+			3,
+			"",
+			"",
+			"You cannot interactive rebase onto the first commit",
+		},
+		{
+			"move to bottom",
+			false,
+			0,
+			"pick a newest\npick c older\npick b middle\n",
+			"d",
+			"",
+		},
+		{
+			"move to bottom from the middle",
+			false,
+			1,
+			"pick b middle\npick c older\npick a newest\n",
+			"d",
+			"",
+		},
+		{
+			"move oldest commit to bottom",
+			false,
+			3,
+			"",
+			"",
+			"Not enough room",
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			var todo, sha string
+			var err error
+			if s.toTop {
+				todo, sha, err = gitCmd.generateMoveCommitToTopTodo(commits, s.index)
+			} else {
+				todo, sha, err = gitCmd.generateMoveCommitToBottomTodo(commits, s.index)
+			}
+
+			if s.expectedErrorMsg != "" {
+				assert.EqualError(t, err, s.expectedErrorMsg)
+				return
+			}
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expectedTodo, todo)
+			assert.EqualValues(t, s.expectedBaseSha, sha)
+		})
+	}
+}
+
+// TestExceedsFileSizeLimit is a function.
+func TestExceedsFileSizeLimit(t *testing.T) {
+	type scenario struct {
+		testName  string
+		size      int64
+		limitInMB int
+		expected  bool
+	}
+
+	scenarios := []scenario{
+		{
+			"just under the limit",
+			1024*1024 - 1,
+			1,
+			false,
+		},
+		{
+			"exactly at the limit",
+			1024 * 1024,
+			1,
+			false,
+		},
+		{
+			"just over the limit",
+			1024*1024 + 1,
+			1,
+			true,
+		},
+		{
+			"check disabled",
+			1024 * 1024 * 1024,
+			0,
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, exceedsFileSizeLimit(s.size, s.limitInMB))
+		})
+	}
+}
+
+// TestFileDiffPanels is a function.
+func TestFileDiffPanels(t *testing.T) {
+	type scenario struct {
+		testName                string
+		file                    *File
+		expectedMainCached      bool
+		expectedSecondaryCached bool
+		expectedSplit           bool
+	}
+
+	scenarios := []scenario{
+		{
+			"staged and unstaged changes",
+			&File{HasStagedChanges: true, HasUnstagedChanges: true},
+			true,
+			false,
+			true,
+		},
+		{
+			"only unstaged changes",
+			&File{HasUnstagedChanges: true},
+			false,
+			false,
+			false,
+		},
+		{
+			"only staged changes",
+			&File{HasStagedChanges: true},
+			true,
+			false,
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			mainCached, secondaryCached, split := s.file.DiffPanels()
+			assert.EqualValues(t, s.expectedMainCached, mainCached)
+			assert.EqualValues(t, s.expectedSecondaryCached, secondaryCached)
+			assert.EqualValues(t, s.expectedSplit, split)
+		})
+	}
+}
+
+// TestGitCommandAddRemote is a function.
+func TestGitCommandAddRemote(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"remote", "add", "upstream", "git@github.com:jesseduffield/lazygit.git"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.AddRemote("upstream", "git@github.com:jesseduffield/lazygit.git"))
+}
+
+// TestGitCommandRenameRemote is a function.
+func TestGitCommandRenameRemote(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"remote", "rename", "origin", "my remote"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.RenameRemote("origin", "my remote"))
+}
+
+// TestGitCommandRemoveRemote is a function.
+func TestGitCommandRemoveRemote(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"remote", "remove", "upstream"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.RemoveRemote("upstream"))
+}
+
+// TestGitCommandMoveCommitsToNewBranch is a function.
+func TestGitCommandMoveCommitsToNewBranch(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"branch is created before resetting",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git branch 'my-branch'",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git reset --hard HEAD~2",
+					Replace: "echo",
+				},
+			}),
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"we don't reset if the branch can't be created",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git branch 'my-branch'",
+					Replace: "test",
+				},
+			}),
+			func(err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.MoveCommitsToNewBranch("my-branch", 2))
+		})
+	}
+}
+
+// TestParseShortstat is a function.
+func TestParseShortstat(t *testing.T) {
+	type scenario struct {
+		testName          string
+		output            string
+		expectedFiles     int
+		expectedAdditions int
+		expectedDeletions int
+	}
+
+	scenarios := []scenario{
+		{
+			"insertions and deletions",
+			" 3 files changed, 40 insertions(+), 12 deletions(-)\n",
+			3,
+			40,
+			12,
+		},
+		{
+			"single file with only an insertion",
+			" 1 file changed, 1 insertion(+)\n",
+			1,
+			1,
+			0,
+		},
+		{
+			"only deletions",
+			" 2 files changed, 5 deletions(-)\n",
+			2,
+			0,
+			5,
+		},
+		{
+			"merge commit or commit without changes",
+			"",
+			0,
+			0,
+			0,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			files, additions, deletions := parseShortstat(s.output)
+			assert.EqualValues(t, s.expectedFiles, files)
+			assert.EqualValues(t, s.expectedAdditions, additions)
+			assert.EqualValues(t, s.expectedDeletions, deletions)
+		})
+	}
+}
+
+// TestGitCommandGetCommitStat is a function.
+func TestGitCommandGetCommitStat(t *testing.T) {
+	calls := 0
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		calls++
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"show", "--shortstat", "--format=", "abc123"}, args)
+
+		return exec.Command("echo", " 3 files changed, 40 insertions(+), 12 deletions(-)")
+	}
+
+	for i := 0; i < 2; i++ {
+		files, additions, deletions, err := gitCmd.GetCommitStat("abc123")
+		assert.NoError(t, err)
+		assert.EqualValues(t, 3, files)
+		assert.EqualValues(t, 40, additions)
+		assert.EqualValues(t, 12, deletions)
+	}
+	// the second lookup comes from the cache
+	assert.EqualValues(t, 1, calls)
+}
+
+// TestGitCommandCheckoutTag is a function.
+func TestGitCommandCheckoutTag(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"checkout", "v1.2.3"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.CheckoutTag("v1.2.3"))
+}
+
+// TestGitCommandDiffStatAgainstBranch is a function.
+func TestGitCommandDiffStatAgainstBranch(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"diff", "--stat", "feature/my branch"}, args)
+
+		return exec.Command("echo", " file.txt | 2 +-")
+	}
+
+	output, err := gitCmd.DiffStatAgainstBranch("feature/my branch")
+	assert.NoError(t, err)
+	assert.EqualValues(t, " file.txt | 2 +-\n", output)
+}
+
+// TestGitCommandGenerateAutosquashRangeTodo is a function.
+func TestGitCommandGenerateAutosquashRangeTodo(t *testing.T) {
+	// oldest first
+	commits := []*Commit{
+		{Sha: "a1", Name: "add feature"},
+		{Sha: "b2", Name: "tweak docs"},
+		{Sha: "c3", Name: "fixup! add feature"},
+		{Sha: "d4", Name: "squash! tweak docs"},
+		{Sha: "e5", Name: "fixup! fixup! add feature"},
+		{Sha: "f6", Name: "fixup! tweak docs"},
+	}
+
+	type scenario struct {
+		testName         string
+		topSha           string
+		expectedTodo     string
+		expectedErrorMsg string
+	}
+
+	scenarios := []scenario{
+		{
+			"whole range",
+			"f6",
+			"pick a1 add feature\nfixup c3 fixup! add feature\nfixup e5 fixup! fixup! add feature\npick b2 tweak docs\nsquash d4 squash! tweak docs\nfixup f6 fixup! tweak docs\n",
+			"",
+		},
+		{
+			"commits above the top are left alone",
+			"d4",
+			"pick a1 add feature\nfixup c3 fixup! add feature\npick b2 tweak docs\nsquash d4 squash! tweak docs\npick e5 fixup! fixup! add feature\npick f6 fixup! tweak docs\n",
+			"",
+		},
+		{
+			"top commit not in range",
+			"zz",
+			"",
+			"That commit isn't part of the range being rebased",
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			todo, err := gitCmd.generateAutosquashRangeTodo(commits, s.topSha)
+			if s.expectedErrorMsg != "" {
+				assert.EqualError(t, err, s.expectedErrorMsg)
+				return
+			}
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expectedTodo, todo)
+		})
+	}
+}
+
+// TestGitCommandPrepareFixupCommit is a function.
+func TestGitCommandPrepareFixupCommit(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = test.CreateMockCommand(t, []*test.CommandSwapper{
+		{
+			Expect:  "git show --color --no-renames 12345",
+			Replace: "echo 'commit 12345'",
+		},
+		{
+			Expect:  "git rev-list -1 --merges 12345^...12345",
+			Replace: "echo",
+		},
+	})
+
+	preview, createFixupCommit, err := gitCmd.PrepareFixupCommit("12345")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "commit 12345\n", preview)
+
+	// nothing is committed until the caller confirms
+	gitCmd.OSCommand.command = test.CreateMockCommand(t, []*test.CommandSwapper{
+		{
+			Expect:  "git commit --fixup=12345",
+			Replace: "echo",
+		},
+	})
+	assert.NoError(t, createFixupCommit())
+}
+
+// TestParseAuthors is a function.
+func TestParseAuthors(t *testing.T) {
+	output := "Jesse Duffield <jesse@example.com>\nGlenn Vriesman <glenn@example.com>\nJesse Duffield <jesse@example.com>\n\nDawid Dziurla <dawid@example.com>\n"
+
+	assert.EqualValues(t, []string{
+		"Jesse Duffield <jesse@example.com>",
+		"Glenn Vriesman <glenn@example.com>",
+		"Dawid Dziurla <dawid@example.com>",
+	}, parseAuthors(output))
+}
+
+// TestGitCommandGetRecentAuthors is a function.
+func TestGitCommandGetRecentAuthors(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"log", "--format=%an <%ae>", "-20"}, args)
+
+		return exec.Command("printf", "a <a@example.com>\\na <a@example.com>\\nb <b@example.com>\\n")
+	}
+
+	authors, err := gitCmd.GetRecentAuthors(20)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"a <a@example.com>", "b <b@example.com>"}, authors)
+}
+
+// TestGitCommandCommitWithAuthor is a function.
+func TestGitCommandCommitWithAuthor(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.getGlobalGitConfig = func(string) (string, error) {
+		return "false", nil
+	}
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"commit", "--no-verify", "--author=Jesse Duffield <jesse@example.com>", "-m", "test"}, args)
+
+		return exec.Command("echo")
+	}
+
+	_, err := gitCmd.CommitWithAuthor("test", "--no-verify", "Jesse Duffield <jesse@example.com>")
+	assert.NoError(t, err)
+}
+
+// TestGitCommandGetReflogSince is a function.
+func TestGitCommandGetReflogSince(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"reflog", "--since=1 day ago", "--date=iso", "--format=%h%x09%gd%x09%gs"}, args)
+
+		return exec.Command("printf", "abc1234\\tHEAD@{2020-04-01 10:00:00 +1100}\\tcommit: add feature\\n")
+	}
+
+	entries, err := gitCmd.GetReflogSince("1 day ago")
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*ReflogEntry{
+		{Sha: "abc1234", Date: "2020-04-01 10:00:00 +1100", Action: "commit", Description: "add feature"},
+	}, entries)
+}
+
+// TestReflogEntryFromLine is a function.
+func TestReflogEntryFromLine(t *testing.T) {
+	type scenario struct {
+		testName string
+		line     string
+		expected *ReflogEntry
+	}
+
+	scenarios := []scenario{
+		{
+			"commit",
+			"abc1234\tHEAD@{2020-04-01 10:00:00 +1100}\tcommit: add feature",
+			&ReflogEntry{Sha: "abc1234", Date: "2020-04-01 10:00:00 +1100", Action: "commit", Description: "add feature"},
+		},
+		{
+			"action with a qualifier and colons in the description",
+			"def5678\tHEAD@{2020-04-01 11:30:00 +1100}\trebase -i (finish): returning to refs/heads/master",
+			&ReflogEntry{Sha: "def5678", Date: "2020-04-01 11:30:00 +1100", Action: "rebase -i (finish)", Description: "returning to refs/heads/master"},
+		},
+		{
+			"checkout",
+			"0123abc\tHEAD@{2020-04-01 12:00:00 +1100}\tcheckout: moving from master to feature: x",
+			&ReflogEntry{Sha: "0123abc", Date: "2020-04-01 12:00:00 +1100", Action: "checkout", Description: "moving from master to feature: x"},
+		},
+		{
+			"malformed line",
+			"abc1234",
+			nil,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, reflogEntryFromLine(s.line))
+		})
+	}
+}
+
+// TestGitCommandCommitDraft is a function.
+func TestGitCommandCommitDraft(t *testing.T) {
+	configDir, err := ioutil.TempDir("", "lazygit-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(configDir)
+
+	gitCmd := NewDummyGitCommand()
+	appConfig := NewDummyAppConfig()
+	appConfig.UserConfigDir = configDir
+	gitCmd.Config = appConfig
+
+	draft, err := gitCmd.LoadCommitDraft()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "", draft)
+
+	assert.NoError(t, gitCmd.SaveCommitDraft("add feature\n\nstill a work in progress"))
+	draft, err = gitCmd.LoadCommitDraft()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "add feature\n\nstill a work in progress", draft)
+
+	// saving an empty message throws the draft away
+	assert.NoError(t, gitCmd.SaveCommitDraft(""))
+	draft, err = gitCmd.LoadCommitDraft()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "", draft)
+
+	assert.NoError(t, gitCmd.SaveCommitDraft("add feature"))
+	assert.NoError(t, gitCmd.ClearCommitDraft())
+	draft, err = gitCmd.LoadCommitDraft()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "", draft)
+}
+
+// TestGitCommandGetIncomingCommits is a function.
+func TestGitCommandGetIncomingCommits(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func([]*Commit, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"commits on the upstream that we don't have",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rev-parse --abbrev-ref --symbolic-full-name @{u}",
+					Replace: "echo origin/master",
+				},
+				{
+					Expect:  "git log --oneline HEAD..@{u}",
+					Replace: "echo \"abc1234 fix bug\ndef5678 add feature\"",
+				},
+			}),
+			func(commits []*Commit, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, []*Commit{
+					{Sha: "abc1234", Name: "fix bug", DisplayString: "abc1234 fix bug"},
+					{Sha: "def5678", Name: "add feature", DisplayString: "def5678 add feature"},
+				}, commits)
+			},
+		},
+		{
+			"no upstream",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rev-parse --abbrev-ref --symbolic-full-name @{u}",
+					Replace: "test",
+				},
+			}),
+			func(commits []*Commit, err error) {
+				assert.EqualError(t, err, "The current branch has no upstream, so there is nothing to compare against")
+				assert.Nil(t, commits)
+			},
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.GetIncomingCommits())
+		})
+	}
+}
+
+// TestGitCommandOldestUnpushedCommit is a function.
+func TestGitCommandOldestUnpushedCommit(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(*Commit, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"takes the oldest of the commits we haven't pushed",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rev-parse --abbrev-ref --symbolic-full-name @{push}",
+					Replace: "echo origin/master",
+				},
+				{
+					Expect:  "git log @{push}..HEAD --reverse --oneline",
+					Replace: "echo \"abc1234 fix bug\ndef5678 add feature\"",
+				},
+			}),
+			func(commit *Commit, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, &Commit{Sha: "abc1234", Name: "fix bug", DisplayString: "abc1234 fix bug"}, commit)
+			},
+		},
+		{
+			"nothing to push",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rev-parse --abbrev-ref --symbolic-full-name @{push}",
+					Replace: "echo origin/master",
+				},
+				{
+					Expect:  "git log @{push}..HEAD --reverse --oneline",
+					Replace: "echo -n",
+				},
+			}),
+			func(commit *Commit, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, commit)
+			},
+		},
+		{
+			"no upstream",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rev-parse --abbrev-ref --symbolic-full-name @{push}",
+					Replace: "test",
+				},
+			}),
+			func(commit *Commit, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, commit)
+			},
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.OldestUnpushedCommit())
+		})
+	}
+}
+
+// TestGitCommandRemoveTrackedFile is a function.
+func TestGitCommandRemoveTrackedFile(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"rm", "my file.txt"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.RemoveTrackedFile("my file.txt"))
+}
+
+// TestGitCommandRemoveTrackedFileKeepLocal is a function.
+func TestGitCommandRemoveTrackedFileKeepLocal(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"rm", "--cached", "my file.txt"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.RemoveTrackedFileKeepLocal("my file.txt"))
+}
+
+// TestGitCommandRestoreStashFile is a function.
+func TestGitCommandRestoreStashFile(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"checkout", "stash@{2}", "--", "my file.txt"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.RestoreStashFile(2, "my file.txt"))
+}
+
+// TestGitCommandGenerateSquashMarkedTodo is a function.
+func TestGitCommandGenerateSquashMarkedTodo(t *testing.T) {
+	// newest first
+	commits := []*Commit{
+		{Sha: "a", Name: "commit a"},
+		{Sha: "b", Name: "commit b"},
+		{Sha: "c", Name: "commit c"},
+		{Sha: "d", Name: "commit d"},
+		{Sha: "e", Name: "commit e"},
+		{Sha: "f", Name: "commit f"},
+	}
+
+	type scenario struct {
+		testName         string
+		markedIndices    []int
+		expectedTodo     string
+		expectedBaseSha  string
+		expectedErrorMsg string
+	}
+
+	scenarios := []scenario{
+		{
+			"non-contiguous commits",
+			[]int{0, 2, 4},
+			"pick e commit e\nsquash c commit c\nsquash a commit a\npick d commit d\npick b commit b\n",
+			"f",
+			"",
+		},
+		{
+			"order of marking doesn't matter",
+			[]int{4, 0, 2},
+			"pick e commit e\nsquash c commit c\nsquash a commit a\npick d commit d\npick b commit b\n",
+			"f",
+			"",
+		},
+		{
+			"only one marked commit",
+			[]int{2},
+			"",
+			"",
+			"You need to mark at least two commits to squash",
+		},
+		{
+			"oldest commit marked",
+			[]int{0, 5},
+			"",
+			"",
+			"You cannot interactive rebase onto the first commit",
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			todo, sha, err := gitCmd.generateSquashMarkedTodo(commits, s.markedIndices)
+			if s.expectedErrorMsg != "" {
+				assert.EqualError(t, err, s.expectedErrorMsg)
+				return
+			}
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expectedTodo, todo)
+			assert.EqualValues(t, s.expectedBaseSha, sha)
+		})
+	}
+}
+
+// TestGitCommandSwitch is a function.
+func TestGitCommandSwitch(t *testing.T) {
+	type scenario struct {
+		testName string
+		create   bool
+		expected []string
+	}
+
+	scenarios := []scenario{
+		{
+			"Switch to an existing branch",
+			false,
+			[]string{"switch", "test"},
+		},
+		{
+			"Create and switch to a new branch",
+			true,
+			[]string{"switch", "-c", "test"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, s.expected, args)
+
+				return exec.Command("echo")
+			}
+
+			assert.NoError(t, gitCmd.Switch("test", s.create))
+		})
+	}
+}
+
+// TestGitCommandUseSwitch is a function.
+func TestGitCommandUseSwitch(t *testing.T) {
+	type scenario struct {
+		testName string
+		run      func(*GitCommand) error
+		swappers []*test.CommandSwapper
+	}
+
+	scenarios := []scenario{
+		{
+			"NewBranch",
+			func(gitCmd *GitCommand) error { return gitCmd.NewBranch("test") },
+			[]*test.CommandSwapper{
+				{Expect: "git switch -c test", Replace: "echo"},
+			},
+		},
+		{
+			"Checkout",
+			func(gitCmd *GitCommand) error { return gitCmd.Checkout("test", false) },
+			[]*test.CommandSwapper{
+				{Expect: "git show-ref --verify --quiet refs/heads/test", Replace: "echo"},
+				{Expect: "git switch test", Replace: "echo"},
+			},
+		},
+		{
+			"Checkout forced",
+			func(gitCmd *GitCommand) error { return gitCmd.Checkout("test", true) },
+			[]*test.CommandSwapper{
+				{Expect: "git show-ref --verify --quiet refs/heads/test", Replace: "echo"},
+				{Expect: "git switch --force test", Replace: "echo"},
+			},
+		},
+		{
+			"Checkout something other than a local branch",
+			func(gitCmd *GitCommand) error { return gitCmd.Checkout("v1.0", false) },
+			[]*test.CommandSwapper{
+				{Expect: "git show-ref --verify --quiet refs/heads/v1.0", Replace: "test"},
+				{Expect: "git checkout v1.0", Replace: "echo"},
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.Config.GetUserConfig().Set("git.useSwitch", true)
+			gitCmd.OSCommand.command = test.CreateMockCommand(t, s.swappers)
+
+			assert.NoError(t, s.run(gitCmd))
+		})
+	}
+}
+
+// TestGitCommandMergeMode is a function.
+func TestGitCommandMergeMode(t *testing.T) {
+	type scenario struct {
+		testName  string
+		dotGitDir func(string)
+		expected  bool
+	}
+
+	scenarios := []scenario{
+		{
+			"MERGE_HEAD exists",
+			func(dir string) {
+				assert.NoError(t, ioutil.WriteFile(dir+"/MERGE_HEAD", []byte{}, 0644))
+			},
+			true,
+		},
+		{
+			"no MERGE_HEAD",
+			func(dir string) {},
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "lazygit-test")
+			assert.NoError(t, err)
+			defer os.RemoveAll(dir)
+			s.dotGitDir(dir)
+
+			gitCmd := NewDummyGitCommand()
+			gitCmd.DotGitDir = dir
+			merging, err := gitCmd.MergeMode()
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expected, merging)
+		})
+	}
+}
+
+// TestGraphLineSha is a function.
+func TestGraphLineSha(t *testing.T) {
+	type scenario struct {
+		testName string
+		line     string
+		expected string
+	}
+
+	scenarios := []scenario{
+		{
+			"commit on the first column",
+			"* 1a2b3c4 (HEAD -> master) add feature",
+			"1a2b3c4",
+		},
+		{
+			"commit on another branch",
+			"| | * 8f9e0d1c (origin/feature) fix the thing",
+			"8f9e0d1c",
+		},
+		{
+			"merge line",
+			"|\\",
+			"",
+		},
+		{
+			"branching line",
+			"| |/",
+			"",
+		},
+		{
+			"message that isn't a sha",
+			"* | not a sha",
+			"",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, GraphLineSha(s.line))
+		})
+	}
+}
+
+// TestGitCommandGetAllBranchesGraph is a function.
+func TestGitCommandGetAllBranchesGraph(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"log", "--graph", "--all", "--oneline", "--decorate", "--color=never", "-100"}, args)
+
+		return exec.Command("echo")
+	}
+
+	_, err := gitCmd.GetAllBranchesGraph()
+	assert.NoError(t, err)
+}
+
+// TestFormatChangesSummary is a function.
+func TestFormatChangesSummary(t *testing.T) {
+	type scenario struct {
+		testName string
+		files    []*File
+		expected string
+	}
+
+	scenarios := []scenario{
+		{
+			"clean tree",
+			[]*File{},
+			"clean",
+		},
+		{
+			"only untracked files",
+			[]*File{
+				{Name: "a.txt", Tracked: false, HasUnstagedChanges: true},
+				{Name: "b.txt", Tracked: false, HasUnstagedChanges: true},
+			},
+			"2 untracked",
+		},
+		{
+			"mix of files",
+			[]*File{
+				{Name: "a.txt", Tracked: true, HasStagedChanges: true},
+				{Name: "b.txt", Tracked: true, HasStagedChanges: true},
+				{Name: "c.txt", Tracked: true, HasStagedChanges: true, HasUnstagedChanges: true},
+				{Name: "d.txt", Tracked: true, HasUnstagedChanges: true},
+				{Name: "e.txt", Tracked: false, HasUnstagedChanges: true},
+			},
+			"3 staged, 2 unstaged, 1 untracked",
+		},
+		{
+			"newly added file",
+			[]*File{
+				{Name: "a.txt", Tracked: false, HasStagedChanges: true},
+			},
+			"1 staged",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, FormatChangesSummary(s.files))
+		})
+	}
+}
+
+// TestGenerateExecTodo is a function.
+func TestGenerateExecTodo(t *testing.T) {
+	type scenario struct {
+		testName string
+		commits  []*Commit
+		command  string
+		expected string
+	}
+
+	scenarios := []scenario{
+		{
+			"no commits",
+			[]*Commit{},
+			"make test",
+			"",
+		},
+		{
+			"exec after each pick",
+			[]*Commit{
+				{Sha: "a", Name: "commit a"},
+				{Sha: "b", Name: "commit b"},
+			},
+			"git commit --amend --no-edit -S",
+			"pick a commit a\nexec git commit --amend --no-edit -S\npick b commit b\nexec git commit --amend --no-edit -S\n",
+		},
+		{
+			"multi-line command",
+			[]*Commit{
+				{Sha: "a", Name: "commit a"},
+			},
+			"go build ./...\ngo test ./...",
+			`pick a commit a` + "\n" + `exec sh -c "$(printf '%b' 'go build ./...\ngo test ./...')"` + "\n",
+		},
+		{
+			"multi-line command with an if block",
+			[]*Commit{
+				{Sha: "a", Name: "commit a"},
+			},
+			"if [ -f Makefile ]; then\n  make test\nfi\n",
+			`pick a commit a` + "\n" + `exec sh -c "$(printf '%b' 'if [ -f Makefile ]; then\n  make test\nfi')"` + "\n",
+		},
+		{
+			"single-line command with quotes",
+			[]*Commit{
+				{Sha: "a", Name: "commit a"},
+			},
+			`grep -q "TODO" main.go || echo 'no todos'`,
+			`pick a commit a` + "\n" + `exec grep -q "TODO" main.go || echo 'no todos'` + "\n",
+		},
+		{
+			"multi-line command with quotes and a line continuation",
+			[]*Commit{
+				{Sha: "a", Name: "commit a"},
+			},
+			"echo 'one' \\\n  \"two\"",
+			`pick a commit a` + "\n" + `exec sh -c "$(printf '%b' 'echo '\''one'\'' \\\n  "two"')"` + "\n",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, generateExecTodo(s.commits, s.command))
+		})
+	}
+}
+
+// TestGitCommandHasDirtySubmodules is a function.
+func TestGitCommandHasDirtySubmodules(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(bool, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"no submodules",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git submodule status",
+					Replace: "echo -n",
+				},
+			}),
+			func(dirty bool, err error) {
+				assert.NoError(t, err)
+				assert.False(t, dirty)
+			},
+		},
+		{
+			"clean submodules",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"submodule", "status"}, args)
+
+				return exec.Command("printf", " 1a2b3c4 lib/one (v1.0)\n 5d6e7f8 lib/two (heads/master)")
+			},
+			func(dirty bool, err error) {
+				assert.NoError(t, err)
+				assert.False(t, dirty)
+			},
+		},
+		{
+			"a submodule with a different commit checked out",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("printf", " 1a2b3c4 lib/one (v1.0)\n+5d6e7f8 lib/two (heads/master)")
+			},
+			func(dirty bool, err error) {
+				assert.NoError(t, err)
+				assert.True(t, dirty)
+			},
+		},
+		{
+			"git submodule status fails",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git submodule status",
+					Replace: "test",
+				},
+			}),
+			func(dirty bool, err error) {
+				assert.Error(t, err)
+				assert.False(t, dirty)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.HasDirtySubmodules())
+		})
+	}
+}
+
+// TestGitCommandIsDetachedHead is a function.
+func TestGitCommandIsDetachedHead(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(bool, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"on a branch",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git symbolic-ref --short HEAD",
+					Replace: "echo master",
+				},
+			}),
+			func(detached bool, err error) {
+				assert.NoError(t, err)
+				assert.False(t, detached)
+			},
+		},
+		{
+			"symbolic-ref fails on a detached HEAD",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git symbolic-ref --short HEAD",
+					Replace: "test",
+				},
+				{
+					Expect:  "git rev-parse --verify HEAD",
+					Replace: "echo 1a2b3c4",
+				},
+			}),
+			func(detached bool, err error) {
+				assert.NoError(t, err)
+				assert.True(t, detached)
+			},
+		},
+		{
+			"HEAD doesn't exist",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git symbolic-ref --short HEAD",
+					Replace: "test",
+				},
+				{
+					Expect:  "git rev-parse --verify HEAD",
+					Replace: "test",
+				},
+			}),
+			func(detached bool, err error) {
+				assert.Error(t, err)
+				assert.False(t, detached)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.IsDetachedHead())
+		})
+	}
+}
+
+// TestConflictedFiles is a function.
+func TestConflictedFiles(t *testing.T) {
+	type scenario struct {
+		testName string
+		files    []*File
+		expected []string
+	}
+
+	scenarios := []scenario{
+		{
+			"no files",
+			[]*File{},
+			[]string{},
+		},
+		{
+			"no conflicts",
+			[]*File{
+				{Name: "a.txt", HasStagedChanges: true},
+				{Name: "b.txt", HasUnstagedChanges: true},
+			},
+			[]string{},
+		},
+		{
+			"mix of conflicted and normal files",
+			[]*File{
+				{Name: "a.txt", HasMergeConflicts: true, HasInlineMergeConflicts: true},
+				{Name: "b.txt", HasStagedChanges: true},
+				{Name: "c.txt", HasMergeConflicts: true},
+				{Name: "d.txt", HasUnstagedChanges: true},
+			},
+			[]string{"a.txt", "c.txt"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			names := []string{}
+			for _, file := range ConflictedFiles(s.files) {
+				names = append(names, file.Name)
+			}
+			assert.EqualValues(t, s.expected, names)
+		})
+	}
+}
+
+// TestGitCommandGetConflictedFiles is a function.
+func TestGitCommandGetConflictedFiles(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"status", "--untracked-files=all", "--porcelain"}, args)
+
+		return exec.Command("echo", "MM file1.txt\nUU file2.txt\n?? file3.txt\nAA file4.txt")
+	}
+
+	names := []string{}
+	for _, file := range gitCmd.GetConflictedFiles() {
+		assert.True(t, file.HasMergeConflicts)
+		names = append(names, file.Name)
+	}
+	assert.EqualValues(t, []string{"file2.txt", "file4.txt"}, names)
+}
+
+// TestGitCommandAmendHeadKeepDate is a function.
+func TestGitCommandAmendHeadKeepDate(t *testing.T) {
+	type scenario struct {
+		testName           string
+		getGlobalGitConfig func(string) (string, error)
+		test               func(*exec.Cmd, error)
+	}
+
+	date := "Mon, 2 Mar 2020 10:00:00 +1100"
+	amendCalls := 0
+	command := func(cmd string, args ...string) *exec.Cmd {
+		if cmd == "git" && args[0] == "log" {
+			assert.EqualValues(t, []string{"log", "--format=%aD", "-n", "1", "HEAD"}, args)
+			return exec.Command("echo", date)
+		}
+
+		amendCalls++
+		if cmd == "bash" {
+			assert.EqualValues(t, []string{"-c", "git commit --amend --no-edit --allow-empty --date='" + date + "'"}, args)
+		} else {
+			assert.EqualValues(t, "git", cmd)
+			assert.EqualValues(t, []string{"commit", "--amend", "--no-edit", "--allow-empty", "--date=" + date}, args)
+		}
+		return exec.Command("echo")
+	}
+
+	scenarios := []scenario{
+		{
+			"Amend commit using gpg",
+			func(string) (string, error) {
+				return "true", nil
+			},
+			func(cmd *exec.Cmd, err error) {
+				assert.NoError(t, err)
+				assert.NotNil(t, cmd)
+				assert.Contains(t, cmd.Env, "GIT_COMMITTER_DATE="+date)
+			},
+		},
+		{
+			"Amend commit without using gpg",
+			func(string) (string, error) {
+				return "false", nil
+			},
+			func(cmd *exec.Cmd, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, cmd)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			amendCalls = 0
+			gitCmd := NewDummyGitCommand()
+			gitCmd.getGlobalGitConfig = s.getGlobalGitConfig
+			gitCmd.OSCommand.command = command
+			s.test(gitCmd.AmendHeadKeepDate())
+			assert.EqualValues(t, 1, amendCalls)
+		})
+	}
+}
+
+// TestGitCommandStageGlob is a function.
+func TestGitCommandStageGlob(t *testing.T) {
+	type scenario struct {
+		testName string
+		pattern  string
+		command  func(string, ...string) *exec.Cmd
+		test     func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"passes the pattern through to git unexpanded",
+			"*.go",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"add", "--", "*.go"}, args)
+
+				return exec.Command("echo")
+			},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"empty pattern",
+			"  ",
+			func(cmd string, args ...string) *exec.Cmd {
+				t.Error("no command should be run for an empty pattern")
+				return exec.Command("echo")
+			},
+			func(err error) {
+				assert.EqualError(t, err, "Pattern cannot be empty")
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.StageGlob(s.pattern))
+		})
+	}
+}
+
+// TestGitCommandFilesMatchingGlob is a function.
+func TestGitCommandFilesMatchingGlob(t *testing.T) {
+	type scenario struct {
+		testName string
+		pattern  string
+		command  func(string, ...string) *exec.Cmd
+		test     func([]string, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"files matching the pattern",
+			"*.go",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"add", "--dry-run", "--", "*.go"}, args)
+
+				return exec.Command("printf", "add 'main.go'\nadd 'pkg/gui/gui.go'\n")
+			},
+			func(fileNames []string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, []string{"main.go", "pkg/gui/gui.go"}, fileNames)
+			},
+		},
+		{
+			"nothing matches",
+			"*.rs",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("echo", "-n")
+			},
+			func(fileNames []string, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, fileNames, 0)
+			},
+		},
+		{
+			"empty pattern",
+			"  ",
+			func(cmd string, args ...string) *exec.Cmd {
+				t.Error("no command should be run for an empty pattern")
+				return exec.Command("echo")
+			},
+			func(fileNames []string, err error) {
+				assert.EqualError(t, err, "Pattern cannot be empty")
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.FilesMatchingGlob(s.pattern))
+		})
+	}
+}
+
+// TestGitCommandLargeFiles is a function.
+func TestGitCommandLargeFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lazygit-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	largeFile := filepath.Join(dir, "large.bin")
+	smallFile := filepath.Join(dir, "small.txt")
+	assert.NoError(t, ioutil.WriteFile(largeFile, make([]byte, 2*1024*1024), 0644))
+	assert.NoError(t, ioutil.WriteFile(smallFile, []byte("hello"), 0644))
+
+	gitCmd := NewDummyGitCommand()
+	gitCmd.Config.GetUserConfig().Set("git.largeFileWarningSize", 1)
+
+	largeFiles, err := gitCmd.LargeFiles([]string{smallFile, largeFile, filepath.Join(dir, "deleted.txt")})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{largeFile}, largeFiles)
+}
+
+// TestGitCommandShowMergeAgainstParent is a function.
+func TestGitCommandShowMergeAgainstParent(t *testing.T) {
+	type scenario struct {
+		testName  string
+		parentNum int
+		command   func(string, ...string) *exec.Cmd
+		test      func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"first parent",
+			1,
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"diff", "--color", "1234abc^1", "1234abc"}, args)
+
+				return exec.Command("echo")
+			},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"second parent",
+			2,
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"diff", "--color", "1234abc^2", "1234abc"}, args)
+
+				return exec.Command("echo")
+			},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"invalid parent",
+			0,
+			func(cmd string, args ...string) *exec.Cmd {
+				t.Error("no command should be run for an invalid parent")
+				return exec.Command("echo")
+			},
+			func(err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			_, err := gitCmd.ShowMergeAgainstParent("1234abc", s.parentNum)
+			s.test(err)
+		})
+	}
+}
+
+// TestGitCommandGetParentCount is a function.
+func TestGitCommandGetParentCount(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(int, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"regular commit",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"rev-list", "--parents", "-n", "1", "1234abc"}, args)
+
+				return exec.Command("echo", "1234abc 5678def")
+			},
+			func(count int, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 1, count)
+			},
+		},
+		{
+			"merge commit",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("echo", "1234abc 5678def 9abc012")
+			},
+			func(count int, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 2, count)
+			},
+		},
+		{
+			"root commit",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("echo", "1234abc")
+			},
+			func(count int, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 0, count)
+			},
+		},
+		{
+			"git rev-list fails",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("test")
+			},
+			func(count int, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.GetParentCount("1234abc"))
+		})
+	}
+}
+
+// TestGitCommandRebaseOnto is a function.
+func TestGitCommandRebaseOnto(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"rebase", "--autostash", "--onto", "newbase", "oldbase"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.RebaseOnto("newbase", "oldbase"))
+}
+
+// TestGitCommandRebaseBranchOnto is a function.
+func TestGitCommandRebaseBranchOnto(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"rebases the commits since the fork point",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git merge-base HEAD master",
+					Replace: "echo 5678def",
+				},
+				{
+					Expect:  "git rebase --autostash --onto 1234abc 5678def",
+					Replace: "echo",
+				},
+			}),
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"no merge base",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git merge-base HEAD master",
+					Replace: "test",
+				},
+			}),
+			func(err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.RebaseBranchOnto("1234abc", "master"))
+		})
+	}
+}
+
+// TestGitCommandGetMergeBase is a function.
+func TestGitCommandGetMergeBase(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"merge-base", "HEAD", "1234abc"}, args)
+
+		return exec.Command("echo", "5678def")
+	}
+
+	mergeBase, err := gitCmd.GetMergeBase("HEAD", "1234abc")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "5678def", mergeBase)
+}
+
+// TestGitCommandGetCommitCount is a function.
+func TestGitCommandGetCommitCount(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(int, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"repo with commits",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"rev-list", "--count", "HEAD"}, args)
+
+				return exec.Command("echo", "42")
+			},
+			func(count int, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 42, count)
+			},
+		},
+		{
+			"empty repo",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("bash", "-c", "echo \"fatal: ambiguous argument 'HEAD': unknown revision or path not in the working tree.\" && exit 128")
+			},
+			func(count int, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 0, count)
+			},
+		},
+		{
+			"other error",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("bash", "-c", "echo \"fatal: not a git repository\" && exit 128")
+			},
+			func(count int, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.GetCommitCount())
+		})
+	}
+}
+
+// TestGitCommandGetStagedDiff is a function.
+func TestGitCommandGetStagedDiff(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"diff", "--cached", "--color"}, args)
+
+		return exec.Command("echo")
+	}
+
+	_, err := gitCmd.GetStagedDiff()
+	assert.NoError(t, err)
+}
+
+// TestGitCommandGenerateEditRangeTodo is a function.
+func TestGitCommandGenerateEditRangeTodo(t *testing.T) {
+	type scenario struct {
+		testName        string
+		startIndex      int
+		endIndex        int
+		expectedTodo    string
+		expectedBaseSha string
+		expectError     bool
+	}
+
+	commits := []*Commit{
+		{Sha: "a", Name: "commit a"},
+		{Sha: "b", Name: "commit b"},
+		{Sha: "c", Name: "commit c"},
+		{Sha: "d", Name: "commit d"},
+	}
+
+	scenarios := []scenario{
+		{
+			"range in the middle",
+			1,
+			2,
+			"edit c commit c\nedit b commit b\npick a commit a\n",
+			"d",
+			false,
+		},
+		{
+			"single commit",
+			0,
+			0,
+			"edit a commit a\n",
+			"b",
+			false,
+		},
+		{
+			"range including the first commit",
+			2,
+			3,
+			"",
+			"",
+			true,
+		},
+		{
+			"start after end",
+			2,
+			1,
+			"",
+			"",
+			true,
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			todo, sha, err := gitCmd.generateEditRangeTodo(commits, s.startIndex, s.endIndex)
+			if s.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expectedTodo, todo)
+			assert.EqualValues(t, s.expectedBaseSha, sha)
+		})
+	}
+}
+
+// TestGitCommandDiscardFileChangesInRange is a function.
+func TestGitCommandDiscardFileChangesInRange(t *testing.T) {
+	type scenario struct {
+		testName          string
+		getLocalGitConfig func(string) (string, error)
+		command           func(string, ...string) *exec.Cmd
+		test              func(error)
+	}
+
+	commits := []*Commit{
+		{Name: "commit", Sha: "123456"},
+		{Name: "commit2", Sha: "abcdef"},
+		{Name: "commit3", Sha: "fedcba"},
+	}
+
+	scenarios := []scenario{
+		{
+			"returns error when using gpg",
+			func(string) (string, error) {
+				return "true", nil
+			},
+			nil,
+			func(err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			"checks out the file from before the range in each commit",
+			func(string) (string, error) {
+				return "", nil
+			},
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rebase --interactive --autostash --keep-empty --rebase-merges fedcba",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git cat-file -e fedcba:test999.txt",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git checkout fedcba test999.txt",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git commit --amend --no-edit --allow-empty",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git rebase --continue",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git cat-file -e fedcba:test999.txt",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git checkout fedcba test999.txt",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git commit --amend --no-edit --allow-empty",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git rebase --continue",
+					Replace: "echo",
+				},
+			}),
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd.OSCommand.command = s.command
+			gitCmd.getLocalGitConfig = s.getLocalGitConfig
+			s.test(gitCmd.DiscardFileChangesInRange(commits, 0, 1, "test999.txt"))
+		})
+	}
+}
+
+// TestGitCommandRunMaintenance is a function.
+func TestGitCommandRunMaintenance(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"maintenance", "run"}, args)
+
+		return exec.Command("echo")
+	}
+
+	_, err := gitCmd.RunMaintenance()
+	assert.NoError(t, err)
+}
+
+// TestGitCommandGetMaintenanceStrategy is a function.
+func TestGitCommandGetMaintenanceStrategy(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(string, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"strategy set",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"config", "--get", "maintenance.strategy"}, args)
+
+				return exec.Command("echo", "incremental")
+			},
+			func(strategy string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, "incremental", strategy)
+			},
+		},
+		{
+			"strategy not set",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("test")
+			},
+			func(strategy string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, "", strategy)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.GetMaintenanceStrategy())
+		})
+	}
+}
+
+// TestGitCommandRevert is a function.
+func TestGitCommandRevert(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"revert", "--no-edit", "1234abc"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.Revert("1234abc"))
+}
+
+// TestGitCommandShowWithSignature is a function.
+func TestGitCommandShowWithSignature(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"show", "--show-signature", "--color", "1234abc"}, args)
+
+		return exec.Command("echo", "gpg: Good signature")
+	}
+
+	output, err := gitCmd.ShowWithSignature("1234abc")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "gpg: Good signature\n", output)
+}
+
+// TestGitCommandRevertRange is a function.
+func TestGitCommandRevertRange(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"revert", "--no-edit", "1234abc^..5678def"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.RevertRange("1234abc", "5678def"))
+}
+
+// TestGitCommandRevertHead is a function.
+func TestGitCommandRevertHead(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"reverts the commit at HEAD",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rev-parse HEAD",
+					Replace: "echo 1234abcd5678",
+				},
+				{
+					Expect:  "git revert --no-edit 1234abcd5678",
+					Replace: "echo",
+				},
+			}),
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"no HEAD",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rev-parse HEAD",
+					Replace: "test",
+				},
+			}),
+			func(err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.RevertHead())
+		})
+	}
+}
+
+// TestGitCommandCheckIgnore is a function.
+func TestGitCommandCheckIgnore(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(string, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"ignored file",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"check-ignore", "--no-index", "-v", "my file.log"}, args)
+
+				return exec.Command("echo", ".gitignore:3:*.log\tmy file.log")
+			},
+			func(rule string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, ".gitignore:3:*.log\tmy file.log", rule)
+			},
+		},
+		{
+			"file not ignored",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("test")
+			},
+			func(rule string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, "", rule)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.CheckIgnore("my file.log"))
+		})
+	}
+}
+
+// TestGitCommandCommitAndPush is a function.
+func TestGitCommandCommitAndPush(t *testing.T) {
+	type scenario struct {
+		testName           string
+		getGlobalGitConfig func(string) (string, error)
+		commitErr          error
+		expectedCalls      []string
+		test               func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"commits before pushing",
+			func(string) (string, error) {
+				return "false", nil
+			},
+			nil,
+			[]string{"commit", "push"},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"skips the push when the commit fails",
+			func(string) (string, error) {
+				return "false", nil
+			},
+			errors.New("pre-commit hook failed"),
+			[]string{"commit"},
+			func(err error) {
+				assert.EqualError(t, err, "pre-commit hook failed")
+			},
+		},
+		{
+			"does nothing when using gpg",
+			func(string) (string, error) {
+				return "true", nil
+			},
+			nil,
+			[]string{},
+			func(err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.getGlobalGitConfig = s.getGlobalGitConfig
+			calls := []string{}
+			err := gitCmd.CommitAndPush(
+				func() (*exec.Cmd, error) {
+					calls = append(calls, "commit")
+					return nil, s.commitErr
+				},
+				func() error {
+					calls = append(calls, "push")
+					return nil
+				},
+			)
+			s.test(err)
+			assert.EqualValues(t, s.expectedCalls, calls)
+		})
+	}
+}
+
+// TestHunkStagedState is a function.
+func TestHunkStagedState(t *testing.T) {
+	type scenario struct {
+		testName     string
+		stagedDiff   string
+		unstagedDiff string
+		firstLine    int
+		lastLine     int
+		expected     bool
+	}
+
+	stagedDiff := "diff --git a/test b/test\n--- a/test\n+++ b/test\n@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n"
+	unstagedDiff := "diff --git a/test b/test\n--- a/test\n+++ b/test\n@@ -1,5 +1,5 @@\n a\n B\n c\n-d\n+D\n e\n"
+	prependedDiff := "diff --git a/test b/test\n--- a/test\n+++ b/test\n@@ -0,0 +1,2 @@\n+x\n+y\n"
+	// the unstaged hunk's trailing context reaches the staged change to d
+	contextStagedDiff := "diff --git a/test b/test\n--- a/test\n+++ b/test\n@@ -1,7 +1,7 @@\n a\n b\n c\n-d\n+D\n e\n f\n g\n"
+	contextUnstagedDiff := "diff --git a/test b/test\n--- a/test\n+++ b/test\n@@ -1,4 +1,6 @@\n a\n+x\n+y\n b\n c\n D\n"
+
+	scenarios := []scenario{
+		{"Range covering a staged change", stagedDiff, unstagedDiff, 2, 2, true},
+		{"Range covering an unstaged change", stagedDiff, unstagedDiff, 4, 4, false},
+		{"Range covering both staged and unstaged changes", stagedDiff, unstagedDiff, 1, 5, false},
+		{"Range covering no changes", stagedDiff, unstagedDiff, 5, 5, false},
+		{"Staged change shifted by unstaged lines above it", stagedDiff, prependedDiff, 4, 4, true},
+		{"Unshifted line number no longer matches the staged change", stagedDiff, prependedDiff, 3, 3, false},
+		{"Nothing staged", "", unstagedDiff, 1, 5, false},
+		{"Staged change within the context of an unstaged hunk", contextStagedDiff, contextUnstagedDiff, 6, 6, true},
+		{"Unchanged line within the context of an unstaged hunk", contextStagedDiff, contextUnstagedDiff, 4, 4, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, hunkStagedState(s.stagedDiff, s.unstagedDiff, s.firstLine, s.lastLine))
+		})
+	}
+}
+
+// TestDiffLineNumber is a function.
+func TestDiffLineNumber(t *testing.T) {
+	type scenario struct {
+		testName string
+		diff     string
+		lineIdx  int
+		expected int
+	}
+
+	diff := "diff --git a/test b/test\n--- a/test\n+++ b/test\n@@ -3,4 +3,4 @@\n c\n-d\n+D\n e\n f\n"
+	removalDiff := "diff --git a/test b/test\n--- a/test\n+++ b/test\n@@ -5,2 +4,0 @@\n-e\n-f\n"
+
+	scenarios := []scenario{
+		{"Header line", diff, 1, -1},
+		{"Context line", diff, 4, 3},
+		{"Removed line", diff, 5, 4},
+		{"Added line", diff, 6, 4},
+		{"Context line after a change", diff, 8, 6},
+		{"Lines removed without any context", removalDiff, 4, 5},
+		{"Index past the end of the diff", diff, 20, -1},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, DiffLineNumber(s.diff, s.lineIdx))
+		})
+	}
+}
+
+// TestGitCommandHunkStagedState is a function.
+func TestGitCommandHunkStagedState(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		for _, arg := range args {
+			if arg == "--cached" {
+				return exec.Command("echo", "@@ -1,2 +1,2 @@\n a\n-b\n+B")
+			}
+		}
+		return exec.Command("echo")
+	}
+
+	file := &File{Name: "test.txt", Tracked: true, HasStagedChanges: true}
+
+	staged, err := gitCmd.HunkStagedState(file, 2, 2)
+	assert.NoError(t, err)
+	assert.True(t, staged)
+
+	_, err = gitCmd.HunkStagedState(file, 3, 2)
+	assert.Error(t, err)
+}
+
+// TestGitCommandBisectRun is a function.
+func TestGitCommandBisectRun(t *testing.T) {
+	type scenario struct {
+		testName  string
+		dotGitDir func(string)
+		test      func(string, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"bisect session started",
+			func(dir string) {
+				assert.NoError(t, ioutil.WriteFile(dir+"/BISECT_LOG", []byte{}, 0644))
+			},
+			func(output string, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"no bisect session",
+			func(dir string) {},
+			func(output string, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "lazygit-test")
+			assert.NoError(t, err)
+			defer os.RemoveAll(dir)
+			s.dotGitDir(dir)
+
+			gitCmd := NewDummyGitCommand()
+			gitCmd.DotGitDir = dir
+			gitCmd.OSCommand.Platform.shell = "bash"
+			gitCmd.OSCommand.Platform.shellArg = "-c"
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"bisect", "run", "bash", "-c", "make test && ./check.sh"}, args)
+				return exec.Command("echo")
+			}
+			s.test(gitCmd.BisectRun("make test && ./check.sh"))
+		})
+	}
+}
+
+// TestGitCommandDiffAlgorithm is a function.
+func TestGitCommandDiffAlgorithm(t *testing.T) {
+	type scenario struct {
+		testName  string
+		algorithm string
+		run       func(*GitCommand) (string, error)
+		command   func(string, ...string) *exec.Cmd
+	}
+
+	scenarios := []scenario{
+		{
+			"show with the patience algorithm",
+			"patience",
+			func(gitCmd *GitCommand) (string, error) { return gitCmd.Show("456abcde") },
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git show --color --no-renames --diff-algorithm=patience 456abcde",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git rev-list -1 --merges 456abcde^...456abcde",
+					Replace: "echo",
+				},
+			}),
+		},
+		{
+			"show with the default algorithm",
+			"myers",
+			func(gitCmd *GitCommand) (string, error) { return gitCmd.Show("456abcde") },
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git show --color --no-renames 456abcde",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git rev-list -1 --merges 456abcde^...456abcde",
+					Replace: "echo",
+				},
+			}),
+		},
+		{
+			"commit file with the histogram algorithm",
+			"histogram",
+			func(gitCmd *GitCommand) (string, error) {
+				return gitCmd.ShowCommitFile("123456", "hello.txt", "", false)
+			},
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"show", "--no-renames", "--color", "--diff-algorithm=histogram", "123456", "--", "hello.txt"}, args)
+
+				return exec.Command("echo")
+			},
+		},
+		{
+			"file diff with the minimal algorithm",
+			"minimal",
+			func(gitCmd *GitCommand) (string, error) {
+				return gitCmd.Diff(&File{Name: "test.txt", Tracked: true}, false, false), nil
+			},
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"diff", "--color", "--diff-algorithm=minimal", "--", "test.txt"}, args)
+
+				return exec.Command("echo")
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.Config.GetUserConfig().Set("git.diffAlgorithm", s.algorithm)
+			gitCmd.OSCommand.command = s.command
+			_, err := s.run(gitCmd)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// TestGitCommandGetHooks is a function.
+func TestGitCommandGetHooks(t *testing.T) {
+	type scenario struct {
+		testName string
+		setup    func(string)
+		expected []*Hook
+	}
+
+	scenarios := []scenario{
+		{
+			"no hooks directory",
+			func(dir string) {},
+			[]*Hook{},
+		},
+		{
+			"skips sample files and detects which hooks are executable",
+			func(dir string) {
+				hooksDir := filepath.Join(dir, "hooks")
+				assert.NoError(t, os.Mkdir(hooksDir, 0755))
+				assert.NoError(t, ioutil.WriteFile(filepath.Join(hooksDir, "pre-commit"), []byte{}, 0755))
+				assert.NoError(t, ioutil.WriteFile(filepath.Join(hooksDir, "commit-msg"), []byte{}, 0644))
+				assert.NoError(t, ioutil.WriteFile(filepath.Join(hooksDir, "pre-push.sample"), []byte{}, 0755))
+			},
+			[]*Hook{
+				{Name: "commit-msg", Path: filepath.Join("hooks", "commit-msg"), Enabled: false},
+				{Name: "pre-commit", Path: filepath.Join("hooks", "pre-commit"), Enabled: true},
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "lazygit-test")
+			assert.NoError(t, err)
+			defer os.RemoveAll(dir)
+			s.setup(dir)
+
+			gitCmd := NewDummyGitCommand()
+			gitCmd.DotGitDir = dir
+			hooks, err := gitCmd.GetHooks()
+			assert.NoError(t, err)
+
+			for _, hook := range s.expected {
+				hook.Path = filepath.Join(dir, hook.Path)
+			}
+			assert.EqualValues(t, s.expected, hooks)
+		})
+	}
+}
+
+// TestGitCommandSetHookEnabled is a function.
+func TestGitCommandSetHookEnabled(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lazygit-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "pre-commit")
+	assert.NoError(t, ioutil.WriteFile(path, []byte{}, 0644))
+	hook := &Hook{Name: "pre-commit", Path: path}
+
+	gitCmd := NewDummyGitCommand()
+
+	assert.NoError(t, gitCmd.SetHookEnabled(hook, true))
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.EqualValues(t, os.FileMode(0755), info.Mode())
+
+	assert.NoError(t, gitCmd.SetHookEnabled(hook, false))
+	info, err = os.Stat(path)
+	assert.NoError(t, err)
+	assert.EqualValues(t, os.FileMode(0644), info.Mode())
+}
+
+// TestGitCommandCommitPathspec is a function.
+func TestGitCommandCommitPathspec(t *testing.T) {
+	type scenario struct {
+		testName           string
+		command            func(string, ...string) *exec.Cmd
+		getGlobalGitConfig func(string) (string, error)
+		test               func(*exec.Cmd, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"Commit paths using gpg",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "bash", cmd)
+				assert.EqualValues(t, []string{"-c", `git commit --no-verify -m 'add feature' -- 'pkg/my app' 'docs'`}, args)
+
+				return exec.Command("echo")
+			},
+			func(string) (string, error) {
+				return "true", nil
+			},
+			func(cmd *exec.Cmd, err error) {
+				assert.NotNil(t, cmd)
+				assert.Nil(t, err)
+			},
+		},
+		{
+			"Commit paths without using gpg",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"commit", "--no-verify", "-m", "add feature", "--", "pkg/my app", "docs"}, args)
+
+				return exec.Command("echo")
+			},
+			func(string) (string, error) {
+				return "false", nil
+			},
+			func(cmd *exec.Cmd, err error) {
+				assert.Nil(t, cmd)
+				assert.Nil(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.Platform.shell = "bash"
+			gitCmd.getGlobalGitConfig = s.getGlobalGitConfig
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.CommitPathspec("add feature", []string{"pkg/my app", "docs"}, "--no-verify"))
+		})
+	}
+}
+
+// TestGitCommandCheckoutCarryingChanges is a function.
+func TestGitCommandCheckoutCarryingChanges(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"stashes, checks out and pops",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git stash --include-untracked",
+					Replace: "echo Saved working directory",
+				},
+				{
+					Expect:  "git checkout  feature",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git stash pop",
+					Replace: "echo",
+				},
+			}),
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"checkout fails so the changes are put back",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git stash --include-untracked",
+					Replace: "echo Saved working directory",
+				},
+				{
+					Expect:  "git checkout  feature",
+					Replace: "bash -c \"echo no such branch && exit 1\"",
+				},
+				{
+					Expect:  "git stash pop",
+					Replace: "echo",
+				},
+			}),
+			func(err error) {
+				assert.EqualError(t, err, "no such branch\n")
+			},
+		},
+		{
+			"pop conflicts after checking out",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git stash --include-untracked",
+					Replace: "echo Saved working directory",
+				},
+				{
+					Expect:  "git checkout  feature",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git stash pop",
+					Replace: "bash -c \"echo CONFLICT && exit 1\"",
+				},
+			}),
+			func(err error) {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "your changes conflicted")
+				assert.Contains(t, err.Error(), "CONFLICT")
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.CheckoutCarryingChanges("feature"))
+		})
+	}
+}
+
+// TestGitCommandLastFetchTime is a function.
+func TestGitCommandLastFetchTime(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lazygit-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	gitCmd := NewDummyGitCommand()
+	gitCmd.DotGitDir = dir
+
+	fetchTime, err := gitCmd.LastFetchTime()
+	assert.NoError(t, err)
+	assert.True(t, fetchTime.IsZero())
+
+	path := filepath.Join(dir, "FETCH_HEAD")
+	assert.NoError(t, ioutil.WriteFile(path, []byte{}, 0644))
+	modTime := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	assert.NoError(t, os.Chtimes(path, modTime, modTime))
+
+	fetchTime, err = gitCmd.LastFetchTime()
+	assert.NoError(t, err)
+	assert.True(t, modTime.Equal(fetchTime))
+}
@@ -0,0 +1,22 @@
+package commands
+
+import (
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// Hook : A git hook installed in the repo
+type Hook struct {
+	Name    string
+	Path    string
+	Enabled bool // git only runs hooks that are executable
+}
+
+// GetDisplayStrings returns the display string of a hook
+func (h *Hook) GetDisplayStrings(isFocused bool) []string {
+	status := utils.ColoredString("disabled", color.FgRed)
+	if h.Enabled {
+		status = utils.ColoredString("enabled", color.FgGreen)
+	}
+	return []string{h.Name, status}
+}
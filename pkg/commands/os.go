@@ -8,6 +8,7 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-errors/errors"
 
@@ -294,6 +295,48 @@ func (c *OSCommand) FileExists(path string) (bool, error) {
 	return true, nil
 }
 
+// IsExecutable tells us whether any of the execute bits are set on the file
+// at the specified path
+func (c *OSCommand) IsExecutable(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, WrapError(err)
+	}
+	return info.Mode()&0111 != 0, nil
+}
+
+// SetExecutable sets or clears the execute bits of the file at the specified
+// path, leaving its other permissions alone
+func (c *OSCommand) SetExecutable(path string, executable bool) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return WrapError(err)
+	}
+	mode := info.Mode() &^ 0111
+	if executable {
+		mode |= 0111
+	}
+	return WrapError(os.Chmod(path, mode))
+}
+
+// FileModTime returns the time the file at the specified path was last modified
+func (c *OSCommand) FileModTime(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, WrapError(err)
+	}
+	return info.ModTime(), nil
+}
+
+// FileSize returns the size in bytes of the file at the specified path
+func (c *OSCommand) FileSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, WrapError(err)
+	}
+	return info.Size(), nil
+}
+
 // RunPreparedCommand takes a pointer to an exec.Cmd and runs it
 // this is useful if you need to give your command some environment variables
 // before running it
@@ -324,6 +367,16 @@ func (c *OSCommand) RunCustomCommand(command string) *exec.Cmd {
 	return c.PrepareSubProcess(c.Platform.shell, c.Platform.shellArg, command)
 }
 
+// PrepareShellSubProcess prepares an interactive shell, preferring the user's
+// $SHELL and falling back to the platform's shell
+func (c *OSCommand) PrepareShellSubProcess() *exec.Cmd {
+	shell := c.getenv("SHELL")
+	if shell == "" {
+		shell = c.Platform.shell
+	}
+	return c.PrepareSubProcess(shell)
+}
+
 // PipeCommands runs a heap of commands and pipes their inputs/outputs together like A | B | C
 func (c *OSCommand) PipeCommands(commandStrings ...string) error {
 
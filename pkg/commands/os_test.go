@@ -375,3 +375,46 @@ func TestOSCommandCreateTempFile(t *testing.T) {
 		})
 	}
 }
+
+// TestOSCommandPrepareShellSubProcess is a function.
+func TestOSCommandPrepareShellSubProcess(t *testing.T) {
+	type scenario struct {
+		testName string
+		getenv   func(string) string
+		expected string
+	}
+
+	scenarios := []scenario{
+		{
+			"uses $SHELL",
+			func(name string) string {
+				assert.EqualValues(t, "SHELL", name)
+				return "/bin/zsh"
+			},
+			"/bin/zsh",
+		},
+		{
+			"falls back to the platform shell",
+			func(name string) string {
+				return ""
+			},
+			"bash",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			OSCmd := NewDummyOSCommand()
+			OSCmd.getenv = s.getenv
+			OSCmd.Platform.shell = "bash"
+			OSCmd.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, s.expected, cmd)
+				assert.Len(t, args, 0)
+
+				return exec.Command("echo")
+			}
+
+			assert.NotNil(t, OSCmd.PrepareShellSubProcess())
+		})
+	}
+}
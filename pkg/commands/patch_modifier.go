@@ -31,6 +31,12 @@ func newHunk(header string, body string, firstLineIdx int) *PatchHunk {
 	}
 }
 
+// patch returns a patch of just this hunk, unmodified
+func (hunk *PatchHunk) patch(filename string) string {
+	fileHeader := fmt.Sprintf("--- a/%s\n+++ b/%s\n", filename, filename)
+	return fileHeader + hunk.header + "\n" + strings.Join(hunk.bodyLines, "")
+}
+
 func (hunk *PatchHunk) updatedLines(lineIndices []int, reverse bool) []string {
 	skippedNewlineMessageIndex := -1
 	newLines := []string{}
@@ -246,6 +252,18 @@ func (d *PatchModifier) ModifiedPatchForRange(firstLineIdx int, lastLineIdx int,
 	return d.ModifiedPatchForLines(selectedLines, reverse, keepOriginalHeader)
 }
 
+// HunkPatchForLine returns a patch containing only the hunk that contains the
+// given line, unmodified, so that the user can edit it themselves
+func (d *PatchModifier) HunkPatchForLine(lineIdx int) string {
+	for _, hunk := range d.hunks {
+		if lineIdx >= hunk.FirstLineIdx && lineIdx <= hunk.LastLineIdx {
+			return hunk.patch(d.filename)
+		}
+	}
+
+	return ""
+}
+
 func (d *PatchModifier) OriginalPatchLength() int {
 	if len(d.hunks) == 0 {
 		return 0
@@ -258,3 +276,65 @@ func ModifiedPatchForRange(log *logrus.Entry, filename string, diffText string,
 	p := NewPatchModifier(log, filename, diffText)
 	return p.ModifiedPatchForRange(firstLineIdx, lastLineIdx, reverse, keepOriginalHeader)
 }
+
+func HunkPatchForLine(log *logrus.Entry, filename string, diffText string, lineIdx int) string {
+	p := NewPatchModifier(log, filename, diffText)
+	return p.HunkPatchForLine(lineIdx)
+}
+
+// RestorePatchForRange takes a diff from the current version of a file to an
+// old version of it, and returns a patch which brings only the selected lines
+// of the old version into the working tree
+func RestorePatchForRange(log *logrus.Entry, filename string, diffText string, firstLineIdx int, lastLineIdx int) string {
+	return ModifiedPatchForRange(log, filename, diffText, firstLineIdx, lastLineIdx, false, false)
+}
+
+// RecountHunkHeaders takes a patch that the user has edited by hand, drops any
+// comment lines, and updates the line counts in each hunk header to match the
+// edited hunk bodies, given the user is unlikely to have kept them up to date
+func RecountHunkHeaders(patch string) string {
+	lines := strings.Split(strings.TrimRight(patch, "\n"), "\n")
+	result := []string{}
+	headerIdx := -1
+	oldLength, newLength := 0, 0
+
+	updateHeader := func() {
+		if headerIdx == -1 {
+			return
+		}
+		match := hunkHeaderRegexp.FindStringSubmatch(result[headerIdx])
+		result[headerIdx] = fmt.Sprintf("@@ -%s,%d +%s,%d @@%s", match[1], oldLength, match[2], newLength, match[3])
+	}
+
+	for _, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if hunkHeaderRegexp.MatchString(line) {
+			updateHeader()
+			result = append(result, line)
+			headerIdx = len(result) - 1
+			oldLength, newLength = 0, 0
+			continue
+		}
+		if headerIdx != -1 {
+			// editors often strip the trailing whitespace from blank context lines
+			if line == "" {
+				line = " "
+			}
+			switch line[:1] {
+			case "+":
+				newLength++
+			case "-":
+				oldLength++
+			case " ":
+				oldLength++
+				newLength++
+			}
+		}
+		result = append(result, line)
+	}
+	updateHeader()
+
+	return strings.Join(result, "\n") + "\n"
+}
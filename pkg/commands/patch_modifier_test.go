@@ -509,3 +509,237 @@ func TestModifyPatchForRange(t *testing.T) {
 		})
 	}
 }
+
+// TestHunkPatchForLine is a function.
+func TestHunkPatchForLine(t *testing.T) {
+	type scenario struct {
+		testName string
+		diffText string
+		lineIdx  int
+		expected string
+	}
+
+	scenarios := []scenario{
+		{
+			testName: "line outside of any hunk",
+			diffText: simpleDiff,
+			lineIdx:  1,
+			expected: "",
+		},
+		{
+			testName: "line in only hunk",
+			diffText: simpleDiff,
+			lineIdx:  6,
+			expected: `--- a/filename
++++ b/filename
+@@ -1,5 +1,5 @@
+ apple
+-orange
++grape
+ ...
+ ...
+ ...
+`,
+		},
+		{
+			testName: "line in second hunk",
+			diffText: twoHunks,
+			lineIdx:  14,
+			expected: `--- a/filename
++++ b/filename
+@@ -8,6 +8,8 @@ grape
+ ...
+ ...
+ ...
++pear
++lemon
+ ...
+ ...
+ ...
+`,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			result := HunkPatchForLine(nil, "filename", s.diffText, s.lineIdx)
+			if !assert.Equal(t, s.expected, result) {
+				fmt.Println(result)
+			}
+		})
+	}
+}
+
+// TestRecountHunkHeaders is a function.
+func TestRecountHunkHeaders(t *testing.T) {
+	type scenario struct {
+		testName string
+		patch    string
+		expected string
+	}
+
+	scenarios := []scenario{
+		{
+			testName: "unedited hunk",
+			patch: `--- a/filename
++++ b/filename
+@@ -1,5 +1,5 @@
+ apple
+-orange
++grape
+ ...
+ ...
+ ...
+`,
+			expected: `--- a/filename
++++ b/filename
+@@ -1,5 +1,5 @@
+ apple
+-orange
++grape
+ ...
+ ...
+ ...
+`,
+		},
+		{
+			testName: "edited hunk with comments and a stripped blank context line",
+			patch: `--- a/filename
++++ b/filename
+@@ -8,6 +8,8 @@ grape
+ ...
+
+ ...
++pear
+ ...
+ ...
+ ...
+# ---
+# Lines starting with # will be removed.
+
+`,
+			expected: `--- a/filename
++++ b/filename
+@@ -8,6 +8,7 @@ grape
+ ...
+ 
+ ...
++pear
+ ...
+ ...
+ ...
+`,
+		},
+		{
+			testName: "removal turned into context",
+			patch: `--- a/filename
++++ b/filename
+@@ -60,4 +60,4 @@ grape
+ ...
+ ...
+ ...
+ last line
+\ No newline at end of file
++last line
+`,
+			expected: `--- a/filename
++++ b/filename
+@@ -60,4 +60,5 @@ grape
+ ...
+ ...
+ ...
+ last line
+\ No newline at end of file
++last line
+`,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			result := RecountHunkHeaders(s.patch)
+			if !assert.Equal(t, s.expected, result) {
+				fmt.Println(result)
+			}
+		})
+	}
+}
+
+// TestRestorePatchForRange is a function.
+func TestRestorePatchForRange(t *testing.T) {
+	type scenario struct {
+		testName       string
+		diffText       string
+		firstLineIndex int
+		lastLineIndex  int
+		expected       string
+	}
+
+	scenarios := []scenario{
+		{
+			testName:       "only context selected",
+			diffText:       twoHunks,
+			firstLineIndex: 5,
+			lastLineIndex:  5,
+			expected:       "",
+		},
+		{
+			testName:       "old line selected without the current line it replaced",
+			diffText:       twoHunks,
+			firstLineIndex: 7,
+			lastLineIndex:  7,
+			expected: `--- a/filename
++++ b/filename
+@@ -1,5 +1,6 @@
+ apple
+ grape
++orange
+ ...
+ ...
+ ...
+`,
+		},
+		{
+			testName:       "one of several old lines selected",
+			diffText:       twoHunks,
+			firstLineIndex: 15,
+			lastLineIndex:  15,
+			expected: `--- a/filename
++++ b/filename
+@@ -8,6 +8,7 @@ grape
+ ...
+ ...
+ ...
++pear
+ ...
+ ...
+ ...
+`,
+		},
+		{
+			testName:       "old and current lines selected",
+			diffText:       twoHunks,
+			firstLineIndex: 6,
+			lastLineIndex:  7,
+			expected: `--- a/filename
++++ b/filename
+@@ -1,5 +1,5 @@
+ apple
+-grape
++orange
+ ...
+ ...
+ ...
+`,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			result := RestorePatchForRange(nil, "filename", s.diffText, s.firstLineIndex, s.lastLineIndex)
+			if !assert.Equal(t, s.expected, result) {
+				fmt.Println(result)
+			}
+		})
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"github.com/go-errors/errors"
 )
 
-// Service is a service that repository is on (Github, Bitbucket, ...)
+// Service is a service that repository is on (Github, Bitbucket, ...). Its
+// PullRequestURL is formatted with the owner, repository, source branch and
+// target branch, in that order
 type Service struct {
 	Name           string
 	PullRequestURL string
@@ -30,15 +32,15 @@ func getServices() []*Service {
 	return []*Service{
 		{
 			Name:           "github.com",
-			PullRequestURL: "https://github.com/%s/%s/compare/%s?expand=1",
+			PullRequestURL: "https://github.com/%[1]s/%[2]s/compare/%[4]s...%[3]s?expand=1",
 		},
 		{
 			Name:           "bitbucket.org",
-			PullRequestURL: "https://bitbucket.org/%s/%s/pull-requests/new?source=%s&t=1",
+			PullRequestURL: "https://bitbucket.org/%[1]s/%[2]s/pull-requests/new?source=%[3]s&dest=%[4]s&t=1",
 		},
 		{
 			Name:           "gitlab.com",
-			PullRequestURL: "https://gitlab.com/%s/%s/merge_requests/new?merge_request[source_branch]=%s",
+			PullRequestURL: "https://gitlab.com/%[1]s/%[2]s/merge_requests/new?merge_request[source_branch]=%[3]s&merge_request[target_branch]=%[4]s",
 		},
 	}
 }
@@ -59,7 +61,8 @@ func (pr *PullRequest) Create(branch *Branch) error {
 		return errors.New(pr.GitCommand.Tr.SLocalize("NoBranchOnRemote"))
 	}
 
-	repoURL := pr.GitCommand.GetRemoteURL()
+	remote, remoteBranch, _ := pr.GitCommand.GetUpstreamRemoteAndBranch(branch.Name)
+	repoURL := pr.GitCommand.GetRemoteURL(remote)
 	var gitService *Service
 
 	for _, service := range pr.GitServices {
@@ -73,13 +76,28 @@ func (pr *PullRequest) Create(branch *Branch) error {
 		return errors.New(pr.GitCommand.Tr.SLocalize("UnsupportedGitService"))
 	}
 
+	baseBranch, err := pr.GitCommand.GetDefaultBranch()
+	if err != nil {
+		return err
+	}
+
 	repoInfo := getRepoInfoFromURL(repoURL)
 
 	return pr.GitCommand.OSCommand.OpenLink(fmt.Sprintf(
-		gitService.PullRequestURL, repoInfo.Owner, repoInfo.Repository, branch.Name,
+		gitService.PullRequestURL, repoInfo.Owner, repoInfo.Repository, remoteBranch, baseBranch,
 	))
 }
 
+// PushAndCreate runs the given push function and then opens the link to a new
+// pull request for the branch. If the push fails we don't open anything
+func (pr *PullRequest) PushAndCreate(branch *Branch, push func() error) error {
+	if err := push(); err != nil {
+		return err
+	}
+
+	return pr.Create(branch)
+}
+
 func getRepoInfoFromURL(url string) *RepoInformation {
 	isHTTP := strings.HasPrefix(url, "http")
 
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/go-errors/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -42,6 +43,28 @@ func TestGetRepoInfoFromURL(t *testing.T) {
 	}
 }
 
+// pullRequestGitCommand answers the git calls made when creating a pull
+// request, with each branch tracking a branch of the same name on origin,
+// origin pointing at the given url and master being the default branch
+func pullRequestGitCommand(args []string, remoteURL string) *exec.Cmd {
+	switch args[0] {
+	case "config":
+		key := args[2]
+		switch {
+		case key == "remote.origin.url":
+			return exec.Command("echo", remoteURL)
+		case strings.HasSuffix(key, ".remote"):
+			return exec.Command("echo", "origin")
+		case strings.HasSuffix(key, ".merge"):
+			branchName := strings.TrimSuffix(strings.TrimPrefix(key, "branch."), ".merge")
+			return exec.Command("echo", "refs/heads/"+branchName)
+		}
+	case "symbolic-ref":
+		return exec.Command("echo", "origin/master")
+	}
+	return exec.Command("echo")
+}
+
 // TestCreatePullRequest is a function.
 func TestCreatePullRequest(t *testing.T) {
 	type scenario struct {
@@ -58,13 +81,12 @@ func TestCreatePullRequest(t *testing.T) {
 				Name: "feature/profile-page",
 			},
 			func(cmd string, args ...string) *exec.Cmd {
-				// Handle git remote url call
-				if strings.HasPrefix(cmd, "git") {
-					return exec.Command("echo", "git@bitbucket.org:johndoe/social_network.git")
+				if cmd == "git" {
+					return pullRequestGitCommand(args, "git@bitbucket.org:johndoe/social_network.git")
 				}
 
 				assert.Equal(t, cmd, "open")
-				assert.Equal(t, args, []string{"https://bitbucket.org/johndoe/social_network/pull-requests/new?source=feature/profile-page&t=1"})
+				assert.Equal(t, args, []string{"https://bitbucket.org/johndoe/social_network/pull-requests/new?source=feature/profile-page&dest=master&t=1"})
 				return exec.Command("echo")
 			},
 			func(err error) {
@@ -77,13 +99,12 @@ func TestCreatePullRequest(t *testing.T) {
 				Name: "feature/events",
 			},
 			func(cmd string, args ...string) *exec.Cmd {
-				// Handle git remote url call
-				if strings.HasPrefix(cmd, "git") {
-					return exec.Command("echo", "https://my_username@bitbucket.org/johndoe/social_network.git")
+				if cmd == "git" {
+					return pullRequestGitCommand(args, "https://my_username@bitbucket.org/johndoe/social_network.git")
 				}
 
 				assert.Equal(t, cmd, "open")
-				assert.Equal(t, args, []string{"https://bitbucket.org/johndoe/social_network/pull-requests/new?source=feature/events&t=1"})
+				assert.Equal(t, args, []string{"https://bitbucket.org/johndoe/social_network/pull-requests/new?source=feature/events&dest=master&t=1"})
 				return exec.Command("echo")
 			},
 			func(err error) {
@@ -96,13 +117,12 @@ func TestCreatePullRequest(t *testing.T) {
 				Name: "feature/sum-operation",
 			},
 			func(cmd string, args ...string) *exec.Cmd {
-				// Handle git remote url call
-				if strings.HasPrefix(cmd, "git") {
-					return exec.Command("echo", "git@github.com:peter/calculator.git")
+				if cmd == "git" {
+					return pullRequestGitCommand(args, "git@github.com:peter/calculator.git")
 				}
 
 				assert.Equal(t, cmd, "open")
-				assert.Equal(t, args, []string{"https://github.com/peter/calculator/compare/feature/sum-operation?expand=1"})
+				assert.Equal(t, args, []string{"https://github.com/peter/calculator/compare/master...feature/sum-operation?expand=1"})
 				return exec.Command("echo")
 			},
 			func(err error) {
@@ -115,13 +135,40 @@ func TestCreatePullRequest(t *testing.T) {
 				Name: "feature/ui",
 			},
 			func(cmd string, args ...string) *exec.Cmd {
-				// Handle git remote url call
-				if strings.HasPrefix(cmd, "git") {
-					return exec.Command("echo", "git@gitlab.com:peter/calculator.git")
+				if cmd == "git" {
+					return pullRequestGitCommand(args, "git@gitlab.com:peter/calculator.git")
+				}
+
+				assert.Equal(t, cmd, "open")
+				assert.Equal(t, args, []string{"https://gitlab.com/peter/calculator/merge_requests/new?merge_request[source_branch]=feature/ui&merge_request[target_branch]=master"})
+				return exec.Command("echo")
+			},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"Opens a link for the branch tracked on a remote other than origin",
+			&Branch{
+				Name: "sum",
+			},
+			func(cmd string, args ...string) *exec.Cmd {
+				if cmd == "git" {
+					if args[0] == "config" {
+						switch args[2] {
+						case "branch.sum.remote":
+							return exec.Command("echo", "fork")
+						case "branch.sum.merge":
+							return exec.Command("echo", "refs/heads/feature/sum-operation")
+						case "remote.fork.url":
+							return exec.Command("echo", "git@github.com:paul/calculator.git")
+						}
+					}
+					return pullRequestGitCommand(args, "")
 				}
 
 				assert.Equal(t, cmd, "open")
-				assert.Equal(t, args, []string{"https://gitlab.com/peter/calculator/merge_requests/new?merge_request[source_branch]=feature/ui"})
+				assert.Equal(t, args, []string{"https://github.com/paul/calculator/compare/master...feature/sum-operation?expand=1"})
 				return exec.Command("echo")
 			},
 			func(err error) {
@@ -134,7 +181,7 @@ func TestCreatePullRequest(t *testing.T) {
 				Name: "feature/divide-operation",
 			},
 			func(cmd string, args ...string) *exec.Cmd {
-				return exec.Command("echo", "git@something.com:peter/calculator.git")
+				return pullRequestGitCommand(args, "git@something.com:peter/calculator.git")
 			},
 			func(err error) {
 				assert.Error(t, err)
@@ -152,3 +199,57 @@ func TestCreatePullRequest(t *testing.T) {
 		})
 	}
 }
+
+// TestPushAndCreatePullRequest is a function.
+func TestPushAndCreatePullRequest(t *testing.T) {
+	type scenario struct {
+		testName       string
+		pushErr        error
+		expectedEvents []string
+		test           func(err error)
+	}
+
+	scenarios := []scenario{
+		{
+			"Opens the link after a successful push",
+			nil,
+			[]string{"push", "git", "git", "git", "git", "git", "git", "git", "open"},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"Does not open the link if the push fails",
+			errors.New("push failed"),
+			[]string{"push"},
+			func(err error) {
+				assert.EqualError(t, err, "push failed")
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			events := []string{}
+			gitCommand := NewDummyGitCommand()
+			gitCommand.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				events = append(events, cmd)
+				if cmd == "git" {
+					return pullRequestGitCommand(args, "git@github.com:peter/calculator.git")
+				}
+
+				assert.Equal(t, []string{"https://github.com/peter/calculator/compare/master...feature/sum-operation?expand=1"}, args)
+				return exec.Command("echo")
+			}
+			gitCommand.OSCommand.Config.GetUserConfig().Set("os.openLinkCommand", "open {{link}}")
+			dummyPullRequest := NewPullRequest(gitCommand)
+
+			push := func() error {
+				events = append(events, "push")
+				return s.pushErr
+			}
+			s.test(dummyPullRequest.PushAndCreate(&Branch{Name: "feature/sum-operation"}, push))
+			assert.EqualValues(t, s.expectedEvents, events)
+		})
+	}
+}
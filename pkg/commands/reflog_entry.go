@@ -0,0 +1,18 @@
+package commands
+
+import "github.com/fatih/color"
+
+// ReflogEntry : A git reflog entry
+type ReflogEntry struct {
+	Sha         string
+	Date        string
+	Action      string // e.g. 'commit', 'checkout', 'rebase -i (finish)'
+	Description string
+}
+
+// GetDisplayStrings returns the display strings of a reflog entry
+func (r *ReflogEntry) GetDisplayStrings(isFocused bool) []string {
+	yellow := color.New(color.FgYellow)
+	blue := color.New(color.FgBlue)
+	return []string{yellow.Sprint(r.Sha), blue.Sprint(r.Date), r.Action + ": " + r.Description}
+}
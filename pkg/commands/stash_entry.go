@@ -5,6 +5,7 @@ type StashEntry struct {
 	Index         int
 	Name          string
 	DisplayString string
+	Branch        string // the branch the stash was created on, if we could tell
 }
 
 // GetDisplayStrings returns the display string of branch
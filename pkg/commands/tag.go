@@ -0,0 +1,14 @@
+package commands
+
+import "github.com/fatih/color"
+
+// Tag : A git tag
+type Tag struct {
+	Name    string
+	Message string // the subject of the annotation, empty for lightweight tags
+}
+
+// GetDisplayStrings returns the display strings of a tag
+func (t *Tag) GetDisplayStrings(isFocused bool) []string {
+	return []string{color.New(color.FgYellow).Sprint(t.Name), t.Message}
+}
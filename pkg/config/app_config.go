@@ -242,6 +242,7 @@ func GetDefaultConfig() []byte {
   ## stuff relating to the UI
   scrollHeight: 2
   scrollPastBottom: true
+  branchGraphPageSize: 100
   mouseEvents: true
   theme:
     lightTheme: false
@@ -259,6 +260,19 @@ git:
     manualCommit: false
   skipHookPrefix: 'WIP'
   autoFetch: true
+  fetch:
+    tags: true
+  commit:
+    bodyWrap: 72 # set to 0 to disable wrapping
+    blankLineAfterSubject: true
+  largeFileWarningSize: 50 # in megabytes, set to 0 to disable the warning
+  detectRenames: true
+  showIncomingBeforePull: false
+  useSwitch: false
+  submoduleDiffFormat: short
+  diffAlgorithm: myers
+  protectedBranches: []
+  cherryPickViaRebase: false
 update:
   method: prompt # can be: prompt | background | never
   days: 14 # how often a update is checked for
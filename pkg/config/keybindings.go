@@ -0,0 +1,18 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// GetKeybinding returns the key the user has mapped to the given action in the
+// keybinding section of their config, or defaultKey if they haven't remapped
+// it. Actions are named like 'files.commitChanges'
+func GetKeybinding(userConfig *viper.Viper, action string, defaultKey string) string {
+	key := strings.TrimSpace(userConfig.GetString("keybinding." + action))
+	if key == "" {
+		return defaultKey
+	}
+	return key
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetKeybinding is a function.
+func TestGetKeybinding(t *testing.T) {
+	userConfig := viper.New()
+	userConfig.SetConfigType("yaml")
+	err := userConfig.ReadConfig(bytes.NewBufferString(`
+keybinding:
+  universal:
+    push: 'U'
+  files:
+    commitChanges: 'ctrl+o'
+`))
+	assert.NoError(t, err)
+
+	type scenario struct {
+		testName   string
+		action     string
+		defaultKey string
+		expected   string
+	}
+
+	scenarios := []scenario{
+		{
+			"remapped universal action",
+			"universal.push",
+			"P",
+			"U",
+		},
+		{
+			"remapped panel action",
+			"files.commitChanges",
+			"c",
+			"ctrl+o",
+		},
+		{
+			"action in a remapped section that wasn't itself remapped",
+			"files.stashAllChanges",
+			"s",
+			"s",
+		},
+		{
+			"action in a section with nothing remapped",
+			"commits.squashDown",
+			"s",
+			"s",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, GetKeybinding(userConfig, s.action, s.defaultKey))
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package gui
+
+import (
+	"strings"
+
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/commands"
+)
+
+// the blame panel lets us move through the lines of blame output in the main
+// view and jump to the commit a line comes from
+
+func (gui *Gui) enterBlamePanel(blame string) error {
+	gui.State.Panels.Blame = &blamePanelState{
+		SelectedLine: 0,
+		Shas:         commands.BlameLineShas(blame),
+	}
+
+	if err := gui.changeContext("blame"); err != nil {
+		return err
+	}
+	mainView := gui.getMainView()
+	if err := gui.switchFocus(gui.g, gui.getCommitFilesView(), mainView); err != nil {
+		return err
+	}
+	return gui.resetOrigin(mainView)
+}
+
+func (gui *Gui) handleBlameNextLine(g *gocui.Gui, v *gocui.View) error {
+	state := gui.State.Panels.Blame
+	if state == nil {
+		return nil
+	}
+	gui.changeSelectedLine(&state.SelectedLine, len(state.Shas), false)
+
+	return gui.focusPoint(0, state.SelectedLine, len(state.Shas), v)
+}
+
+func (gui *Gui) handleBlamePrevLine(g *gocui.Gui, v *gocui.View) error {
+	state := gui.State.Panels.Blame
+	if state == nil {
+		return nil
+	}
+	gui.changeSelectedLine(&state.SelectedLine, len(state.Shas), true)
+
+	return gui.focusPoint(0, state.SelectedLine, len(state.Shas), v)
+}
+
+// handleGoToBlameCommit selects the commit the current line of the blame comes
+// from in the commits panel
+func (gui *Gui) handleGoToBlameCommit(g *gocui.Gui, v *gocui.View) error {
+	state := gui.State.Panels.Blame
+	if state == nil || state.SelectedLine < 0 || state.SelectedLine >= len(state.Shas) {
+		return nil
+	}
+	sha := state.Shas[state.SelectedLine]
+	if sha == "" {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("BlameLineNotCommitted"))
+	}
+
+	// blame abbreviates its shas whereas we hold onto the full ones
+	for i, commit := range gui.State.Commits {
+		if strings.HasPrefix(commit.Sha, sha) {
+			gui.State.Panels.Blame = nil
+			gui.State.Panels.Commits.SelectedLine = i
+			if err := gui.changeContext("normal"); err != nil {
+				return err
+			}
+			if _, err := g.SetViewOnBottom("commitFiles"); err != nil {
+				return err
+			}
+			return gui.switchFocus(g, v, gui.getCommitsView())
+		}
+	}
+
+	return gui.createErrorPanel(g, gui.Tr.SLocalize("BlameCommitNotLoaded"))
+}
+
+func (gui *Gui) handleEscapeBlamePanel(g *gocui.Gui, v *gocui.View) error {
+	gui.State.Panels.Blame = nil
+	if err := gui.changeContext("normal"); err != nil {
+		return err
+	}
+
+	return gui.switchFocus(g, nil, gui.getCommitFilesView())
+}
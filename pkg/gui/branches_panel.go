@@ -59,11 +59,77 @@ func (gui *Gui) handleBranchSelect(g *gocui.Gui, v *gocui.View) error {
 		if strings.Contains(upstream, "no upstream configured for branch") {
 			upstream = gui.Tr.SLocalize("notTrackingRemote")
 		}
-		graph, err := gui.GitCommand.GetBranchGraph(branch.Name)
-		if err != nil && strings.HasPrefix(graph, "fatal: ambiguous argument") {
-			graph = gui.Tr.SLocalize("NoTrackingThisBranch")
+		// we show the commit at the tip of the branch unless the user has asked
+		// for its graph, falling back to the graph if we couldn't resolve the tip
+		content := ""
+		gui.State.Panels.Branches.GraphBranch = ""
+		if !gui.State.Panels.Branches.ShowGraph && branch.CommitSha != "" {
+			commitText, err := gui.GitCommand.Show(branch.CommitSha)
+			if err == nil {
+				gui.getMainView().Title = "Patch"
+				content = commitText
+			}
+		}
+		if content == "" {
+			pageSize := gui.branchGraphPageSize()
+			graph, err := gui.GitCommand.GetBranchGraph(branch.Name, pageSize)
+			if err != nil && strings.HasPrefix(graph, "fatal: ambiguous argument") {
+				graph = gui.Tr.SLocalize("NoTrackingThisBranch")
+			} else if err == nil {
+				gui.State.Panels.Branches.GraphBranch = branch.Name
+				gui.State.Panels.Branches.GraphLoadedCount = pageSize
+			}
+			content = graph
+		}
+		_ = gui.renderString(g, "main", fmt.Sprintf("%s → %s\n\n%s", utils.ColoredString(branch.Name, color.FgGreen), utils.ColoredString(upstream, color.FgRed), content))
+	}()
+	return nil
+}
+
+// handleToggleBranchGraph switches the main view between the details of the
+// commit at the selected branch's tip and the branch's graph
+func (gui *Gui) handleToggleBranchGraph(g *gocui.Gui, v *gocui.View) error {
+	gui.State.Panels.Branches.ShowGraph = !gui.State.Panels.Branches.ShowGraph
+	return gui.handleBranchSelect(g, v)
+}
+
+func (gui *Gui) branchGraphPageSize() int {
+	pageSize := gui.Config.GetUserConfig().GetInt("gui.branchGraphPageSize")
+	if pageSize <= 0 {
+		return 100
+	}
+	return pageSize
+}
+
+// loadMoreBranchGraph appends the next page of the branch graph to the main
+// view, for when the user has scrolled to the bottom of what we've loaded
+func (gui *Gui) loadMoreBranchGraph() error {
+	state := gui.State.Panels.Branches
+	if state.GraphBranch == "" {
+		return nil
+	}
+	branchName := state.GraphBranch
+	skip := state.GraphLoadedCount
+	pageSize := gui.branchGraphPageSize()
+	// we clear this while loading so that scrolling again doesn't load the same page twice
+	state.GraphBranch = ""
+
+	go func() {
+		graph, err := gui.GitCommand.GetBranchGraphFrom(branchName, skip, pageSize)
+		if err != nil || graph == "" {
+			return
 		}
-		_ = gui.renderString(g, "main", fmt.Sprintf("%s → %s\n\n%s", utils.ColoredString(branch.Name, color.FgGreen), utils.ColoredString(upstream, color.FgRed), graph))
+		gui.g.Update(func(g *gocui.Gui) error {
+			// the user may have moved on to another branch, or switched back to its
+			// tip commit, in the meantime
+			if branch := gui.getSelectedBranch(); branch == nil || branch.Name != branchName || (!state.ShowGraph && branch.CommitSha != "") {
+				return nil
+			}
+			state.GraphBranch = branchName
+			state.GraphLoadedCount = skip + pageSize
+			fmt.Fprint(gui.getMainView(), graph)
+			return nil
+		})
 	}()
 	return nil
 }
@@ -139,7 +205,29 @@ func (gui *Gui) handleBranchPress(g *gocui.Gui, v *gocui.View) error {
 		return gui.createErrorPanel(g, gui.Tr.SLocalize("AlreadyCheckedOutBranch"))
 	}
 	branch := gui.getSelectedBranch()
-	return gui.handleCheckoutBranch(branch.Name)
+	if len(gui.State.Files) == 0 {
+		return gui.handleCheckoutBranch(branch.Name)
+	}
+
+	// with a dirty working tree we show how far we are from the branch so that
+	// any conflicts don't come as a surprise
+	diffStat, err := gui.GitCommand.DiffStatAgainstBranch(branch.Name)
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	if strings.TrimSpace(diffStat) == "" {
+		return gui.handleCheckoutBranch(branch.Name)
+	}
+	prompt := gui.Tr.TemplateLocalize(
+		"CheckoutDirtyPrompt",
+		Teml{
+			"branchName": branch.Name,
+			"diffStat":   strings.TrimRight(diffStat, "\n"),
+		},
+	)
+	return gui.createConfirmationPanel(g, v, true, gui.Tr.SLocalize("CheckoutDirtyTitle"), prompt, func(g *gocui.Gui, v *gocui.View) error {
+		return gui.handleCheckoutBranch(branch.Name)
+	}, nil)
 }
 
 func (gui *Gui) handleCreatePullRequestPress(g *gocui.Gui, v *gocui.View) error {
@@ -153,6 +241,32 @@ func (gui *Gui) handleCreatePullRequestPress(g *gocui.Gui, v *gocui.View) error
 	return nil
 }
 
+// handlePushAndCreatePullRequest pushes the checked out branch, setting its
+// upstream if it doesn't have one, and then opens the link to a new pull request
+func (gui *Gui) handlePushAndCreatePullRequest(g *gocui.Gui, v *gocui.View) error {
+	branch := gui.State.Branches[0]
+	upstream := ""
+	if _, pullables := gui.GitCommand.GetCurrentBranchUpstreamDifferenceCount(); pullables == "?" {
+		upstream = "origin " + branch.Name
+	}
+
+	if err := gui.createLoaderPanel(gui.g, v, gui.Tr.SLocalize("PushWait")); err != nil {
+		return err
+	}
+	go func() {
+		unamePassOpened := false
+		pullRequest := commands.NewPullRequest(gui.GitCommand)
+		err := pullRequest.PushAndCreate(branch, func() error {
+			return gui.GitCommand.Push(branch.Name, false, upstream, func(passOrUname string) string {
+				unamePassOpened = true
+				return gui.waitForPassUname(g, v, passOrUname)
+			})
+		})
+		gui.HandleCredentialsPopup(g, unamePassOpened, err)
+	}()
+	return nil
+}
+
 func (gui *Gui) handleGitFetch(g *gocui.Gui, v *gocui.View) error {
 	if err := gui.createLoaderPanel(gui.g, v, gui.Tr.SLocalize("FetchWait")); err != nil {
 		return err
@@ -183,17 +297,13 @@ func (gui *Gui) handleCheckoutBranch(branchName string) error {
 		if strings.Contains(err.Error(), "Please commit your changes or stash them before you switch branch") {
 			// offer to autostash changes
 			return gui.createConfirmationPanel(gui.g, gui.getBranchesView(), true, gui.Tr.SLocalize("AutoStashTitle"), gui.Tr.SLocalize("AutoStashPrompt"), func(g *gocui.Gui, v *gocui.View) error {
-				if err := gui.GitCommand.StashSave(gui.Tr.SLocalize("StashPrefix") + branchName); err != nil {
-					return gui.createErrorPanel(g, err.Error())
+				err := gui.GitCommand.CheckoutCarryingChanges(branchName)
+				// even if reapplying the changes failed we may have checked out the
+				// branch, in which case we select it
+				if currentBranchName, _ := gui.GitCommand.CurrentBranchName(); currentBranchName == branchName {
+					gui.State.Panels.Branches.SelectedLine = 0
 				}
-				if err := gui.GitCommand.Checkout(branchName, false); err != nil {
-					return gui.createErrorPanel(g, err.Error())
-				}
-
-				// checkout successful so we select the new branch
-				gui.State.Panels.Branches.SelectedLine = 0
-
-				if err := gui.GitCommand.StashDo(0, "pop"); err != nil {
+				if err != nil {
 					if err := gui.refreshSidePanels(g); err != nil {
 						return err
 					}
@@ -237,8 +347,139 @@ func (gui *Gui) handleNewBranch(g *gocui.Gui, v *gocui.View) error {
 	return nil
 }
 
+func (gui *Gui) handleRenameBranch(g *gocui.Gui, v *gocui.View) error {
+	selectedBranch := gui.getSelectedBranch()
+	if selectedBranch == nil {
+		return nil
+	}
+	message := gui.Tr.TemplateLocalize(
+		"RenameBranchPrompt",
+		Teml{
+			"branchName": selectedBranch.Name,
+		},
+	)
+	return gui.createPromptPanel(g, v, message, selectedBranch.Name, func(g *gocui.Gui, v *gocui.View) error {
+		newName := gui.trimmedContent(v)
+		if newName == "" || newName == selectedBranch.Name {
+			return nil
+		}
+		var err error
+		if gui.State.Branches[0].Name == selectedBranch.Name {
+			err = gui.GitCommand.RenameCurrentBranch(newName)
+		} else {
+			err = gui.GitCommand.RenameBranch(selectedBranch.Name, newName)
+		}
+		if err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		return gui.refreshSidePanels(g)
+	})
+}
+
+type deleteBranchOption struct {
+	description string
+	handler     func() error
+}
+
+// GetDisplayStrings is a function.
+func (o *deleteBranchOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description}
+}
+
+// handleDeleteBranch deletes the selected branch. If it has an upstream we ask
+// whether to delete the local branch, the remote one, or both
 func (gui *Gui) handleDeleteBranch(g *gocui.Gui, v *gocui.View) error {
-	return gui.deleteBranch(g, v, false)
+	selectedBranch := gui.getSelectedBranch()
+	if selectedBranch == nil {
+		return nil
+	}
+	remote, remoteBranch, hasUpstream := gui.GitCommand.GetUpstreamRemoteAndBranch(selectedBranch.Name)
+	// a branch tracking another local branch has nothing to delete remotely
+	if !hasUpstream || remote == "." {
+		return gui.deleteBranch(g, v, false)
+	}
+
+	teml := Teml{
+		"selectedBranchName": selectedBranch.Name,
+		"upstream":           commands.UpstreamRef(remote, remoteBranch),
+	}
+	options := []*deleteBranchOption{
+		{
+			description: gui.Tr.TemplateLocalize("deleteLocalBranch", teml),
+			handler: func() error {
+				return gui.deleteBranch(g, v, false)
+			},
+		},
+		{
+			description: gui.Tr.TemplateLocalize("deleteRemoteBranch", teml),
+			handler: func() error {
+				return gui.deleteRemoteBranch(g, v, selectedBranch, remote, remoteBranch, false)
+			},
+		},
+		{
+			description: gui.Tr.TemplateLocalize("deleteLocalAndRemoteBranch", teml),
+			handler: func() error {
+				if gui.State.Branches[0].Name == selectedBranch.Name {
+					return gui.createErrorPanel(g, gui.Tr.SLocalize("CantDeleteCheckOutBranch"))
+				}
+				return gui.deleteRemoteBranch(g, v, selectedBranch, remote, remoteBranch, true)
+			},
+		},
+		{
+			description: gui.Tr.SLocalize("cancel"),
+			handler: func() error {
+				return nil
+			},
+		},
+	}
+
+	handleMenuPress := func(index int) error {
+		return options[index].handler()
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("DeleteBranch"), options, len(options), handleMenuPress)
+}
+
+// deleteRemoteBranch deletes the branch's copy on the remote, followed by the
+// local branch itself if andLocal is set
+func (gui *Gui) deleteRemoteBranch(g *gocui.Gui, v *gocui.View, branch *commands.Branch, remote, remoteBranch string, andLocal bool) error {
+	messageID := "DeleteRemoteBranchMessage"
+	if andLocal {
+		messageID = "DeleteLocalAndRemoteBranchMessage"
+	}
+	message := gui.Tr.TemplateLocalize(
+		messageID,
+		Teml{
+			"selectedBranchName": branch.Name,
+			"upstream":           commands.UpstreamRef(remote, remoteBranch),
+		},
+	)
+	return gui.createConfirmationPanel(g, v, true, gui.Tr.SLocalize("DeleteBranch"), message, func(g *gocui.Gui, _ *gocui.View) error {
+		if err := gui.createLoaderPanel(gui.g, v, gui.Tr.SLocalize("DeletingRemoteBranchStatus")); err != nil {
+			return err
+		}
+		go func() {
+			unamePassOpend := false
+			err := gui.GitCommand.DeleteRemoteBranch(remote, remoteBranch, func(passOrUname string) string {
+				unamePassOpend = true
+				return gui.waitForPassUname(g, v, passOrUname)
+			})
+			gui.HandleCredentialsPopup(g, unamePassOpend, err)
+			if err != nil || !andLocal {
+				return
+			}
+			if err := gui.GitCommand.DeleteBranch(branch.Name, false); err != nil {
+				if strings.Contains(err.Error(), "is not fully merged") {
+					_ = gui.deleteNamedBranch(g, v, branch, true)
+					return
+				}
+				_ = gui.createErrorPanel(g, err.Error())
+				return
+			}
+			_ = gui.refreshSidePanels(g)
+		}()
+		return nil
+	}, nil)
 }
 
 func (gui *Gui) handleForceDeleteBranch(g *gocui.Gui, v *gocui.View) error {
@@ -304,6 +545,91 @@ func (gui *Gui) handleMerge(g *gocui.Gui, v *gocui.View) error {
 		}, nil)
 }
 
+func (gui *Gui) handleCompareWithCurrentBranch(g *gocui.Gui, v *gocui.View) error {
+	checkedOutBranch := gui.State.Branches[0].Name
+	selectedBranch := gui.getSelectedBranch().Name
+	if checkedOutBranch == selectedBranch {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("CantCompareBranchWithItself"))
+	}
+
+	files, err := gui.GitCommand.GetDiffFiles(checkedOutBranch, selectedBranch)
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+
+	gui.getMainView().Title = gui.Tr.TemplateLocalize(
+		"FilesChangedTitle",
+		Teml{
+			"checkedOutBranch": checkedOutBranch,
+			"selectedBranch":   selectedBranch,
+		},
+	)
+	if len(files) == 0 {
+		return gui.renderString(g, "main", gui.Tr.SLocalize("NoFilesChanged"))
+	}
+	list, err := utils.RenderList(files, false)
+	if err != nil {
+		return err
+	}
+	return gui.renderString(g, "main", list)
+}
+
+type commitRangeOption struct {
+	description string
+	getCommits  func() ([]*commands.Commit, error)
+}
+
+// GetDisplayStrings is a function.
+func (o *commitRangeOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description}
+}
+
+func (gui *Gui) handleCreateCommitRangeMenu(g *gocui.Gui, v *gocui.View) error {
+	checkedOutBranch := gui.State.Branches[0].Name
+	selectedBranch := gui.getSelectedBranch().Name
+	if checkedOutBranch == selectedBranch {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("CantCompareBranchWithItself"))
+	}
+
+	teml := Teml{
+		"checkedOutBranch": checkedOutBranch,
+		"selectedBranch":   selectedBranch,
+	}
+	options := []*commitRangeOption{
+		{
+			description: gui.Tr.TemplateLocalize("CommitsNotInSelectedBranch", teml),
+			getCommits: func() ([]*commands.Commit, error) {
+				return gui.GitCommand.GetCommitsNotIn(selectedBranch)
+			},
+		},
+		{
+			description: gui.Tr.TemplateLocalize("CommitsNotInCheckedOutBranch", teml),
+			getCommits: func() ([]*commands.Commit, error) {
+				return gui.GitCommand.GetCommitsNotInHead(selectedBranch)
+			},
+		},
+	}
+
+	handleMenuPress := func(index int) error {
+		commits, err := options[index].getCommits()
+		if err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+
+		gui.getMainView().Title = options[index].description
+		if len(commits) == 0 {
+			return gui.renderString(g, "main", gui.Tr.SLocalize("NoCommitsInRange"))
+		}
+		list, err := utils.RenderList(commits, false)
+		if err != nil {
+			return err
+		}
+		return gui.renderString(g, "main", list)
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("CompareCommitsTitle"), options, len(options), handleMenuPress)
+}
+
 func (gui *Gui) handleRebase(g *gocui.Gui, v *gocui.View) error {
 	checkedOutBranch := gui.State.Branches[0].Name
 	selectedBranch := gui.getSelectedBranch().Name
@@ -339,11 +665,11 @@ func (gui *Gui) handleFastForward(g *gocui.Gui, v *gocui.View) error {
 	if branch.Pushables != "0" {
 		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("FwdCommitsToPush"))
 	}
-	upstream := "origin" // hardcoding for now
+	remote, remoteBranch, _ := gui.GitCommand.GetUpstreamRemoteAndBranch(branch.Name)
 	message := gui.Tr.TemplateLocalize(
 		"Fetching",
 		Teml{
-			"from": fmt.Sprintf("%s/%s", upstream, branch.Name),
+			"from": commands.UpstreamRef(remote, remoteBranch),
 			"to":   branch.Name,
 		},
 	)
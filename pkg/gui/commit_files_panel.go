@@ -1,6 +1,8 @@
 package gui
 
 import (
+	"strconv"
+
 	"github.com/go-errors/errors"
 	"github.com/jesseduffield/gocui"
 	"github.com/jesseduffield/lazygit/pkg/commands"
@@ -28,7 +30,7 @@ func (gui *Gui) handleCommitFileSelect(g *gocui.Gui, v *gocui.View) error {
 		return nil
 	}
 
-	gui.getMainView().Title = "Patch"
+	gui.getMainView().Title = gui.withDiffAlgorithm("Patch")
 	gui.State.Panels.LineByLine = nil
 
 	commitFile := gui.getSelectedCommitFile(g)
@@ -43,7 +45,7 @@ func (gui *Gui) handleCommitFileSelect(g *gocui.Gui, v *gocui.View) error {
 	if err := gui.focusPoint(0, gui.State.Panels.CommitFiles.SelectedLine, len(gui.State.CommitFiles), v); err != nil {
 		return err
 	}
-	commitText, err := gui.GitCommand.ShowCommitFile(commitFile.Sha, commitFile.Name, false)
+	commitText, err := gui.GitCommand.ShowCommitFile(commitFile.Sha, commitFile.Name, commitFile.PreviousName, false)
 	if err != nil {
 		return err
 	}
@@ -102,6 +104,34 @@ func (gui *Gui) handleDiscardOldFileChange(g *gocui.Gui, v *gocui.View) error {
 	}, nil)
 }
 
+// handleDiscardFileChangesInRange discards the selected file's changes from the
+// selected commit and the given number of commits before it, in one rebase
+func (gui *Gui) handleDiscardFileChangesInRange(g *gocui.Gui, v *gocui.View) error {
+	if ok, err := gui.validateNormalWorkingTreeState(); !ok {
+		return err
+	}
+
+	fileName := gui.State.CommitFiles[gui.State.Panels.CommitFiles.SelectedLine].Name
+	startIndex := gui.State.Panels.Commits.SelectedLine
+
+	return gui.createPromptPanel(g, v, gui.Tr.SLocalize("DiscardFileChangesInRangePrompt"), "2", func(g *gocui.Gui, promptView *gocui.View) error {
+		count, err := strconv.Atoi(gui.trimmedContent(promptView))
+		if err != nil || count < 1 {
+			return gui.createErrorPanel(g, gui.Tr.SLocalize("InvalidCommitCount"))
+		}
+
+		return gui.WithWaitingStatus(gui.Tr.SLocalize("RebasingStatus"), func() error {
+			if err := gui.GitCommand.DiscardFileChangesInRange(gui.State.Commits, startIndex, startIndex+count-1, fileName); err != nil {
+				if err := gui.handleGenericMergeCommandResult(err); err != nil {
+					return err
+				}
+			}
+
+			return gui.refreshSidePanels(gui.g)
+		})
+	})
+}
+
 func (gui *Gui) refreshCommitFilesView() error {
 	if err := gui.refreshSecondaryPatchPanel(); err != nil {
 		return err
@@ -136,6 +166,40 @@ func (gui *Gui) handleOpenOldCommitFile(g *gocui.Gui, v *gocui.View) error {
 	return gui.openFile(file.Name)
 }
 
+func (gui *Gui) handleShowCommitFileAtRevision(g *gocui.Gui, v *gocui.View) error {
+	commitFile := gui.getSelectedCommitFile(g)
+	if commitFile == nil {
+		return nil
+	}
+
+	return gui.showFileAtRevision(v, commitFile.Name, commitFile.Sha)
+}
+
+func (gui *Gui) handleBlameCommitFile(g *gocui.Gui, v *gocui.View) error {
+	commitFile := gui.getSelectedCommitFile(g)
+	if commitFile == nil {
+		return nil
+	}
+
+	blame, err := gui.GitCommand.Blame(commitFile.Name, commitFile.Sha)
+	if err != nil {
+		return gui.createErrorPanel(gui.g, err.Error())
+	}
+
+	gui.State.SplitMainPanel = false
+	gui.getMainView().Title = gui.Tr.TemplateLocalize(
+		"BlameTitle",
+		Teml{
+			"fileName": commitFile.Name,
+			"rev":      commitFile.Sha,
+		},
+	)
+	if err := gui.renderString(gui.g, "main", blame); err != nil {
+		return err
+	}
+	return gui.enterBlamePanel(blame)
+}
+
 func (gui *Gui) handleToggleFileForPatch(g *gocui.Gui, v *gocui.View) error {
 	if ok, err := gui.validateNormalWorkingTreeState(); !ok {
 		return err
@@ -171,7 +235,7 @@ func (gui *Gui) handleToggleFileForPatch(g *gocui.Gui, v *gocui.View) error {
 func (gui *Gui) startPatchManager() error {
 	diffMap := map[string]string{}
 	for _, commitFile := range gui.State.CommitFiles {
-		commitText, err := gui.GitCommand.ShowCommitFile(commitFile.Sha, commitFile.Name, true)
+		commitText, err := gui.GitCommand.ShowCommitFile(commitFile.Sha, commitFile.Name, "", true)
 		if err != nil {
 			return err
 		}
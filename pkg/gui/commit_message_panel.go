@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/commands"
 )
 
 // runSyncOrAsyncCommand takes the output of a command that may have returned
@@ -30,18 +31,43 @@ func (gui *Gui) handleCommitConfirm(g *gocui.Gui, v *gocui.View) error {
 	if message == "" {
 		return gui.createErrorPanel(g, gui.Tr.SLocalize("CommitWithoutMessageErr"))
 	}
-	flags := ""
 	skipHookPrefix := gui.Config.GetUserConfig().GetString("git.skipHookPrefix")
-	if skipHookPrefix != "" && strings.HasPrefix(message, skipHookPrefix) {
-		flags = "--no-verify"
+	flags := commands.CommitFlags(message, skipHookPrefix, gui.State.SkipHooksNextCommit)
+
+	message = gui.GitCommand.FormatCommitMessage(message)
+	commit := func() (*exec.Cmd, error) {
+		if gui.State.CommitPathspec != "" {
+			pathspecFlags := flags
+			if gui.State.CommitAuthor != "" {
+				pathspecFlags = strings.TrimSpace(flags + " --author=" + gui.OSCommand.Quote(gui.State.CommitAuthor))
+			}
+			return gui.GitCommand.CommitPathspec(message, []string{gui.State.CommitPathspec}, pathspecFlags)
+		}
+		if gui.State.CommitAuthor != "" {
+			return gui.GitCommand.CommitWithAuthor(message, flags, gui.State.CommitAuthor)
+		}
+		return gui.GitCommand.Commit(message, flags)
 	}
-	ok, err := gui.runSyncOrAsyncCommand(gui.GitCommand.Commit(message, flags))
+
+	if gui.State.PushAfterCommit {
+		return gui.commitAndPush(g, v, message, commit)
+	}
+
+	ok, err := gui.runSyncOrAsyncCommand(commit())
 	if err != nil {
 		return err
 	}
 	if !ok {
 		return nil
 	}
+	gui.State.CommitAuthor = ""
+	gui.State.CommitPathspec = ""
+	// the skip hooks toggle only ever applies to a single successful commit
+	gui.State.SkipHooksNextCommit = false
+	gui.renderCommitMessageTitle()
+	if err := gui.GitCommand.ClearCommitDraft(); err != nil {
+		gui.Log.Error(err)
+	}
 
 	v.Clear()
 	_ = v.SetCursor(0, 0)
@@ -51,7 +77,83 @@ func (gui *Gui) handleCommitConfirm(g *gocui.Gui, v *gocui.View) error {
 	return gui.refreshSidePanels(g)
 }
 
+// commitAndPushBranchName returns the name of the checked out branch. Before
+// the branches have loaded we ask git, which also covers a repo with no
+// commits yet
+func (gui *Gui) commitAndPushBranchName() (string, error) {
+	if len(gui.State.Branches) > 0 {
+		return gui.State.Branches[0].Name, nil
+	}
+	return gui.GitCommand.CurrentBranchName()
+}
+
+// commitAndPush commits and then pushes in the background. The message is
+// kept as a draft until the commit succeeds so it isn't lost if, for example,
+// a hook rejects the commit
+func (gui *Gui) commitAndPush(g *gocui.Gui, v *gocui.View, message string, commit func() (*exec.Cmd, error)) error {
+	// we read this here rather than in the goroutine because the branches may
+	// be refreshed in the meantime
+	branchName, err := gui.commitAndPushBranchName()
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	upstream := gui.State.PushUpstream
+	gui.State.PushAfterCommit = false
+	gui.State.PushUpstream = ""
+	if err := gui.GitCommand.SaveCommitDraft(message); err != nil {
+		gui.Log.Error(err)
+	}
+
+	v.Clear()
+	_ = v.SetCursor(0, 0)
+	_ = v.SetOrigin(0, 0)
+	_, _ = g.SetViewOnBottom("commitMessage")
+	filesView := gui.getFilesView()
+	_ = gui.switchFocus(g, v, filesView)
+
+	if err := gui.createLoaderPanel(g, filesView, gui.Tr.SLocalize("CommitAndPushWait")); err != nil {
+		return err
+	}
+	go func() {
+		unamePassOpened := false
+		err := gui.GitCommand.CommitAndPush(
+			func() (*exec.Cmd, error) {
+				sub, err := commit()
+				if err == nil && sub == nil {
+					gui.State.CommitAuthor = ""
+					gui.State.SkipHooksNextCommit = false
+					gui.renderCommitMessageTitle()
+					if err := gui.GitCommand.ClearCommitDraft(); err != nil {
+						gui.Log.Error(err)
+					}
+				}
+				return sub, err
+			},
+			func() error {
+				return gui.GitCommand.Push(branchName, false, upstream, func(passOrUname string) string {
+					unamePassOpened = true
+					return gui.waitForPassUname(g, filesView, passOrUname)
+				})
+			},
+		)
+		gui.HandleCredentialsPopup(g, unamePassOpened, err)
+		if err != nil {
+			// the commit may have gone through even if the push didn't
+			_ = gui.refreshSidePanels(g)
+		}
+	}()
+	return nil
+}
+
 func (gui *Gui) handleCommitClose(g *gocui.Gui, v *gocui.View) error {
+	gui.State.PushAfterCommit = false
+	gui.State.PushUpstream = ""
+	gui.State.CommitPathspec = ""
+	gui.renderCommitMessageTitle()
+	// we keep what's been typed so far in case lazygit is closed before the commit is made
+	if err := gui.GitCommand.SaveCommitDraft(gui.trimmedContent(v)); err != nil {
+		gui.Log.Error(err)
+	}
 	g.SetViewOnBottom("commitMessage")
 	return gui.switchFocus(g, v, gui.getFilesView())
 }
@@ -68,9 +170,84 @@ func (gui *Gui) handleCommitFocused(g *gocui.Gui, v *gocui.View) error {
 			"keyBindConfirm": "enter",
 		},
 	)
+	message += ", " + gui.Tr.TemplateLocalize(
+		"ToggleSkipHooksOption",
+		Teml{
+			"keyBindToggle": "ctrl+n",
+		},
+	)
+	message += ", " + gui.Tr.TemplateLocalize(
+		"SetCommitAuthorOption",
+		Teml{
+			"keyBindAuthor": "ctrl+o",
+		},
+	)
 	return gui.renderString(g, "options", message)
 }
 
+func (gui *Gui) handleToggleSkipHooksNextCommit(g *gocui.Gui, v *gocui.View) error {
+	gui.State.SkipHooksNextCommit = !gui.State.SkipHooksNextCommit
+	gui.renderCommitMessageTitle()
+	return nil
+}
+
+// renderCommitMessageTitle lets the user know in the commit message header
+// whether hooks will be skipped for this commit
+func (gui *Gui) renderCommitMessageTitle() {
+	v := gui.getCommitMessageView()
+	v.Title = gui.Tr.SLocalize("CommitMessage")
+	if gui.State.SkipHooksNextCommit {
+		v.Title += " " + gui.Tr.SLocalize("SkippingHooks")
+	}
+	if gui.State.CommitAuthor != "" {
+		v.Title += " " + gui.Tr.TemplateLocalize(
+			"CommittingAs",
+			Teml{
+				"author": gui.State.CommitAuthor,
+			},
+		)
+	}
+	if gui.State.CommitPathspec != "" {
+		v.Title += " " + gui.Tr.TemplateLocalize(
+			"CommittingPathspec",
+			Teml{
+				"path": gui.State.CommitPathspec,
+			},
+		)
+	}
+}
+
+type authorOption struct {
+	author string
+}
+
+// GetDisplayStrings is a function.
+func (o *authorOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.author}
+}
+
+// handleCreateCommitAuthorMenu lets the user pick the author of the next commit
+// from the people who've committed recently
+func (gui *Gui) handleCreateCommitAuthorMenu(g *gocui.Gui, v *gocui.View) error {
+	authors, err := gui.GitCommand.GetRecentAuthors(100)
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+
+	options := make([]*authorOption, len(authors))
+	for i, author := range authors {
+		options[i] = &authorOption{author: author}
+	}
+
+	handleMenuPress := func(index int) error {
+		gui.State.CommitAuthor = options[index].author
+		gui.renderCommitMessageTitle()
+		return nil
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("CommitAuthorTitle"), options, len(options), handleMenuPress)
+}
+
 func (gui *Gui) getBufferLength(view *gocui.View) string {
 	return " " + strconv.Itoa(strings.Count(view.Buffer(), "")-1) + " "
 }
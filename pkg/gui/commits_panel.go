@@ -3,6 +3,7 @@ package gui
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/go-errors/errors"
@@ -58,7 +59,7 @@ func (gui *Gui) handleCommitSelect(g *gocui.Gui, v *gocui.View) error {
 		return err
 	}
 
-	gui.getMainView().Title = "Patch"
+	gui.getMainView().Title = gui.withDiffAlgorithm("Patch")
 	gui.getSecondaryView().Title = "Custom Patch"
 	gui.State.Panels.LineByLine = nil
 
@@ -76,23 +77,82 @@ func (gui *Gui) handleCommitSelect(g *gocui.Gui, v *gocui.View) error {
 		return nil
 	}
 
-	commitText, err := gui.GitCommand.Show(commit.Sha)
+	commitText, err := gui.showCommit(commit.Sha)
 	if err != nil {
 		return err
 	}
+	if err := gui.renderCommitStat(commit.Sha); err != nil {
+		return err
+	}
 	return gui.renderString(g, "main", commitText)
 }
 
+func (gui *Gui) showCommit(sha string) (string, error) {
+	parent := gui.State.Panels.Commits.MergeParent
+	if parent == 0 {
+		return gui.GitCommand.Show(sha)
+	}
+
+	// commits other than merges are shown the usual way
+	parentCount, err := gui.GitCommand.GetParentCount(sha)
+	if err != nil || parentCount < 2 {
+		return gui.GitCommand.Show(sha)
+	}
+
+	gui.getMainView().Title = gui.Tr.TemplateLocalize(
+		"DiffAgainstParentTitle",
+		Teml{
+			"parent": strconv.Itoa(parent),
+		},
+	)
+	return gui.GitCommand.ShowMergeAgainstParent(sha, parent)
+}
+
+// handleToggleMergeParent cycles between showing merge commits the usual way
+// and showing them against their first or second parent
+func (gui *Gui) handleToggleMergeParent(g *gocui.Gui, v *gocui.View) error {
+	panelState := gui.State.Panels.Commits
+	panelState.MergeParent = (panelState.MergeParent + 1) % 3
+	return gui.handleCommitSelect(g, v)
+}
+
+// renderCommitStat shows e.g. '3 files changed, +40 -12' in the commits panel's title
+func (gui *Gui) renderCommitStat(sha string) error {
+	files, additions, deletions, err := gui.GitCommand.GetCommitStat(sha)
+	if err != nil {
+		return err
+	}
+
+	title := gui.Tr.SLocalize("CommitsTitle")
+	if files > 0 {
+		title += " - " + gui.Tr.TemplateLocalize(
+			"CommitStat",
+			Teml{
+				"files":     strconv.Itoa(files),
+				"additions": strconv.Itoa(additions),
+				"deletions": strconv.Itoa(deletions),
+			},
+		)
+	}
+	gui.getCommitsView().Title = title
+	return nil
+}
+
 func (gui *Gui) refreshCommits(g *gocui.Gui) error {
 	g.Update(func(*gocui.Gui) error {
 		builder, err := commands.NewCommitListBuilder(gui.Log, gui.GitCommand, gui.OSCommand, gui.Tr, gui.State.CherryPickedCommits, gui.State.DiffEntries)
 		if err != nil {
 			return err
 		}
+		builder.ShowFullSha = gui.State.ShowFullSha
+		builder.MergeCommitsMode = gui.State.MergeCommitsMode
 		commits, err := builder.GetCommits()
 		if err != nil {
 			return err
 		}
+		for _, commit := range commits {
+			commit.Marked = gui.State.MarkedCommitShas[commit.Sha]
+		}
 		gui.State.Commits = commits
 
 		gui.refreshSelectedLine(&gui.State.Panels.Commits.SelectedLine, len(gui.State.Commits))
@@ -237,8 +297,12 @@ func (gui *Gui) handleRenameCommit(g *gocui.Gui, v *gocui.View) error {
 	if gui.State.Panels.Commits.SelectedLine != 0 {
 		return gui.createErrorPanel(g, gui.Tr.SLocalize("OnlyRenameTopCommit"))
 	}
-	return gui.createPromptPanel(g, v, gui.Tr.SLocalize("renameCommit"), "", func(g *gocui.Gui, v *gocui.View) error {
-		if err := gui.GitCommand.RenameCommit(v.Buffer()); err != nil {
+	message, err := gui.GitCommand.GetCommitMessage("HEAD")
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	return gui.createPromptPanel(g, v, gui.Tr.SLocalize("renameCommit"), message, func(g *gocui.Gui, v *gocui.View) error {
+		if err := gui.renameHeadCommit(v.Buffer()); err != nil {
 			return gui.createErrorPanel(g, err.Error())
 		}
 		if err := gui.refreshCommits(g); err != nil {
@@ -248,6 +312,15 @@ func (gui *Gui) handleRenameCommit(g *gocui.Gui, v *gocui.View) error {
 	})
 }
 
+// renameHeadCommit rewords the selected commit, which must be HEAD, by amending
+// it rather than starting a rebase
+func (gui *Gui) renameHeadCommit(message string) error {
+	if gui.State.Panels.Commits.SelectedLine != 0 {
+		return errors.New(gui.Tr.SLocalize("OnlyRenameTopCommit"))
+	}
+	return gui.GitCommand.RenameCommit(message)
+}
+
 func (gui *Gui) handleRenameCommitEditor(g *gocui.Gui, v *gocui.View) error {
 	applied, err := gui.handleMidRebaseCommand("reword")
 	if err != nil {
@@ -370,6 +443,185 @@ func (gui *Gui) handleCommitMoveUp(g *gocui.Gui, v *gocui.View) error {
 	})
 }
 
+func (gui *Gui) handleCommitMoveToTop(g *gocui.Gui, v *gocui.View) error {
+	index := gui.State.Panels.Commits.SelectedLine
+	if index == 0 {
+		return nil
+	}
+	if gui.State.Commits[index].Status == "rebasing" {
+		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("CantMoveTodoToEnd"))
+	}
+
+	return gui.WithWaitingStatus(gui.Tr.SLocalize("MovingStatus"), func() error {
+		err := gui.GitCommand.MoveCommitToTop(gui.State.Commits, index)
+		if err == nil {
+			gui.State.Panels.Commits.SelectedLine = 0
+		}
+		return gui.handleGenericMergeCommandResult(err)
+	})
+}
+
+func (gui *Gui) handleCommitMoveToBottom(g *gocui.Gui, v *gocui.View) error {
+	index := gui.State.Panels.Commits.SelectedLine
+	if gui.State.Commits[index].Status == "rebasing" {
+		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("CantMoveTodoToEnd"))
+	}
+
+	return gui.WithWaitingStatus(gui.Tr.SLocalize("MovingStatus"), func() error {
+		err := gui.GitCommand.MoveCommitToBottom(gui.State.Commits, index)
+		if err == nil {
+			gui.State.Panels.Commits.SelectedLine = len(gui.State.Commits) - 2
+		}
+		return gui.handleGenericMergeCommandResult(err)
+	})
+}
+
+// handleMoveCommitsToNewBranch moves the selected commit and every commit above
+// it onto a new branch, leaving the current branch at the selected commit's parent
+func (gui *Gui) handleMoveCommitsToNewBranch(g *gocui.Gui, v *gocui.View) error {
+	index := gui.State.Panels.Commits.SelectedLine
+	for _, commit := range gui.State.Commits[0 : index+1] {
+		if commit.Status == "rebasing" {
+			return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("CantMoveCommitsMidRebase"))
+		}
+	}
+	if len(gui.State.Files) > 0 {
+		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("MustStashToMoveCommits"))
+	}
+
+	count := index + 1
+	message := gui.Tr.TemplateLocalize(
+		"MoveCommitsToNewBranchPrompt",
+		Teml{
+			"count": fmt.Sprintf("%d", count),
+		},
+	)
+	return gui.createPromptPanel(g, v, message, "", func(g *gocui.Gui, v *gocui.View) error {
+		if err := gui.GitCommand.MoveCommitsToNewBranch(gui.trimmedContent(v), count); err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		gui.State.Panels.Commits.SelectedLine = 0
+		return gui.refreshSidePanels(g)
+	})
+}
+
+func (gui *Gui) handleToggleFullSha(g *gocui.Gui, v *gocui.View) error {
+	gui.State.ShowFullSha = !gui.State.ShowFullSha
+	return gui.refreshCommits(g)
+}
+
+// handleCycleMergeCommitsMode cycles between showing all commits, hiding merge
+// commits, and following only the first parent of each merge
+func (gui *Gui) handleCycleMergeCommitsMode(g *gocui.Gui, v *gocui.View) error {
+	gui.State.MergeCommitsMode = (gui.State.MergeCommitsMode + 1) % 3
+	return gui.refreshCommits(g)
+}
+
+// handleRebaseWithExec runs a command on the selected commit and every commit
+// above it, e.g. to re-sign them or check that each one builds
+func (gui *Gui) handleRebaseWithExec(g *gocui.Gui, v *gocui.View) error {
+	if gui.State.WorkingTreeState != "normal" {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("CantExecMidRebase"))
+	}
+
+	index := gui.State.Panels.Commits.SelectedLine
+	if len(gui.State.Commits) <= index+1 {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("CannotRebaseOntoFirstCommit"))
+	}
+	baseSha := gui.State.Commits[index+1].Sha
+
+	return gui.createPromptPanel(g, v, gui.Tr.SLocalize("RebaseExecPrompt"), "", func(g *gocui.Gui, promptView *gocui.View) error {
+		command := gui.trimmedContent(promptView)
+		if command == "" {
+			return nil
+		}
+		return gui.WithWaitingStatus(gui.Tr.SLocalize("RebasingStatus"), func() error {
+			err := gui.GitCommand.RebaseWithExec(baseSha, command)
+			return gui.handleGenericMergeCommandResult(err)
+		})
+	})
+}
+
+// handleRebaseOntoCommit rebases the commits the current branch has on top of
+// the default branch onto the selected commit. The commits panel only lists
+// ancestors of HEAD, so the old base has to come from somewhere else
+func (gui *Gui) handleRebaseOntoCommit(g *gocui.Gui, v *gocui.View) error {
+	if gui.State.WorkingTreeState != "normal" {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("CantRebaseOntoMidRebase"))
+	}
+
+	commit := gui.getSelectedCommit(g)
+	if commit == nil {
+		return nil
+	}
+
+	baseBranch, err := gui.GitCommand.GetDefaultBranch()
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	if len(gui.State.Branches) > 0 && gui.State.Branches[0].Name == baseBranch {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("CantRebaseDefaultBranchOnto"))
+	}
+
+	prompt := gui.Tr.TemplateLocalize(
+		"SureRebaseOntoCommit",
+		Teml{
+			"commit":     commit.Sha,
+			"baseBranch": baseBranch,
+		},
+	)
+	return gui.createConfirmationPanel(g, v, true, gui.Tr.SLocalize("RebasingTitle"), prompt, func(g *gocui.Gui, v *gocui.View) error {
+		return gui.WithWaitingStatus(gui.Tr.SLocalize("RebasingStatus"), func() error {
+			err := gui.GitCommand.RebaseBranchOnto(commit.Sha, baseBranch)
+			return gui.handleGenericMergeCommandResult(err)
+		})
+	}, nil)
+}
+
+func (gui *Gui) handleToggleCommitMarked(g *gocui.Gui, v *gocui.View) error {
+	commit := gui.getSelectedCommit(g)
+	if commit == nil {
+		return nil
+	}
+
+	if gui.State.MarkedCommitShas == nil {
+		gui.State.MarkedCommitShas = map[string]bool{}
+	}
+	if gui.State.MarkedCommitShas[commit.Sha] {
+		delete(gui.State.MarkedCommitShas, commit.Sha)
+	} else {
+		gui.State.MarkedCommitShas[commit.Sha] = true
+	}
+	return gui.refreshCommits(g)
+}
+
+// handleSquashMarkedCommits squashes every marked commit into the oldest one
+func (gui *Gui) handleSquashMarkedCommits(g *gocui.Gui, v *gocui.View) error {
+	markedIndices := []int{}
+	for i, commit := range gui.State.Commits {
+		if !commit.Marked {
+			continue
+		}
+		if commit.Status == "rebasing" {
+			return gui.createErrorPanel(g, gui.Tr.SLocalize("CantSquashMarkedMidRebase"))
+		}
+		markedIndices = append(markedIndices, i)
+	}
+	if len(markedIndices) < 2 {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("MarkAtLeastTwoCommits"))
+	}
+
+	return gui.createConfirmationPanel(g, v, true, gui.Tr.SLocalize("Squash"), gui.Tr.SLocalize("SureSquashMarkedCommits"), func(g *gocui.Gui, v *gocui.View) error {
+		return gui.WithWaitingStatus(gui.Tr.SLocalize("SquashingStatus"), func() error {
+			err := gui.GitCommand.SquashMarkedCommits(gui.State.Commits, markedIndices)
+			if err == nil {
+				gui.State.MarkedCommitShas = nil
+			}
+			return gui.handleGenericMergeCommandResult(err)
+		})
+	}, nil)
+}
+
 func (gui *Gui) handleCommitEdit(g *gocui.Gui, v *gocui.View) error {
 	applied, err := gui.handleMidRebaseCommand("edit")
 	if err != nil {
@@ -416,6 +668,93 @@ func (gui *Gui) handleCommitRevert(g *gocui.Gui, v *gocui.View) error {
 	return gui.refreshCommits(gui.g)
 }
 
+func (gui *Gui) handleRevertHead(g *gocui.Gui, v *gocui.View) error {
+	if len(gui.State.Commits) == 0 {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoCommitsThisBranch"))
+	}
+
+	return gui.createConfirmationPanel(g, v, true, gui.Tr.SLocalize("RevertHeadTitle"), gui.Tr.SLocalize("SureRevertHead"), func(g *gocui.Gui, v *gocui.View) error {
+		if err := gui.GitCommand.RevertHead(); err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		return gui.refreshSidePanels(g)
+	}, nil)
+}
+
+// handleRevertRange reverts every commit from the selected one up to HEAD, e.g.
+// to back out a feature. Conflicts are handled like those of any other revert
+func (gui *Gui) handleRevertRange(g *gocui.Gui, v *gocui.View) error {
+	if gui.State.WorkingTreeState != "normal" {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("CantRevertRangeMidRebase"))
+	}
+
+	index := gui.State.Panels.Commits.SelectedLine
+	if len(gui.State.Commits) <= index+1 {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("CannotRevertRangeFromFirstCommit"))
+	}
+	startSha := gui.State.Commits[index].Sha
+	endSha := gui.State.Commits[0].Sha
+
+	prompt := gui.Tr.TemplateLocalize(
+		"SureRevertRange",
+		Teml{
+			"count": strconv.Itoa(index + 1),
+		},
+	)
+	return gui.createConfirmationPanel(g, v, true, gui.Tr.SLocalize("RevertRangeTitle"), prompt, func(g *gocui.Gui, v *gocui.View) error {
+		return gui.WithWaitingStatus(gui.Tr.SLocalize("RevertingStatus"), func() error {
+			err := gui.GitCommand.RevertRange(startSha, endSha)
+			return gui.handleGenericMergeCommandResult(err)
+		})
+	}, nil)
+}
+
+// handleShowCommitSignature shows the selected commit in the main view with the
+// full output of verifying its signature, rather than just its status
+func (gui *Gui) handleShowCommitSignature(g *gocui.Gui, v *gocui.View) error {
+	commit := gui.getSelectedCommit(g)
+	if commit == nil {
+		return nil
+	}
+
+	output, err := gui.GitCommand.ShowWithSignature(commit.Sha)
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+
+	gui.getMainView().Title = gui.Tr.SLocalize("SignatureTitle")
+	return gui.renderString(g, "main", output)
+}
+
+// handleBisectRun asks for a command with which git can test each commit of the
+// current bisect session, then shows which commit git found to be the first bad one
+func (gui *Gui) handleBisectRun(g *gocui.Gui, v *gocui.View) error {
+	bisecting, err := gui.GitCommand.BisectMode()
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	if !bisecting {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NotBisecting"))
+	}
+
+	return gui.createPromptPanel(g, v, gui.Tr.SLocalize("BisectRunPrompt"), "", func(g *gocui.Gui, promptView *gocui.View) error {
+		command := gui.trimmedContent(promptView)
+		if command == "" {
+			return nil
+		}
+		return gui.WithWaitingStatus(gui.Tr.SLocalize("BisectRunStatus"), func() error {
+			output, err := gui.GitCommand.BisectRun(command)
+			if err != nil {
+				return gui.createErrorPanel(g, err.Error())
+			}
+			if err := gui.refreshSidePanels(g); err != nil {
+				return err
+			}
+			return gui.createMessagePanel(g, gui.getCommitsView(), gui.Tr.SLocalize("BisectResultTitle"), strings.TrimSpace(output))
+		})
+	})
+}
+
 func (gui *Gui) handleCopyCommit(g *gocui.Gui, v *gocui.View) error {
 	// get currently selected commit, add the sha to state.
 	commit := gui.State.Commits[gui.State.Panels.Commits.SelectedLine]
@@ -473,7 +812,43 @@ func (gui *Gui) handleCopyCommitRange(g *gocui.Gui, v *gocui.View) error {
 func (gui *Gui) HandlePasteCommits(g *gocui.Gui, v *gocui.View) error {
 	return gui.createConfirmationPanel(g, v, true, gui.Tr.SLocalize("CherryPick"), gui.Tr.SLocalize("SureCherryPick"), func(g *gocui.Gui, v *gocui.View) error {
 		return gui.WithWaitingStatus(gui.Tr.SLocalize("CherryPickingStatus"), func() error {
-			err := gui.GitCommand.CherryPickCommits(gui.State.CherryPickedCommits)
+			if gui.Config.GetUserConfig().GetBool("git.cherryPickViaRebase") {
+				err := gui.GitCommand.CherryPickCommits(gui.State.CherryPickedCommits)
+				return gui.handleGenericMergeCommandResult(err)
+			}
+			// copied commits are stored newest first but we want to pick the oldest first
+			copiedCommits := gui.State.CherryPickedCommits
+			shas := make([]string, len(copiedCommits))
+			for i, commit := range copiedCommits {
+				shas[len(copiedCommits)-1-i] = commit.Sha
+			}
+			err := gui.GitCommand.CherryPick(shas)
+			return gui.handleGenericMergeCommandResult(err)
+		})
+	}, nil)
+}
+
+// handlePasteCommitRange cherry-picks everything from the oldest to the newest
+// copied commit via `git cherry-pick` rather than an interactive rebase
+func (gui *Gui) handlePasteCommitRange(g *gocui.Gui, v *gocui.View) error {
+	copiedCommits := gui.State.CherryPickedCommits
+	if len(copiedCommits) == 0 {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoCopiedCommits"))
+	}
+	// copied commits are stored newest first
+	startSha := copiedCommits[len(copiedCommits)-1].Sha
+	endSha := copiedCommits[0].Sha
+
+	prompt := gui.Tr.TemplateLocalize(
+		"SureCherryPickRange",
+		Teml{
+			"startSha": startSha,
+			"endSha":   endSha,
+		},
+	)
+	return gui.createConfirmationPanel(g, v, true, gui.Tr.SLocalize("CherryPick"), prompt, func(g *gocui.Gui, v *gocui.View) error {
+		return gui.WithWaitingStatus(gui.Tr.SLocalize("CherryPickingStatus"), func() error {
+			err := gui.GitCommand.CherryPickRange(startSha, endSha)
 			return gui.handleGenericMergeCommandResult(err)
 		})
 	}, nil)
@@ -554,13 +929,84 @@ func (gui *Gui) handleCreateFixupCommit(g *gocui.Gui, v *gocui.View) error {
 		return nil
 	}
 
+	return gui.createFixupCommitForSha(g, v, commit.Sha)
+}
+
+type graphLineOption struct {
+	line string
+	sha  string
+}
+
+// GetDisplayStrings is a function.
+func (o *graphLineOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.line}
+}
+
+// handleCreateFixupCommitFromGraph lets the user pick the target of a fixup
+// commit from the log of all branches, for when the fix belongs to a commit
+// that isn't in the current branch's history
+func (gui *Gui) handleCreateFixupCommitFromGraph(g *gocui.Gui, v *gocui.View) error {
+	graph, err := gui.GitCommand.GetAllBranchesGraph()
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+
+	options := []*graphLineOption{}
+	for _, line := range utils.SplitLines(graph) {
+		options = append(options, &graphLineOption{line: line, sha: commands.GraphLineSha(line)})
+	}
+
+	handleMenuPress := func(index int) error {
+		sha := options[index].sha
+		if sha == "" {
+			return gui.createErrorPanel(g, gui.Tr.SLocalize("NoCommitOnGraphLine"))
+		}
+		return gui.createFixupCommitForSha(g, v, sha)
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("FixupTargetFromGraphTitle"), options, len(options), handleMenuPress)
+}
+
+func (gui *Gui) createFixupCommitForSha(g *gocui.Gui, v *gocui.View, sha string) error {
+	// show the target commit so the user can be sure they're fixing up the right one
+	preview, createFixupCommit, err := gui.GitCommand.PrepareFixupCommit(sha)
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	gui.getMainView().Title = gui.Tr.SLocalize("FixupTargetTitle")
+	if err := gui.renderString(g, "main", preview); err != nil {
+		return err
+	}
+
 	return gui.createConfirmationPanel(g, v, true, gui.Tr.SLocalize("CreateFixupCommit"), gui.Tr.TemplateLocalize(
 		"SureCreateFixupCommit",
+		Teml{
+			"commit": sha,
+		},
+	), func(g *gocui.Gui, v *gocui.View) error {
+		if err := createFixupCommit(); err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+
+		return gui.refreshSidePanels(gui.g)
+	}, nil)
+}
+
+// handleCreateSquashCommit is like handleCreateFixupCommit except the new
+// commit's message is kept when it's squashed into the selected one
+func (gui *Gui) handleCreateSquashCommit(g *gocui.Gui, v *gocui.View) error {
+	commit := gui.getSelectedCommit(g)
+	if commit == nil {
+		return nil
+	}
+
+	return gui.createConfirmationPanel(g, v, true, gui.Tr.SLocalize("CreateSquashCommit"), gui.Tr.TemplateLocalize(
+		"SureCreateSquashCommit",
 		Teml{
 			"commit": commit.Sha,
 		},
 	), func(g *gocui.Gui, v *gocui.View) error {
-		if err := gui.GitCommand.CreateFixupCommit(commit.Sha); err != nil {
+		if err := gui.GitCommand.CreateSquashCommit(commit.Sha); err != nil {
 			return gui.createErrorPanel(g, err.Error())
 		}
 
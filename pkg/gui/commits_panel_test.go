@@ -0,0 +1,69 @@
+package gui
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/commands"
+	"github.com/jesseduffield/lazygit/pkg/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRenameHeadCommit is a function.
+func TestRenameHeadCommit(t *testing.T) {
+	type scenario struct {
+		testName     string
+		selectedLine int
+		expectedArgs [][]string
+		test         func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"HEAD is amended without a rebase",
+			0,
+			[][]string{{"commit", "--allow-empty", "--amend", "-m", "new message"}},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"older commits can't be renamed inline",
+			1,
+			[][]string{},
+			func(err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			args := [][]string{}
+			osCommand := commands.NewDummyOSCommand()
+			osCommand.SetCommand(func(cmd string, cmdArgs ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.NotEqual(t, "rebase", cmdArgs[0])
+				args = append(args, cmdArgs)
+
+				return exec.Command("echo")
+			})
+
+			gui := &Gui{
+				Log:        commands.NewDummyLog(),
+				GitCommand: commands.NewDummyGitCommandWithOSCommand(osCommand),
+				Tr:         i18n.NewLocalizer(commands.NewDummyLog()),
+				State: guiState{
+					Commits: []*commands.Commit{
+						{Sha: "abc123", Name: "HEAD commit", Status: "unpushed"},
+						{Sha: "def456", Name: "older commit", Status: "pushed"},
+					},
+					Panels: &panelStates{Commits: &commitPanelState{SelectedLine: s.selectedLine}},
+				},
+			}
+
+			s.test(gui.renameHeadCommit("new message"))
+			assert.EqualValues(t, s.expectedArgs, args)
+		})
+	}
+}
@@ -1,13 +1,14 @@
 package gui
 
 import (
-
 	// "io"
 	// "io/ioutil"
 
 	// "strings"
 
 	"fmt"
+	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/fatih/color"
@@ -71,37 +72,39 @@ func (gui *Gui) handleFileSelect(g *gocui.Gui, v *gocui.View, alreadySelected bo
 		return gui.refreshMergePanel()
 	}
 
-	content := gui.GitCommand.Diff(file, false, false)
-	contentCached := gui.GitCommand.Diff(file, false, true)
-	leftContent := content
-	if file.HasStagedChanges && file.HasUnstagedChanges {
-		gui.State.SplitMainPanel = true
-		gui.getMainView().Title = gui.Tr.SLocalize("UnstagedChanges")
-		gui.getSecondaryView().Title = gui.Tr.SLocalize("StagedChanges")
-	} else {
-		gui.State.SplitMainPanel = false
-		if file.HasUnstagedChanges {
-			leftContent = content
-			gui.getMainView().Title = gui.Tr.SLocalize("UnstagedChanges")
-		} else {
-			leftContent = contentCached
-			gui.getMainView().Title = gui.Tr.SLocalize("StagedChanges")
-		}
+	mainCached, secondaryCached, split := file.DiffPanels()
+	mainContent := gui.GitCommand.Diff(file, false, mainCached)
+	if commands.IsBinaryDiff(mainContent) {
+		mainContent += "\n" + gui.Tr.SLocalize("BinaryFileStaging")
+	}
+	secondaryContent := ""
+	gui.State.SplitMainPanel = split
+	gui.getMainView().Title = gui.diffTitle(mainCached)
+	if split {
+		secondaryContent = gui.GitCommand.Diff(file, false, secondaryCached)
+		gui.getSecondaryView().Title = gui.diffTitle(secondaryCached)
 	}
 
 	if alreadySelected {
 		g.Update(func(*gocui.Gui) error {
-			if err := gui.setViewContent(gui.g, gui.getSecondaryView(), contentCached); err != nil {
+			if err := gui.setViewContent(gui.g, gui.getSecondaryView(), secondaryContent); err != nil {
 				return err
 			}
-			return gui.setViewContent(gui.g, gui.getMainView(), leftContent)
+			return gui.setViewContent(gui.g, gui.getMainView(), mainContent)
 		})
 		return nil
 	}
-	if err := gui.renderString(g, "secondary", contentCached); err != nil {
+	if err := gui.renderString(g, "secondary", secondaryContent); err != nil {
 		return err
 	}
-	return gui.renderString(g, "main", leftContent)
+	return gui.renderString(g, "main", mainContent)
+}
+
+func (gui *Gui) diffTitle(cached bool) string {
+	if cached {
+		return gui.withDiffAlgorithm(gui.Tr.SLocalize("StagedChanges"))
+	}
+	return gui.withDiffAlgorithm(gui.Tr.SLocalize("UnstagedChanges"))
 }
 
 func (gui *Gui) refreshFiles() error {
@@ -155,6 +158,27 @@ func (gui *Gui) handleFilesNextLine(g *gocui.Gui, v *gocui.View) error {
 	return gui.handleFileSelect(gui.g, v, false)
 }
 
+// handleNextConflictedFile jumps to the next file with merge conflicts, wrapping
+// around to the top of the list, so that conflicts can be worked through
+// without hunting for them among the other changes
+func (gui *Gui) handleNextConflictedFile(g *gocui.Gui, v *gocui.View) error {
+	files := gui.State.Files
+	if len(commands.ConflictedFiles(files)) == 0 {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoConflictedFiles"))
+	}
+
+	selectedLine := gui.State.Panels.Files.SelectedLine
+	for offset := 1; offset <= len(files); offset++ {
+		index := (selectedLine + offset) % len(files)
+		if files[index].HasMergeConflicts {
+			gui.State.Panels.Files.SelectedLine = index
+			break
+		}
+	}
+
+	return gui.handleFileSelect(g, v, false)
+}
+
 func (gui *Gui) handleFilesPrevLine(g *gocui.Gui, v *gocui.View) error {
 	if gui.popupPanelFocused() {
 		return nil
@@ -216,6 +240,11 @@ func (gui *Gui) enterFile(forceSecondaryFocused bool, selectedLineIdx int) error
 	if file.HasMergeConflicts {
 		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("FileStagingRequirements"))
 	}
+	// binary files can only be staged as a whole, so there's nothing for the
+	// staging panel to show
+	if commands.IsBinaryDiff(gui.GitCommand.Diff(file, true, !file.HasUnstagedChanges)) {
+		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("BinaryFileStaging"))
+	}
 	if err := gui.changeContext("staging"); err != nil {
 		return err
 	}
@@ -226,6 +255,21 @@ func (gui *Gui) enterFile(forceSecondaryFocused bool, selectedLineIdx int) error
 }
 
 func (gui *Gui) handleFilePress(g *gocui.Gui, v *gocui.View) error {
+	return gui.handleFileStagingKey(g, v, stagingKeyToggle)
+}
+
+// handleStageFile always stages the selected file, unlike handleFilePress which
+// toggles, so a partially staged file doesn't get unstaged by accident
+func (gui *Gui) handleStageFile(g *gocui.Gui, v *gocui.View) error {
+	return gui.handleFileStagingKey(g, v, stagingKeyStage)
+}
+
+// handleUnstageFile always unstages the selected file
+func (gui *Gui) handleUnstageFile(g *gocui.Gui, v *gocui.View) error {
+	return gui.handleFileStagingKey(g, v, stagingKeyUnstage)
+}
+
+func (gui *Gui) handleFileStagingKey(g *gocui.Gui, v *gocui.View, key stagingKey) error {
 	file, err := gui.getSelectedFile(g)
 	if err != nil {
 		if err == gui.Errors.ErrNoFiles {
@@ -234,16 +278,100 @@ func (gui *Gui) handleFilePress(g *gocui.Gui, v *gocui.View) error {
 		return err
 	}
 
-	if file.HasInlineMergeConflicts {
+	switch fileStagingAction(file, key) {
+	case stagingActionMerge:
 		return gui.handleSwitchToMerge(g, v)
+	case stagingActionStage:
+		return gui.stageFile(g, v, file)
+	case stagingActionUnstage:
+		return gui.unstageFile(g, v, file)
+	}
+	return nil
+}
+
+type stagingKey int
+
+const (
+	stagingKeyToggle stagingKey = iota
+	stagingKeyStage
+	stagingKeyUnstage
+)
+
+type stagingAction int
+
+const (
+	stagingActionNone stagingAction = iota
+	stagingActionStage
+	stagingActionUnstage
+	stagingActionMerge
+)
+
+// fileStagingAction decides what the given staging key does to a file. The
+// toggle key unstages a file once it has nothing left to stage, while the
+// explicit keys only go one way and do nothing if there's nothing to move.
+// Whether the file is tracked only matters once we come to unstage it
+func fileStagingAction(file *commands.File, key stagingKey) stagingAction {
+	switch key {
+	case stagingKeyUnstage:
+		if !file.HasStagedChanges {
+			return stagingActionNone
+		}
+		return stagingActionUnstage
+	case stagingKeyStage:
+		if file.HasInlineMergeConflicts {
+			return stagingActionMerge
+		}
+		if !file.HasUnstagedChanges {
+			return stagingActionNone
+		}
+		return stagingActionStage
 	}
 
-	if file.HasUnstagedChanges {
+	if file.HasInlineMergeConflicts {
+		return stagingActionMerge
+	}
+	if !file.HasUnstagedChanges {
+		return stagingActionUnstage
+	}
+	return stagingActionStage
+}
+
+func (gui *Gui) unstageFile(g *gocui.Gui, v *gocui.View, file *commands.File) error {
+	gui.GitCommand.UnStageFile(file.Name, file.Tracked)
+	return gui.refreshFilesAfterStaging(g, v)
+}
+
+func (gui *Gui) stageFile(g *gocui.Gui, v *gocui.View, file *commands.File) error {
+	stage := func(g *gocui.Gui, v *gocui.View) error {
 		gui.GitCommand.StageFile(file.Name)
-	} else {
-		gui.GitCommand.UnStageFile(file.Name, file.Tracked)
+		return gui.refreshFilesAfterStaging(g, v)
 	}
 
+	return gui.confirmStagingLargeFiles(g, v, []string{file.Name}, stage)
+}
+
+// confirmStagingLargeFiles runs stage straight away unless some of the files
+// are bigger than git.largeFileWarningSize, in which case we ask first
+func (gui *Gui) confirmStagingLargeFiles(g *gocui.Gui, v *gocui.View, fileNames []string, stage func(*gocui.Gui, *gocui.View) error) error {
+	largeFiles, err := gui.GitCommand.LargeFiles(fileNames)
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	if len(largeFiles) == 0 {
+		return stage(g, v)
+	}
+
+	prompt := gui.Tr.TemplateLocalize(
+		"LargeFilePrompt",
+		Teml{
+			"file":  strings.Join(largeFiles, ", "),
+			"limit": fmt.Sprintf("%dMB", gui.Config.GetUserConfig().GetInt("git.largeFileWarningSize")),
+		},
+	)
+	return gui.createConfirmationPanel(g, v, true, gui.Tr.SLocalize("LargeFileTitle"), prompt, stage, nil)
+}
+
+func (gui *Gui) refreshFilesAfterStaging(g *gocui.Gui, v *gocui.View) error {
 	if err := gui.refreshFiles(); err != nil {
 		return err
 	}
@@ -261,13 +389,23 @@ func (gui *Gui) allFilesStaged() bool {
 }
 
 func (gui *Gui) handleStageAll(g *gocui.Gui, v *gocui.View) error {
-	var err error
 	if gui.allFilesStaged() {
-		err = gui.GitCommand.UnstageAll()
-	} else {
-		err = gui.GitCommand.StageAll()
+		return gui.stageOrUnstageAll(g, v, gui.GitCommand.UnstageAll)
 	}
-	if err != nil {
+
+	unstagedFileNames := []string{}
+	for _, file := range gui.State.Files {
+		if file.HasUnstagedChanges {
+			unstagedFileNames = append(unstagedFileNames, file.Name)
+		}
+	}
+	return gui.confirmStagingLargeFiles(g, v, unstagedFileNames, func(g *gocui.Gui, _ *gocui.View) error {
+		return gui.stageOrUnstageAll(g, v, gui.GitCommand.StageAll)
+	})
+}
+
+func (gui *Gui) stageOrUnstageAll(g *gocui.Gui, v *gocui.View, run func() error) error {
+	if err := run(); err != nil {
 		_ = gui.createErrorPanel(g, err.Error())
 	}
 
@@ -278,6 +416,29 @@ func (gui *Gui) handleStageAll(g *gocui.Gui, v *gocui.View) error {
 	return gui.handleFileSelect(g, v, false)
 }
 
+// handleMoveFile renames or moves the selected file with git mv, so that the
+// rename is staged straight away
+func (gui *Gui) handleMoveFile(g *gocui.Gui, v *gocui.View) error {
+	file, err := gui.getSelectedFile(g)
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	// in case of a renamed file we want the new name
+	split := strings.Split(file.Name, " -> ")
+	from := split[len(split)-1]
+
+	return gui.createPromptPanel(g, v, gui.Tr.SLocalize("MoveFilePrompt"), from, func(g *gocui.Gui, promptView *gocui.View) error {
+		to := gui.trimmedContent(promptView)
+		if to == "" || to == from {
+			return nil
+		}
+		if err := gui.GitCommand.MoveFile(from, to); err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		return gui.refreshFiles()
+	})
+}
+
 func (gui *Gui) handleIgnoreFile(g *gocui.Gui, v *gocui.View) error {
 	file, err := gui.getSelectedFile(g)
 	if err != nil {
@@ -292,6 +453,31 @@ func (gui *Gui) handleIgnoreFile(g *gocui.Gui, v *gocui.View) error {
 	return gui.refreshFiles()
 }
 
+// handleCheckIgnore asks for a path and tells the user which gitignore rule,
+// if any, matches it. Ignored files never show up in the files panel, so we
+// start from the selected file's path for the user to edit
+func (gui *Gui) handleCheckIgnore(g *gocui.Gui, v *gocui.View) error {
+	initialPath := ""
+	if file, err := gui.getSelectedFile(g); err == nil {
+		initialPath = file.Name
+	}
+
+	return gui.createPromptPanel(g, v, gui.Tr.SLocalize("CheckIgnorePrompt"), initialPath, func(g *gocui.Gui, promptView *gocui.View) error {
+		path := gui.trimmedContent(promptView)
+		if path == "" {
+			return gui.createErrorPanel(g, gui.Tr.SLocalize("EmptyPath"))
+		}
+		rule, err := gui.GitCommand.CheckIgnore(path)
+		if err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		if rule == "" {
+			rule = gui.Tr.SLocalize("PathNotIgnored")
+		}
+		return gui.createMessagePanel(g, v, gui.Tr.SLocalize("CheckIgnoreTitle"), rule)
+	})
+}
+
 func (gui *Gui) handleWIPCommitPress(g *gocui.Gui, filesView *gocui.View) error {
 	skipHookPreifx := gui.Config.GetUserConfig().GetString("git.skipHookPrefix")
 	if skipHookPreifx == "" {
@@ -313,6 +499,16 @@ func (gui *Gui) handleCommitPress(g *gocui.Gui, filesView *gocui.View) error {
 		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoStagedFilesToCommit"))
 	}
 	commitMessageView := gui.getCommitMessageView()
+	if gui.trimmedContent(commitMessageView) == "" {
+		draft, err := gui.GitCommand.LoadCommitDraft()
+		if err != nil {
+			gui.Log.Error(err)
+		}
+		if draft != "" {
+			commitMessageView.Clear()
+			fmt.Fprint(commitMessageView, draft)
+		}
+	}
 	g.Update(func(g *gocui.Gui) error {
 		g.SetViewOnTop("commitMessage")
 		gui.switchFocus(g, filesView, commitMessageView)
@@ -322,7 +518,141 @@ func (gui *Gui) handleCommitPress(g *gocui.Gui, filesView *gocui.View) error {
 	return nil
 }
 
+// handleCommitAndPushPress opens the commit message panel as usual but pushes
+// once the commit is made. If the branch has no upstream we ask for one first
+func (gui *Gui) handleCommitAndPushPress(g *gocui.Gui, filesView *gocui.View) error {
+	if len(gui.stagedFiles()) == 0 && gui.State.WorkingTreeState == "normal" {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoStagedFilesToCommit"))
+	}
+
+	_, pullables := gui.GitCommand.GetCurrentBranchUpstreamDifferenceCount()
+	if pullables != "?" {
+		gui.State.PushAfterCommit = true
+		return gui.handleCommitPress(g, filesView)
+	}
+
+	currentBranchName, err := gui.GitCommand.CurrentBranchName()
+	if err != nil {
+		return err
+	}
+	return gui.createPromptPanel(g, filesView, gui.Tr.SLocalize("EnterUpstream"), "origin "+currentBranchName, func(g *gocui.Gui, v *gocui.View) error {
+		gui.State.PushAfterCommit = true
+		gui.State.PushUpstream = gui.trimmedContent(v)
+		return gui.handleCommitPress(g, filesView)
+	})
+}
+
+// handleCommitPathspecPress opens the commit message panel for a commit of only
+// the changes under a given path, e.g. one package of a monorepo
+func (gui *Gui) handleCommitPathspecPress(g *gocui.Gui, filesView *gocui.View) error {
+	if len(gui.stagedFiles()) == 0 && gui.State.WorkingTreeState == "normal" {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoStagedFilesToCommit"))
+	}
+
+	initialPath := ""
+	if file, err := gui.getSelectedFile(g); err == nil {
+		initialPath = filepath.Dir(file.Name)
+	}
+
+	return gui.createPromptPanel(g, filesView, gui.Tr.SLocalize("EnterCommitPathspec"), initialPath, func(g *gocui.Gui, v *gocui.View) error {
+		path := gui.trimmedContent(v)
+		if path == "" {
+			return gui.createErrorPanel(g, gui.Tr.SLocalize("EmptyPattern"))
+		}
+		gui.State.CommitPathspec = path
+		gui.renderCommitMessageTitle()
+		return gui.handleCommitPress(g, filesView)
+	})
+}
+
+type stagedHunkOption struct {
+	header string
+}
+
+// GetDisplayStrings is a function.
+func (o *stagedHunkOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.header}
+}
+
+// handleCreateUnstageHunkMenu lists the staged hunks of the selected file so
+// that one can be unstaged without going into the staging panel
+func (gui *Gui) handleCreateUnstageHunkMenu(g *gocui.Gui, v *gocui.View) error {
+	file, err := gui.getSelectedFile(g)
+	if err != nil {
+		if err != gui.Errors.ErrNoFiles {
+			return err
+		}
+		return nil
+	}
+	if !file.HasStagedChanges {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("FileHasNoStagedChanges"))
+	}
+
+	diff := gui.GitCommand.Diff(file, true, true)
+	diffLines := strings.Split(diff, "\n")
+	hunks := commands.GetHunksFromDiff(diff)
+	if len(hunks) == 0 {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("FileHasNoStagedChanges"))
+	}
+
+	options := make([]*stagedHunkOption, len(hunks))
+	for i, hunk := range hunks {
+		options[i] = &stagedHunkOption{header: diffLines[hunk.FirstLineIdx]}
+	}
+
+	handleMenuPress := func(index int) error {
+		if err := gui.GitCommand.UnstageHunk(file, index); err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		return gui.refreshFiles()
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("UnstageHunkTitle"), options, len(options), handleMenuPress)
+}
+
+// handleShowStagedDiff shows everything that's staged in one go, for a last
+// look before committing
+func (gui *Gui) handleShowStagedDiff(g *gocui.Gui, v *gocui.View) error {
+	diff, err := gui.GitCommand.GetStagedDiff()
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	if diff == "" {
+		diff = gui.Tr.SLocalize("NoStagedChanges")
+	}
+
+	gui.State.SplitMainPanel = false
+	gui.getMainView().Title = gui.Tr.SLocalize("StagedDiffTitle")
+	return gui.renderString(g, "main", diff)
+}
+
+func (gui *Gui) handleStageGlob(g *gocui.Gui, v *gocui.View) error {
+	return gui.createPromptPanel(g, v, gui.Tr.SLocalize("StageGlobPrompt"), "", func(g *gocui.Gui, promptView *gocui.View) error {
+		pattern := gui.trimmedContent(promptView)
+		fileNames, err := gui.GitCommand.FilesMatchingGlob(pattern)
+		if err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		return gui.confirmStagingLargeFiles(g, v, fileNames, func(g *gocui.Gui, _ *gocui.View) error {
+			if err := gui.GitCommand.StageGlob(pattern); err != nil {
+				return gui.createErrorPanel(g, err.Error())
+			}
+			return gui.refreshFiles()
+		})
+	})
+}
+
 func (gui *Gui) handleAmendCommitPress(g *gocui.Gui, filesView *gocui.View) error {
+	return gui.amendCommit(g, filesView, gui.GitCommand.AmendHead)
+}
+
+// handleAmendCommitKeepDatePress amends the last commit without touching its
+// dates
+func (gui *Gui) handleAmendCommitKeepDatePress(g *gocui.Gui, filesView *gocui.View) error {
+	return gui.amendCommit(g, filesView, gui.GitCommand.AmendHeadKeepDate)
+}
+
+func (gui *Gui) amendCommit(g *gocui.Gui, filesView *gocui.View, amend func() (*exec.Cmd, error)) error {
 	if len(gui.stagedFiles()) == 0 && gui.State.WorkingTreeState == "normal" {
 		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoStagedFilesToCommit"))
 	}
@@ -334,7 +664,122 @@ func (gui *Gui) handleAmendCommitPress(g *gocui.Gui, filesView *gocui.View) erro
 	question := gui.Tr.SLocalize("SureToAmend")
 
 	return gui.createConfirmationPanel(g, filesView, true, title, question, func(g *gocui.Gui, v *gocui.View) error {
-		ok, err := gui.runSyncOrAsyncCommand(gui.GitCommand.AmendHead())
+		ok, err := gui.runSyncOrAsyncCommand(amend())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+
+		return gui.refreshSidePanels(g)
+	}, nil)
+}
+
+func (gui *Gui) handleToggleFileMarked(g *gocui.Gui, v *gocui.View) error {
+	file, err := gui.getSelectedFile(g)
+	if err != nil {
+		if err != gui.Errors.ErrNoFiles {
+			return err
+		}
+		return nil
+	}
+
+	if gui.State.MarkedFileNames == nil {
+		gui.State.MarkedFileNames = map[string]bool{}
+	}
+	if gui.State.MarkedFileNames[file.Name] {
+		delete(gui.State.MarkedFileNames, file.Name)
+	} else {
+		gui.State.MarkedFileNames[file.Name] = true
+	}
+	return gui.refreshFiles()
+}
+
+// handleUnstageFiles unstages the marked files, or the selected file if none
+// are marked, keeping their changes in the working tree
+func (gui *Gui) handleUnstageFiles(g *gocui.Gui, v *gocui.View) error {
+	files := []*commands.File{}
+	for _, file := range gui.State.Files {
+		if file.Marked && file.HasStagedChanges {
+			files = append(files, file)
+		}
+	}
+	if len(gui.State.MarkedFileNames) == 0 {
+		file, err := gui.getSelectedFile(g)
+		if err != nil {
+			if err != gui.Errors.ErrNoFiles {
+				return err
+			}
+			return nil
+		}
+		if file.HasStagedChanges {
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoStagedFilesToUnstage"))
+	}
+
+	if err := gui.GitCommand.UnstageFiles(files); err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	gui.State.MarkedFileNames = nil
+	return gui.refreshFiles()
+}
+
+// handleAmendMarkedFiles amends HEAD with just the marked files, whether or not
+// they're staged, leaving any other staged files alone
+func (gui *Gui) handleAmendMarkedFiles(g *gocui.Gui, filesView *gocui.View) error {
+	fileNames := []string{}
+	for _, file := range gui.State.Files {
+		if file.Marked {
+			fileNames = append(fileNames, file.Name)
+		}
+	}
+	if len(fileNames) == 0 {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoFilesMarkedForAmend"))
+	}
+	if len(gui.State.Commits) == 0 {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoCommitToAmend"))
+	}
+
+	title := strings.Title(gui.Tr.SLocalize("AmendLastCommit"))
+	question := gui.Tr.SLocalize("SureToAmendMarkedFiles")
+
+	return gui.createConfirmationPanel(g, filesView, true, title, question, func(g *gocui.Gui, v *gocui.View) error {
+		ok, err := gui.runSyncOrAsyncCommand(gui.GitCommand.AmendHeadWithFiles(fileNames))
+		if ok || err == gui.Errors.ErrSubProcess {
+			gui.State.MarkedFileNames = nil
+		}
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+
+		return gui.refreshSidePanels(g)
+	}, nil)
+}
+
+func (gui *Gui) handleAmendFileToHead(g *gocui.Gui, filesView *gocui.View) error {
+	file, err := gui.getSelectedFile(g)
+	if err != nil {
+		if err != gui.Errors.ErrNoFiles {
+			return err
+		}
+		return nil
+	}
+	if len(gui.State.Commits) == 0 {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoCommitToAmend"))
+	}
+
+	title := strings.Title(gui.Tr.SLocalize("AmendLastCommit"))
+	question := gui.Tr.TemplateLocalize("SureToAmendFile", Teml{"fileName": file.Name})
+
+	return gui.createConfirmationPanel(g, filesView, true, title, question, func(g *gocui.Gui, v *gocui.View) error {
+		ok, err := gui.runSyncOrAsyncCommand(gui.GitCommand.AmendFileToHead(file.Name))
 		if err != nil {
 			return err
 		}
@@ -386,6 +831,101 @@ func (gui *Gui) handleFileOpen(g *gocui.Gui, v *gocui.View) error {
 	return gui.openFile(file.Name)
 }
 
+func (gui *Gui) handleShowFileAtRevision(g *gocui.Gui, v *gocui.View) error {
+	file, err := gui.getSelectedFile(g)
+	if err != nil {
+		if err != gui.Errors.ErrNoFiles {
+			return err
+		}
+		return nil
+	}
+
+	// in case of a renamed file we want the new filename
+	split := strings.Split(file.Name, " -> ")
+	return gui.showFileAtRevision(v, split[len(split)-1], "HEAD")
+}
+
+// showFileAtRevision prompts for a revision and renders the contents of the
+// given file as of that revision in the main view
+func (gui *Gui) showFileAtRevision(v *gocui.View, fileName string, initialRev string) error {
+	return gui.createPromptPanel(gui.g, v, gui.Tr.SLocalize("EnterRevision"), initialRev, func(g *gocui.Gui, v *gocui.View) error {
+		rev := gui.trimmedContent(v)
+		content, err := gui.GitCommand.ShowFileAtRevision(rev, fileName)
+		if err != nil {
+			return gui.createErrorPanel(gui.g, err.Error())
+		}
+
+		gui.State.SplitMainPanel = false
+		gui.getMainView().Title = fmt.Sprintf("%s:%s", rev, fileName)
+		return gui.renderString(gui.g, "main", content)
+	})
+}
+
+// handleDirectoryLog prompts for a directory, defaulting to that of the
+// selected file, and renders the log of commits touching it
+func (gui *Gui) handleDirectoryLog(g *gocui.Gui, v *gocui.View) error {
+	initialDir := ""
+	if file, err := gui.getSelectedFile(g); err == nil {
+		split := strings.Split(file.Name, " -> ")
+		if dir := filepath.Dir(split[len(split)-1]); dir != "." {
+			initialDir = dir
+		}
+	}
+
+	return gui.createPromptPanel(g, v, gui.Tr.SLocalize("EnterDirectory"), initialDir, func(g *gocui.Gui, v *gocui.View) error {
+		dir := gui.trimmedContent(v)
+		if dir == "" {
+			dir = "."
+		}
+		log, err := gui.GitCommand.GetDirectoryLog(dir, 100)
+		if err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+
+		gui.State.SplitMainPanel = false
+		gui.getMainView().Title = gui.Tr.TemplateLocalize("DirectoryLogTitle", Teml{"dir": dir})
+		return gui.renderString(g, "main", log)
+	})
+}
+
+type applyPatchOption struct {
+	description string
+	cached      bool
+	mailbox     bool
+}
+
+// GetDisplayStrings is a function.
+func (o *applyPatchOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description}
+}
+
+func (gui *Gui) handleApplyPatchFile(g *gocui.Gui, v *gocui.View) error {
+	return gui.createPromptPanel(g, v, gui.Tr.SLocalize("EnterPatchFilePath"), "", func(g *gocui.Gui, v *gocui.View) error {
+		path := gui.trimmedContent(v)
+		if path == "" {
+			return nil
+		}
+
+		options := []*applyPatchOption{
+			{description: gui.Tr.SLocalize("applyPatchToWorkingTree"), cached: false},
+			{description: gui.Tr.SLocalize("applyPatchToIndex"), cached: true},
+			{description: gui.Tr.SLocalize("applyPatchAsCommit"), mailbox: true},
+		}
+
+		handleMenuPress := func(index int) error {
+			if options[index].mailbox {
+				return gui.handleGenericMergeCommandResult(gui.GitCommand.ApplyMailboxPatch(path))
+			}
+			if err := gui.GitCommand.ApplyPatchFile(path, options[index].cached); err != nil {
+				return gui.createErrorPanel(g, err.Error())
+			}
+			return gui.refreshFiles()
+		}
+
+		return gui.createMenu(path, options, len(options), handleMenuPress)
+	})
+}
+
 func (gui *Gui) handleRefreshFiles(g *gocui.Gui, v *gocui.View) error {
 	return gui.refreshFiles()
 }
@@ -394,6 +934,9 @@ func (gui *Gui) refreshStateFiles() error {
 	// get files to stage
 	files := gui.GitCommand.GetStatusFiles()
 	gui.State.Files = gui.GitCommand.MergeStatusFiles(gui.State.Files, files)
+	for _, file := range gui.State.Files {
+		file.Marked = gui.State.MarkedFileNames[file.Name]
+	}
 
 	if err := gui.addFilesToFileWatcher(files); err != nil {
 		return err
@@ -439,11 +982,35 @@ func (gui *Gui) handlePullFiles(g *gocui.Gui, v *gocui.View) error {
 				}
 				return gui.createErrorPanel(gui.g, errorMessage)
 			}
-			return gui.pullFiles(v)
+			return gui.previewIncomingThenPull(v)
 		})
 	}
 
-	return gui.pullFiles(v)
+	return gui.previewIncomingThenPull(v)
+}
+
+// previewIncomingThenPull shows the commits we're about to pull in and asks for
+// confirmation first, if the user has turned that on
+func (gui *Gui) previewIncomingThenPull(v *gocui.View) error {
+	if !gui.Config.GetUserConfig().GetBool("git.showIncomingBeforePull") {
+		return gui.pullFiles(v)
+	}
+
+	incoming, err := gui.GitCommand.GetIncomingCommits()
+	if err != nil {
+		return gui.createErrorPanel(gui.g, err.Error())
+	}
+	if len(incoming) == 0 {
+		return gui.pullFiles(v)
+	}
+
+	list, err := utils.RenderList(incoming, false)
+	if err != nil {
+		return err
+	}
+	return gui.createConfirmationPanel(gui.g, v, true, gui.Tr.SLocalize("IncomingCommitsTitle"), list, func(g *gocui.Gui, v *gocui.View) error {
+		return gui.pullFiles(v)
+	}, nil)
 }
 
 func (gui *Gui) pullFiles(v *gocui.View) error {
@@ -494,7 +1061,21 @@ func (gui *Gui) pushFiles(g *gocui.Gui, v *gocui.View) error {
 	} else if pullables == "0" {
 		return gui.pushWithForceFlag(g, v, false, "")
 	}
-	return gui.createConfirmationPanel(g, nil, true, gui.Tr.SLocalize("ForcePush"), gui.Tr.SLocalize("ForcePushPrompt"), func(g *gocui.Gui, v *gocui.View) error {
+	if gui.GitCommand.IsProtectedBranch(currentBranchName) {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("ForcePushProtectedBranch"))
+	}
+	prompt := gui.Tr.SLocalize("ForcePushPrompt")
+	// showing where our history starts to differ makes an accidental rewrite
+	// easier to spot
+	if oldestCommit, err := gui.GitCommand.OldestUnpushedCommit(); err == nil && oldestCommit != nil {
+		prompt += "\n\n" + gui.Tr.TemplateLocalize(
+			"OldestUnpushedCommit",
+			Teml{
+				"commit": oldestCommit.DisplayString,
+			},
+		)
+	}
+	return gui.createConfirmationPanel(g, nil, true, gui.Tr.SLocalize("ForcePush"), prompt, func(g *gocui.Gui, v *gocui.View) error {
 		return gui.pushWithForceFlag(g, v, true, "")
 	}, nil)
 }
@@ -597,7 +1178,34 @@ func (gui *Gui) handleCreateDiscardMenu(g *gocui.Gui, v *gocui.View) error {
 			},
 		}
 
-		options = append(options[:1], append([]*discardOption{discardUnstagedChanges}, options[1:]...)...)
+		resetToHead := &discardOption{
+			description: gui.Tr.SLocalize("resetFileToHead"),
+			handler: func(file *commands.File) error {
+				return gui.GitCommand.ResetFileToHead(file)
+			},
+		}
+
+		options = append(options[:1], append([]*discardOption{discardUnstagedChanges, resetToHead}, options[1:]...)...)
+	}
+
+	if file.Tracked {
+		removeFile := &discardOption{
+			description: gui.Tr.SLocalize("removeTrackedFile"),
+			handler: func(file *commands.File) error {
+				return gui.GitCommand.RemoveTrackedFile(file.Name)
+			},
+		}
+
+		untrackFile := &discardOption{
+			description: gui.Tr.SLocalize("removeTrackedFileKeepLocal"),
+			handler: func(file *commands.File) error {
+				return gui.GitCommand.RemoveTrackedFileKeepLocal(file.Name)
+			},
+		}
+
+		// these go just above 'cancel'
+		cancelIndex := len(options) - 1
+		options = append(options[:cancelIndex], append([]*discardOption{removeFile, untrackFile}, options[cancelIndex:]...)...)
 	}
 
 	handleMenuPress := func(index int) error {
@@ -698,6 +1306,15 @@ func (gui *Gui) handleCreateStashMenu(g *gocui.Gui, v *gocui.View) error {
 				return gui.handleStashSave(gui.GitCommand.StashSave)
 			},
 		},
+		{
+			description: gui.Tr.SLocalize("stashIncludingUntracked"),
+			handler: func() error {
+				if len(gui.State.Files) == 0 {
+					return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("NoFilesToStash"))
+				}
+				return gui.promptForStashMessage(gui.GitCommand.StashSaveWithUntracked)
+			},
+		},
 		{
 			description: gui.Tr.SLocalize("stashStagedChanges"),
 			handler: func() error {
@@ -0,0 +1,72 @@
+package gui
+
+import (
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/commands"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFileStagingAction is a function.
+func TestFileStagingAction(t *testing.T) {
+	type scenario struct {
+		testName        string
+		file            *commands.File
+		expectedToggle  stagingAction
+		expectedStage   stagingAction
+		expectedUnstage stagingAction
+	}
+
+	scenarios := []scenario{
+		{
+			"untracked file",
+			&commands.File{Name: "new.txt", Tracked: false, HasUnstagedChanges: true},
+			stagingActionStage,
+			stagingActionStage,
+			stagingActionNone,
+		},
+		{
+			"staged untracked file",
+			&commands.File{Name: "new.txt", Tracked: false, HasStagedChanges: true},
+			stagingActionUnstage,
+			stagingActionNone,
+			stagingActionUnstage,
+		},
+		{
+			"tracked file with unstaged changes",
+			&commands.File{Name: "file.txt", Tracked: true, HasUnstagedChanges: true},
+			stagingActionStage,
+			stagingActionStage,
+			stagingActionNone,
+		},
+		{
+			"staged tracked file",
+			&commands.File{Name: "file.txt", Tracked: true, HasStagedChanges: true},
+			stagingActionUnstage,
+			stagingActionNone,
+			stagingActionUnstage,
+		},
+		{
+			"partially staged tracked file",
+			&commands.File{Name: "file.txt", Tracked: true, HasStagedChanges: true, HasUnstagedChanges: true},
+			stagingActionStage,
+			stagingActionStage,
+			stagingActionUnstage,
+		},
+		{
+			"file with merge conflicts",
+			&commands.File{Name: "file.txt", Tracked: true, HasUnstagedChanges: true, HasInlineMergeConflicts: true},
+			stagingActionMerge,
+			stagingActionMerge,
+			stagingActionNone,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expectedToggle, fileStagingAction(s.file, stagingKeyToggle))
+			assert.EqualValues(t, s.expectedStage, fileStagingAction(s.file, stagingKeyStage))
+			assert.EqualValues(t, s.expectedUnstage, fileStagingAction(s.file, stagingKeyUnstage))
+		})
+	}
+}
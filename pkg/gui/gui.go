@@ -81,6 +81,9 @@ type Gui struct {
 	credentials   credentials
 	waitForIntro  sync.WaitGroup
 	fileWatcher   *fsnotify.Watcher
+	// onSubProcDone, if set, is run once the subprocess has finished, for when
+	// we need to act on what the user did in e.g. their editor
+	onSubProcDone func() error
 }
 
 // for now the staging panel state, unlike the other panel states, is going to be
@@ -109,15 +112,25 @@ type filePanelState struct {
 
 type branchPanelState struct {
 	SelectedLine int
+	// the branch whose graph is shown in the main view, and how many of its
+	// commits we've loaded so far. Empty if the main view isn't showing a graph
+	GraphBranch      string
+	GraphLoadedCount int
+	// show the branch's graph rather than the commit at its tip
+	ShowGraph bool
 }
 
 type commitPanelState struct {
 	SelectedLine     int
 	SpecificDiffMode bool
+	MergeParent      int // when non-zero, merge commits are diffed against this parent
 }
 
 type stashPanelState struct {
 	SelectedLine int
+	// when set, the secondary panel shows how the entry differs from the
+	// working tree rather than its stat
+	DiffAgainstWorkingTree bool
 }
 
 type menuPanelState struct {
@@ -129,9 +142,25 @@ type commitFilesPanelState struct {
 	SelectedLine int
 }
 
+// blamePanelState is only set while we're navigating blame output in the
+// main view
+type blamePanelState struct {
+	SelectedLine int
+	Shas         []string // the sha of the commit each line comes from
+}
+
 type statusPanelState struct {
-	pushables string
-	pullables string
+	pushables    string
+	pullables    string
+	detachedHead bool
+	// the commit count only changes along with HEAD, so we hold onto it
+	// rather than counting the whole history on every refresh
+	commitCount    int
+	commitCountSha string
+	// likewise a submodule can only be dirty if it shows up among the
+	// changed files, so we only check again when those change
+	dirtySubmodules    bool
+	dirtySubmodulesKey string
 }
 
 type panelStates struct {
@@ -144,6 +173,7 @@ type panelStates struct {
 	Merging     *mergingPanelState
 	CommitFiles *commitFilesPanelState
 	Status      *statusPanelState
+	Blame       *blamePanelState
 }
 
 type guiState struct {
@@ -158,13 +188,22 @@ type guiState struct {
 	Platform             commands.Platform
 	Updating             bool
 	Panels               *panelStates
-	WorkingTreeState     string // one of "merging", "rebasing", "normal"
+	WorkingTreeState     string // one of "merging", "rebasing", "cherry-picking", "reverting", "applying", "normal"
 	Context              string // important not to set this value directly but to use gui.changeContext("new context")
 	CherryPickedCommits  []*commands.Commit
 	SplitMainPanel       bool
 	RetainOriginalDir    bool
 	IsRefreshingFiles    bool
 	RefreshingFilesMutex sync.Mutex
+	SkipHooksNextCommit  bool
+	CommitAuthor         string
+	ShowFullSha          bool
+	MergeCommitsMode     int // one of commands.ShowMergeCommits, commands.HideMergeCommits, commands.FirstParentOnly
+	MarkedCommitShas     map[string]bool
+	MarkedFileNames      map[string]bool
+	PushAfterCommit      bool
+	PushUpstream         string // only needed when the branch has no upstream yet
+	CommitPathspec       string // when set, only changes under this path are committed
 }
 
 // for now the split view will always be on
@@ -241,7 +280,20 @@ func (gui *Gui) scrollUpMain(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (gui *Gui) scrollDownMain(g *gocui.Gui, v *gocui.View) error {
-	return gui.scrollDownView("main")
+	if err := gui.scrollDownView("main"); err != nil {
+		return err
+	}
+	if g.CurrentView() == gui.getBranchesView() && gui.mainViewScrolledToBottom() {
+		return gui.loadMoreBranchGraph()
+	}
+	return nil
+}
+
+func (gui *Gui) mainViewScrolledToBottom() bool {
+	mainView := gui.getMainView()
+	_, oy := mainView.Origin()
+	_, sy := mainView.Size()
+	return oy+sy >= len(mainView.BufferLines())
 }
 
 func (gui *Gui) scrollUpSecondary(g *gocui.Gui, v *gocui.View) error {
@@ -310,7 +362,7 @@ func (gui *Gui) onFocusLost(v *gocui.View, newView *gocui.View) error {
 			return err
 		}
 	case "commitFiles":
-		if gui.State.Context != "patch-building" {
+		if gui.State.Context != "patch-building" && gui.State.Context != "restoring" && gui.State.Context != "blame" {
 			if _, err := gui.g.SetViewOnBottom(v.Name()); err != nil {
 				return err
 			}
@@ -779,6 +831,42 @@ func (gui *Gui) Run() error {
 	return err
 }
 
+// handleOpenShell suspends lazygit for a shell in the repo, for the odd command
+// lazygit doesn't cover. Everything is refreshed when the shell exits
+func (gui *Gui) handleOpenShell(g *gocui.Gui, v *gocui.View) error {
+	gui.SubProcess = gui.OSCommand.PrepareShellSubProcess()
+	return gui.Errors.ErrSubProcess
+}
+
+// handleCycleDiffAlgorithm switches git.diffAlgorithm to the next algorithm for
+// the rest of the session and re-renders the main view with it
+func (gui *Gui) handleCycleDiffAlgorithm(g *gocui.Gui, v *gocui.View) error {
+	algorithms := []string{"myers", "patience", "histogram", "minimal"}
+	current := gui.Config.GetUserConfig().GetString("git.diffAlgorithm")
+	next := algorithms[0]
+	for i, algorithm := range algorithms {
+		if algorithm == current {
+			next = algorithms[(i+1)%len(algorithms)]
+		}
+	}
+	gui.Config.GetUserConfig().Set("git.diffAlgorithm", next)
+
+	if v == nil {
+		return nil
+	}
+	return gui.newLineFocused(g, v)
+}
+
+// withDiffAlgorithm appends the diff algorithm to a main view title when it's
+// not git's default, so it's clear why a diff looks the way it does
+func (gui *Gui) withDiffAlgorithm(title string) string {
+	algorithm := gui.Config.GetUserConfig().GetString("git.diffAlgorithm")
+	if algorithm == "" || algorithm == "myers" {
+		return title
+	}
+	return fmt.Sprintf("%s (%s)", title, algorithm)
+}
+
 // RunWithSubprocesses loops, instantiating a new gocui.Gui with each iteration
 // if the error returned from a run is a ErrSubProcess, it runs the subprocess
 // otherwise it handles the error, possibly by quitting the application
@@ -827,6 +915,14 @@ func (gui *Gui) runCommand() error {
 	gui.SubProcess.Stdin = nil
 	gui.SubProcess = nil
 
+	if gui.onSubProcDone != nil {
+		if err := gui.onSubProcDone(); err != nil {
+			gui.Log.Error(err)
+			fmt.Fprintf(os.Stdout, "\n%s\n", utils.ColoredString(err.Error(), color.FgRed))
+		}
+		gui.onSubProcDone = nil
+	}
+
 	fmt.Fprintf(os.Stdout, "\n%s", utils.ColoredString(gui.Tr.SLocalize("pressEnterToReturn"), color.FgGreen))
 	fmt.Scanln() // wait for enter press
 
@@ -863,7 +959,7 @@ func (gui *Gui) handleMouseDownMain(g *gocui.Gui, v *gocui.View) error {
 
 	switch g.CurrentView().Name() {
 	case "files":
-		return gui.enterFile(false, v.SelectedLineIdx())
+		return gui.enterFileFromPanel(true, v.SelectedLineIdx())
 	case "commitFiles":
 		return gui.enterCommitFile(v.SelectedLineIdx())
 	}
@@ -878,8 +974,27 @@ func (gui *Gui) handleMouseDownSecondary(g *gocui.Gui, v *gocui.View) error {
 
 	switch g.CurrentView().Name() {
 	case "files":
-		return gui.enterFile(true, v.SelectedLineIdx())
+		return gui.enterFileFromPanel(false, v.SelectedLineIdx())
 	}
 
 	return nil
 }
+
+// enterFileFromPanel opens the staging panel on whichever diff was shown in
+// the clicked panel, so that the clicked line is the one that gets selected
+func (gui *Gui) enterFileFromPanel(main bool, selectedLineIdx int) error {
+	file, err := gui.getSelectedFile(gui.g)
+	if err != nil {
+		if err != gui.Errors.ErrNoFiles {
+			return err
+		}
+		return nil
+	}
+
+	mainCached, secondaryCached, _ := file.DiffPanels()
+	cached := secondaryCached
+	if main {
+		cached = mainCached
+	}
+	return gui.enterFile(cached, selectedLineIdx)
+}
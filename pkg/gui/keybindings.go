@@ -1,7 +1,11 @@
 package gui
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/config"
 )
 
 // Binding - a keybinding mapping a key and modifier to a handler. The keypress
@@ -14,6 +18,10 @@ type Binding struct {
 	Modifier    gocui.Modifier
 	Description string
 	Alternative string
+	// Action is the name by which the binding can be remapped in the keybinding
+	// section of the user's config: its section (the view name, or 'universal'
+	// for global keybindings) followed by the ID of its description
+	Action string
 }
 
 // GetDisplayStrings returns the display string of a file
@@ -62,7 +70,132 @@ func (b *Binding) GetKey() string {
 		return "tab"
 	}
 
-	return string(key)
+	return string(rune(key))
+}
+
+// sidePanelNames are the views down the left hand side of the screen
+var sidePanelNames = []string{"status", "files", "branches", "commits", "commitFiles", "stash"}
+
+// configKeyNames maps the names of special keys that can be used in the
+// keybinding section of the user's config. Otherwise keys are either a single
+// character or e.g. 'ctrl+a'
+var configKeyNames = map[string]gocui.Key{
+	"enter":     gocui.KeyEnter,
+	"esc":       gocui.KeyEsc,
+	"space":     gocui.KeySpace,
+	"tab":       gocui.KeyTab,
+	"backspace": gocui.KeyBackspace2,
+	"delete":    gocui.KeyDelete,
+	"home":      gocui.KeyHome,
+	"end":       gocui.KeyEnd,
+	"pgup":      gocui.KeyPgup,
+	"pgdown":    gocui.KeyPgdn,
+	"up":        gocui.KeyArrowUp,
+	"down":      gocui.KeyArrowDown,
+	"left":      gocui.KeyArrowLeft,
+	"right":     gocui.KeyArrowRight,
+}
+
+func keyToConfigString(key interface{}) string {
+	switch key := key.(type) {
+	case rune:
+		return string(key)
+	case gocui.Key:
+		for name, namedKey := range configKeyNames {
+			if key == namedKey {
+				return name
+			}
+		}
+		if key >= gocui.KeyCtrlA && key <= gocui.KeyCtrlZ {
+			return fmt.Sprintf("ctrl+%c", 'a'+rune(key-gocui.KeyCtrlA))
+		}
+	}
+	return ""
+}
+
+func keyFromConfigString(str string) (interface{}, error) {
+	if runes := []rune(str); len(runes) == 1 {
+		return runes[0], nil
+	}
+
+	lowered := strings.ToLower(str)
+	if key, ok := configKeyNames[lowered]; ok {
+		return key, nil
+	}
+	if strings.HasPrefix(lowered, "ctrl+") && len(lowered) == len("ctrl+")+1 {
+		letter := lowered[len(lowered)-1]
+		if letter >= 'a' && letter <= 'z' {
+			return gocui.KeyCtrlA + gocui.Key(letter-'a'), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key '%s' in keybinding config", str)
+}
+
+// applyUserKeybindings swaps in any keys the user has remapped. We work out all
+// the remappings against the default keys before applying them so that
+// swapping two actions' keys works
+func (gui *Gui) applyUserKeybindings(bindings []*Binding) {
+	userConfig := gui.Config.GetUserConfig()
+	remapped := map[*Binding]interface{}{}
+
+	for _, binding := range bindings {
+		if binding.Action == "" {
+			continue
+		}
+		defaultKey := keyToConfigString(binding.Key)
+		configKey := config.GetKeybinding(userConfig, binding.Action, defaultKey)
+		if configKey == defaultKey {
+			continue
+		}
+		key, err := keyFromConfigString(configKey)
+		if err != nil {
+			gui.Log.Error(err)
+			continue
+		}
+		remapped[binding] = key
+	}
+
+	// a remapping that clashes with another binding in the same view is dropped,
+	// which can in turn cause a clash with the key it leaves in place
+	for {
+		clashing := clashingRemappings(bindings, remapped)
+		if len(clashing) == 0 {
+			break
+		}
+		for _, binding := range clashing {
+			gui.Log.Errorf("keybinding for %s clashes with another keybinding in the same view, keeping its default key", binding.Action)
+			delete(remapped, binding)
+		}
+	}
+
+	for binding, key := range remapped {
+		binding.Key = key
+	}
+}
+
+// clashingRemappings returns the remapped bindings that would end up with the
+// same key as another binding in their view
+func clashingRemappings(bindings []*Binding, remapped map[*Binding]interface{}) []*Binding {
+	keyOf := func(binding *Binding) interface{} {
+		if key, ok := remapped[binding]; ok {
+			return key
+		}
+		return binding.Key
+	}
+
+	clashing := []*Binding{}
+	for _, binding := range bindings {
+		if _, ok := remapped[binding]; !ok {
+			continue
+		}
+		for _, other := range bindings {
+			if other != binding && other.ViewName == binding.ViewName && other.Modifier == binding.Modifier && keyOf(other) == keyOf(binding) {
+				clashing = append(clashing, binding)
+				break
+			}
+		}
+	}
+	return clashing
 }
 
 // GetInitialKeybindings is a function.
@@ -126,24 +259,42 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCreateRebaseOptionsMenu,
 			Description: gui.Tr.SLocalize("ViewMergeRebaseOptions"),
+			Action:      "universal.viewMergeRebaseOptions",
+		}, {
+			ViewName:    "",
+			Key:         'Z',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleAbortAll,
+			Description: gui.Tr.SLocalize("abortAll"),
+			Action:      "universal.abortAll",
 		}, {
 			ViewName:    "",
 			Key:         'P',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.pushFiles,
 			Description: gui.Tr.SLocalize("push"),
+			Action:      "universal.push",
 		}, {
 			ViewName:    "",
 			Key:         'p',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handlePullFiles,
 			Description: gui.Tr.SLocalize("pull"),
+			Action:      "universal.pull",
 		}, {
 			ViewName:    "",
 			Key:         'R',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleRefresh,
 			Description: gui.Tr.SLocalize("refresh"),
+			Action:      "universal.refresh",
+		}, {
+			ViewName:    "",
+			Key:         '!',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleOpenShell,
+			Description: gui.Tr.SLocalize("openShell"),
+			Action:      "universal.openShell",
 		}, {
 			ViewName: "",
 			Key:      'x',
@@ -170,24 +321,28 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleEditConfig,
 			Description: gui.Tr.SLocalize("EditConfig"),
+			Action:      "status.editConfig",
 		}, {
 			ViewName:    "status",
 			Key:         'o',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleOpenConfig,
 			Description: gui.Tr.SLocalize("OpenConfig"),
+			Action:      "status.openConfig",
 		}, {
 			ViewName:    "status",
 			Key:         'u',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCheckForUpdate,
 			Description: gui.Tr.SLocalize("checkForUpdate"),
+			Action:      "status.checkForUpdate",
 		}, {
 			ViewName:    "status",
 			Key:         's',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCreateRecentReposMenu,
 			Description: gui.Tr.SLocalize("SwitchRepo"),
+			Action:      "status.switchRepo",
 		},
 		{
 			ViewName:    "files",
@@ -195,6 +350,7 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitPress,
 			Description: gui.Tr.SLocalize("CommitChanges"),
+			Action:      "files.commitChanges",
 		},
 		{
 			ViewName:    "files",
@@ -202,282 +358,693 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleWIPCommitPress,
 			Description: gui.Tr.SLocalize("commitChangesWithoutHook"),
+			Action:      "files.commitChangesWithoutHook",
+		}, {
+			ViewName:    "status",
+			Key:         'a',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleShowShortlog,
+			Description: gui.Tr.SLocalize("showShortlog"),
+			Action:      "status.showShortlog",
+		}, {
+			ViewName:    "status",
+			Key:         'r',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleShowReflogSince,
+			Description: gui.Tr.SLocalize("showReflogSince"),
+			Action:      "status.showReflogSince",
+		}, {
+			ViewName:    "status",
+			Key:         'n',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCreateBranchFromDetachedHead,
+			Description: gui.Tr.SLocalize("createBranchFromDetachedHead"),
+			Action:      "status.createBranchFromDetachedHead",
+		}, {
+			ViewName:    "status",
+			Key:         'M',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleRunMaintenance,
+			Description: gui.Tr.SLocalize("runMaintenance"),
+			Action:      "status.runMaintenance",
+		}, {
+			ViewName:    "status",
+			Key:         'H',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCreateHooksMenu,
+			Description: gui.Tr.SLocalize("viewHooks"),
+			Action:      "status.viewHooks",
 		}, {
 			ViewName:    "files",
 			Key:         'A',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleAmendCommitPress,
 			Description: gui.Tr.SLocalize("AmendLastCommit"),
+			Action:      "files.amendLastCommit",
+		}, {
+			ViewName:    "files",
+			Key:         gocui.KeyCtrlA,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleAmendCommitKeepDatePress,
+			Description: gui.Tr.SLocalize("amendLastCommitKeepDate"),
+			Action:      "files.amendLastCommitKeepDate",
+		}, {
+			ViewName:    "files",
+			Key:         'N',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleAmendFileToHead,
+			Description: gui.Tr.SLocalize("amendFileToHead"),
+			Action:      "files.amendFileToHead",
+		}, {
+			ViewName:    "files",
+			Key:         'U',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCreateUnstageHunkMenu,
+			Description: gui.Tr.SLocalize("unstageHunk"),
+			Action:      "files.unstageHunk",
+		}, {
+			ViewName:    "files",
+			Key:         'O',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCommitPathspecPress,
+			Description: gui.Tr.SLocalize("commitPathspec"),
+			Action:      "files.commitPathspec",
+		}, {
+			ViewName:    "files",
+			Key:         't',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleToggleFileMarked,
+			Description: gui.Tr.SLocalize("toggleFileMarked"),
+			Action:      "files.toggleFileMarked",
+		}, {
+			ViewName:    "files",
+			Key:         'T',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleAmendMarkedFiles,
+			Description: gui.Tr.SLocalize("amendMarkedFiles"),
+			Action:      "files.amendMarkedFiles",
+		}, {
+			ViewName:    "files",
+			Key:         'u',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleUnstageFiles,
+			Description: gui.Tr.SLocalize("unstageFiles"),
+			Action:      "files.unstageFiles",
 		}, {
 			ViewName:    "files",
 			Key:         'C',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitEditorPress,
 			Description: gui.Tr.SLocalize("CommitChangesWithEditor"),
+			Action:      "files.commitChangesWithEditor",
 		}, {
 			ViewName:    "files",
 			Key:         gocui.KeySpace,
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleFilePress,
 			Description: gui.Tr.SLocalize("toggleStaged"),
+			Action:      "files.toggleStaged",
 		}, {
 			ViewName:    "files",
 			Key:         'd',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCreateDiscardMenu,
 			Description: gui.Tr.SLocalize("viewDiscardOptions"),
+			Action:      "files.viewDiscardOptions",
 		}, {
 			ViewName:    "files",
 			Key:         'e',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleFileEdit,
 			Description: gui.Tr.SLocalize("editFile"),
+			Action:      "files.editFile",
 		}, {
 			ViewName:    "files",
 			Key:         'o',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleFileOpen,
 			Description: gui.Tr.SLocalize("openFile"),
+			Action:      "files.openFile",
 		}, {
 			ViewName:    "files",
 			Key:         'i',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleIgnoreFile,
 			Description: gui.Tr.SLocalize("ignoreFile"),
+			Action:      "files.ignoreFile",
+		}, {
+			ViewName:    "files",
+			Key:         gocui.KeyCtrlR,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleMoveFile,
+			Description: gui.Tr.SLocalize("moveFile"),
+			Action:      "files.moveFile",
 		}, {
 			ViewName:    "files",
 			Key:         'r',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleRefreshFiles,
 			Description: gui.Tr.SLocalize("refreshFiles"),
+			Action:      "files.refreshFiles",
 		}, {
 			ViewName:    "files",
 			Key:         's',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleStashChanges,
 			Description: gui.Tr.SLocalize("stashAllChanges"),
+			Action:      "files.stashAllChanges",
 		}, {
 			ViewName:    "files",
 			Key:         'S',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCreateStashMenu,
 			Description: gui.Tr.SLocalize("viewStashOptions"),
+			Action:      "files.viewStashOptions",
 		}, {
 			ViewName:    "files",
 			Key:         'a',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleStageAll,
 			Description: gui.Tr.SLocalize("toggleStagedAll"),
+			Action:      "files.toggleStagedAll",
 		}, {
 			ViewName:    "files",
 			Key:         'D',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCreateResetMenu,
 			Description: gui.Tr.SLocalize("viewResetOptions"),
+			Action:      "files.viewResetOptions",
 		}, {
 			ViewName:    "files",
 			Key:         gocui.KeyEnter,
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleEnterFile,
 			Description: gui.Tr.SLocalize("StageLines"),
+			Action:      "files.stageLines",
 		}, {
 			ViewName:    "files",
 			Key:         'f',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleGitFetch,
 			Description: gui.Tr.SLocalize("fetch"),
+			Action:      "files.fetch",
 		}, {
 			ViewName:    "files",
 			Key:         'X',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCustomCommand,
 			Description: gui.Tr.SLocalize("executeCustomCommand"),
+			Action:      "files.executeCustomCommand",
+		}, {
+			ViewName:    "files",
+			Key:         'M',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleRebaseContinue,
+			Description: gui.Tr.SLocalize("continueRebase"),
+			Action:      "files.continueRebase",
+		}, {
+			ViewName:    "files",
+			Key:         'E',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleAmendAndContinueRebase,
+			Description: gui.Tr.SLocalize("amendAndContinueRebase"),
+			Action:      "files.amendAndContinueRebase",
+		}, {
+			ViewName:    "files",
+			Key:         'v',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleShowFileAtRevision,
+			Description: gui.Tr.SLocalize("showFileAtRevision"),
+			Action:      "files.showFileAtRevision",
+		}, {
+			ViewName:    "files",
+			Key:         'L',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleDirectoryLog,
+			Description: gui.Tr.SLocalize("viewDirectoryLog"),
+			Action:      "files.viewDirectoryLog",
+		}, {
+			ViewName:    "files",
+			Key:         'F',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleApplyPatchFile,
+			Description: gui.Tr.SLocalize("applyPatchFile"),
+			Action:      "files.applyPatchFile",
+		}, {
+			ViewName:    "files",
+			Key:         'n',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleNextConflictedFile,
+			Description: gui.Tr.SLocalize("nextConflictedFile"),
+			Action:      "files.nextConflictedFile",
+		}, {
+			ViewName:    "files",
+			Key:         'g',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleStageGlob,
+			Description: gui.Tr.SLocalize("stageGlob"),
+			Action:      "files.stageGlob",
+		}, {
+			ViewName:    "files",
+			Key:         '=',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleShowStagedDiff,
+			Description: gui.Tr.SLocalize("showStagedDiff"),
+			Action:      "files.showStagedDiff",
+		}, {
+			ViewName:    "files",
+			Key:         'I',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCheckIgnore,
+			Description: gui.Tr.SLocalize("checkIgnore"),
+			Action:      "files.checkIgnore",
+		}, {
+			ViewName:    "files",
+			Key:         'Y',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCommitAndPushPress,
+			Description: gui.Tr.SLocalize("commitAndPush"),
+			Action:      "files.commitAndPush",
+		}, {
+			ViewName:    "files",
+			Key:         '+',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleStageFile,
+			Description: gui.Tr.SLocalize("stageFile"),
+			Action:      "files.stageFile",
+		}, {
+			ViewName:    "files",
+			Key:         '-',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleUnstageFile,
+			Description: gui.Tr.SLocalize("unstageFile"),
+			Action:      "files.unstageFile",
 		}, {
 			ViewName:    "branches",
 			Key:         gocui.KeySpace,
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleBranchPress,
 			Description: gui.Tr.SLocalize("checkout"),
+			Action:      "branches.checkout",
 		}, {
 			ViewName:    "branches",
 			Key:         'o',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCreatePullRequestPress,
 			Description: gui.Tr.SLocalize("createPullRequest"),
+			Action:      "branches.createPullRequest",
+		}, {
+			ViewName:    "branches",
+			Key:         'O',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handlePushAndCreatePullRequest,
+			Description: gui.Tr.SLocalize("pushAndCreatePullRequest"),
+			Action:      "branches.pushAndCreatePullRequest",
 		}, {
 			ViewName:    "branches",
 			Key:         'c',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCheckoutByName,
 			Description: gui.Tr.SLocalize("checkoutByName"),
+			Action:      "branches.checkoutByName",
 		}, {
 			ViewName:    "branches",
 			Key:         'F',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleForceCheckout,
 			Description: gui.Tr.SLocalize("forceCheckout"),
+			Action:      "branches.forceCheckout",
 		}, {
 			ViewName:    "branches",
 			Key:         'n',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleNewBranch,
 			Description: gui.Tr.SLocalize("newBranch"),
+			Action:      "branches.newBranch",
 		}, {
 			ViewName:    "branches",
 			Key:         'd',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleDeleteBranch,
 			Description: gui.Tr.SLocalize("deleteBranch"),
+			Action:      "branches.deleteBranch",
 		}, {
 			ViewName:    "branches",
 			Key:         'r',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleRebase,
 			Description: gui.Tr.SLocalize("rebaseBranch"),
+			Action:      "branches.rebaseBranch",
 		}, {
 			ViewName:    "branches",
 			Key:         'M',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleMerge,
 			Description: gui.Tr.SLocalize("mergeIntoCurrentBranch"),
+			Action:      "branches.mergeIntoCurrentBranch",
+		}, {
+			ViewName:    "branches",
+			Key:         'v',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCompareWithCurrentBranch,
+			Description: gui.Tr.SLocalize("compareWithCurrentBranch"),
+			Action:      "branches.compareWithCurrentBranch",
+		}, {
+			ViewName:    "branches",
+			Key:         'L',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCreateCommitRangeMenu,
+			Description: gui.Tr.SLocalize("compareCommitsWithCurrentBranch"),
+			Action:      "branches.compareCommitsWithCurrentBranch",
 		}, {
 			ViewName:    "branches",
 			Key:         'f',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleFastForward,
 			Description: gui.Tr.SLocalize("FastForward"),
+			Action:      "branches.fastForward",
+		}, {
+			ViewName:    "branches",
+			Key:         'T',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCreateTagsMenu,
+			Description: gui.Tr.SLocalize("viewTags"),
+			Action:      "branches.viewTags",
+		}, {
+			ViewName:    "branches",
+			Key:         'C',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCompareTags,
+			Description: gui.Tr.SLocalize("compareTags"),
+			Action:      "branches.compareTags",
+		}, {
+			ViewName:    "branches",
+			Key:         'R',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleRenameBranch,
+			Description: gui.Tr.SLocalize("renameBranch"),
+			Action:      "branches.renameBranch",
+		}, {
+			ViewName:    "branches",
+			Key:         'i',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleToggleBranchGraph,
+			Description: gui.Tr.SLocalize("toggleBranchGraph"),
+			Action:      "branches.toggleBranchGraph",
 		}, {
 			ViewName:    "commits",
 			Key:         's',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitSquashDown,
 			Description: gui.Tr.SLocalize("squashDown"),
+			Action:      "commits.squashDown",
 		}, {
 			ViewName:    "commits",
 			Key:         'r',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleRenameCommit,
 			Description: gui.Tr.SLocalize("renameCommit"),
+			Action:      "commits.renameCommit",
 		}, {
 			ViewName:    "commits",
 			Key:         'R',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleRenameCommitEditor,
 			Description: gui.Tr.SLocalize("renameCommitEditor"),
+			Action:      "commits.renameCommitEditor",
 		}, {
 			ViewName:    "commits",
 			Key:         'g',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCreateCommitResetMenu,
 			Description: gui.Tr.SLocalize("resetToThisCommit"),
+			Action:      "commits.resetToThisCommit",
 		}, {
 			ViewName:    "commits",
 			Key:         'f',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitFixup,
 			Description: gui.Tr.SLocalize("fixupCommit"),
+			Action:      "commits.fixupCommit",
 		}, {
 			ViewName:    "commits",
 			Key:         'F',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCreateFixupCommit,
 			Description: gui.Tr.SLocalize("createFixupCommit"),
+			Action:      "commits.createFixupCommit",
+		}, {
+			ViewName:    "commits",
+			Key:         gocui.KeyCtrlS,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCreateSquashCommit,
+			Description: gui.Tr.SLocalize("createSquashCommit"),
+			Action:      "commits.createSquashCommit",
+		}, {
+			ViewName:    "commits",
+			Key:         gocui.KeyCtrlF,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCreateFixupCommitFromGraph,
+			Description: gui.Tr.SLocalize("createFixupCommitFromGraph"),
+			Action:      "commits.createFixupCommitFromGraph",
 		}, {
 			ViewName:    "commits",
 			Key:         'S',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleSquashAllAboveFixupCommits,
 			Description: gui.Tr.SLocalize("squashAboveCommits"),
+			Action:      "commits.squashAboveCommits",
 		}, {
 			ViewName:    "commits",
 			Key:         'd',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitDelete,
 			Description: gui.Tr.SLocalize("deleteCommit"),
+			Action:      "commits.deleteCommit",
 		}, {
 			ViewName:    "commits",
 			Key:         gocui.KeyCtrlJ,
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitMoveDown,
 			Description: gui.Tr.SLocalize("moveDownCommit"),
+			Action:      "commits.moveDownCommit",
 		}, {
 			ViewName:    "commits",
 			Key:         gocui.KeyCtrlK,
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitMoveUp,
 			Description: gui.Tr.SLocalize("moveUpCommit"),
+			Action:      "commits.moveUpCommit",
+		}, {
+			ViewName:    "commits",
+			Key:         'T',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCommitMoveToTop,
+			Description: gui.Tr.SLocalize("moveCommitToTop"),
+			Action:      "commits.moveCommitToTop",
+		}, {
+			ViewName:    "commits",
+			Key:         'B',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCommitMoveToBottom,
+			Description: gui.Tr.SLocalize("moveCommitToBottom"),
+			Action:      "commits.moveCommitToBottom",
+		}, {
+			ViewName:    "commits",
+			Key:         'N',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleMoveCommitsToNewBranch,
+			Description: gui.Tr.SLocalize("moveCommitsToNewBranch"),
+			Action:      "commits.moveCommitsToNewBranch",
+		}, {
+			ViewName:    "commits",
+			Key:         'H',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleToggleFullSha,
+			Description: gui.Tr.SLocalize("toggleFullSha"),
+			Action:      "commits.toggleFullSha",
+		}, {
+			ViewName:    "commits",
+			Key:         'L',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCycleMergeCommitsMode,
+			Description: gui.Tr.SLocalize("cycleMergeCommitsMode"),
+			Action:      "commits.cycleMergeCommitsMode",
+		}, {
+			ViewName:    "commits",
+			Key:         'M',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleToggleCommitMarked,
+			Description: gui.Tr.SLocalize("toggleCommitMarked"),
+			Action:      "commits.toggleCommitMarked",
+		}, {
+			ViewName:    "commits",
+			Key:         'W',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleSquashMarkedCommits,
+			Description: gui.Tr.SLocalize("squashMarkedCommits"),
+			Action:      "commits.squashMarkedCommits",
+		}, {
+			ViewName:    "commits",
+			Key:         'X',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleRebaseWithExec,
+			Description: gui.Tr.SLocalize("rebaseWithExec"),
+			Action:      "commits.rebaseWithExec",
+		}, {
+			ViewName:    "commits",
+			Key:         '^',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleToggleMergeParent,
+			Description: gui.Tr.SLocalize("toggleMergeParent"),
+			Action:      "commits.toggleMergeParent",
+		}, {
+			ViewName:    "commits",
+			Key:         'O',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleRebaseOntoCommit,
+			Description: gui.Tr.SLocalize("rebaseOntoCommit"),
+			Action:      "commits.rebaseOntoCommit",
+		}, {
+			ViewName:    "commits",
+			Key:         'U',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleRevertHead,
+			Description: gui.Tr.SLocalize("revertHead"),
+			Action:      "commits.revertHead",
+		}, {
+			ViewName:    "commits",
+			Key:         'G',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleShowCommitSignature,
+			Description: gui.Tr.SLocalize("showCommitSignature"),
+			Action:      "commits.showCommitSignature",
+		}, {
+			ViewName:    "commits",
+			Key:         gocui.KeyCtrlR,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleRevertRange,
+			Description: gui.Tr.SLocalize("revertRange"),
+			Action:      "commits.revertRange",
+		}, {
+			ViewName:    "commits",
+			Key:         gocui.KeyCtrlT,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCreateLightweightTag,
+			Description: gui.Tr.SLocalize("createLightweightTag"),
+			Action:      "commits.createLightweightTag",
+		}, {
+			ViewName:    "commits",
+			Key:         'b',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleBisectRun,
+			Description: gui.Tr.SLocalize("bisectRun"),
+			Action:      "commits.bisectRun",
 		}, {
 			ViewName:    "commits",
 			Key:         'e',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitEdit,
 			Description: gui.Tr.SLocalize("editCommit"),
+			Action:      "commits.editCommit",
 		}, {
 			ViewName:    "commits",
 			Key:         'A',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitAmendTo,
 			Description: gui.Tr.SLocalize("amendToCommit"),
+			Action:      "commits.amendToCommit",
 		}, {
 			ViewName:    "commits",
 			Key:         'p',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitPick,
 			Description: gui.Tr.SLocalize("pickCommit"),
+			Action:      "commits.pickCommit",
 		}, {
 			ViewName:    "commits",
 			Key:         't',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitRevert,
 			Description: gui.Tr.SLocalize("revertCommit"),
+			Action:      "commits.revertCommit",
 		}, {
 			ViewName:    "commits",
 			Key:         'c',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCopyCommit,
 			Description: gui.Tr.SLocalize("cherryPickCopy"),
+			Action:      "commits.cherryPickCopy",
 		}, {
 			ViewName:    "commits",
 			Key:         'C',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCopyCommitRange,
 			Description: gui.Tr.SLocalize("cherryPickCopyRange"),
+			Action:      "commits.cherryPickCopyRange",
 		}, {
 			ViewName:    "commits",
 			Key:         'v',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.HandlePasteCommits,
 			Description: gui.Tr.SLocalize("pasteCommits"),
+			Action:      "commits.pasteCommits",
+		}, {
+			ViewName:    "commits",
+			Key:         'V',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handlePasteCommitRange,
+			Description: gui.Tr.SLocalize("pasteCommitRange"),
+			Action:      "commits.pasteCommitRange",
 		}, {
 			ViewName:    "commits",
 			Key:         gocui.KeyEnter,
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleSwitchToCommitFilesPanel,
 			Description: gui.Tr.SLocalize("viewCommitFiles"),
+			Action:      "commits.viewCommitFiles",
 		}, {
 			ViewName:    "commits",
 			Key:         gocui.KeySpace,
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleToggleDiffCommit,
 			Description: gui.Tr.SLocalize("CommitsDiff"),
+			Action:      "commits.commitsDiff",
 		}, {
 			ViewName:    "stash",
 			Key:         gocui.KeySpace,
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleStashApply,
 			Description: gui.Tr.SLocalize("apply"),
+			Action:      "stash.apply",
 		}, {
 			ViewName:    "stash",
 			Key:         'g',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleStashPop,
 			Description: gui.Tr.SLocalize("pop"),
+			Action:      "stash.pop",
 		}, {
 			ViewName:    "stash",
 			Key:         'd',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleStashDrop,
 			Description: gui.Tr.SLocalize("drop"),
+			Action:      "stash.drop",
+		}, {
+			ViewName:    "stash",
+			Key:         'r',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCreateRestoreStashFileMenu,
+			Description: gui.Tr.SLocalize("restoreStashFile"),
+			Action:      "stash.restoreStashFile",
+		}, {
+			ViewName:    "stash",
+			Key:         'w',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleToggleStashWorkingTreeDiff,
+			Description: gui.Tr.SLocalize("toggleStashWorkingTreeDiff"),
+			Action:      "stash.toggleStashWorkingTreeDiff",
 		}, {
 			ViewName: "commitMessage",
 			Key:      gocui.KeyEnter,
@@ -488,6 +1055,16 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Key:      gocui.KeyEsc,
 			Modifier: gocui.ModNone,
 			Handler:  gui.handleCommitClose,
+		}, {
+			ViewName: "commitMessage",
+			Key:      gocui.KeyCtrlN,
+			Modifier: gocui.ModNone,
+			Handler:  gui.handleToggleSkipHooksNextCommit,
+		}, {
+			ViewName: "commitMessage",
+			Key:      gocui.KeyCtrlO,
+			Modifier: gocui.ModNone,
+			Handler:  gui.handleCreateCommitAuthorMenu,
 		}, {
 			ViewName: "credentials",
 			Key:      gocui.KeyEnter,
@@ -519,18 +1096,28 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleSwitchToCommitsPanel,
 			Description: gui.Tr.SLocalize("goBack"),
+			Action:      "commitFiles.goBack",
 		}, {
 			ViewName:    "commitFiles",
 			Key:         'c',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCheckoutCommitFile,
 			Description: gui.Tr.SLocalize("checkoutCommitFile"),
+			Action:      "commitFiles.checkoutCommitFile",
 		}, {
 			ViewName:    "commitFiles",
 			Key:         'd',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleDiscardOldFileChange,
 			Description: gui.Tr.SLocalize("discardOldFileChange"),
+			Action:      "commitFiles.discardOldFileChange",
+		}, {
+			ViewName:    "commitFiles",
+			Key:         'D',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleDiscardFileChangesInRange,
+			Description: gui.Tr.SLocalize("discardFileChangesInRange"),
+			Action:      "commitFiles.discardFileChangesInRange",
 		},
 		{
 			ViewName:    "commitFiles",
@@ -538,6 +1125,31 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleOpenOldCommitFile,
 			Description: gui.Tr.SLocalize("openFile"),
+			Action:      "commitFiles.openFile",
+		},
+		{
+			ViewName:    "commitFiles",
+			Key:         'v',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleShowCommitFileAtRevision,
+			Description: gui.Tr.SLocalize("showFileAtRevision"),
+			Action:      "commitFiles.showFileAtRevision",
+		},
+		{
+			ViewName:    "commitFiles",
+			Key:         'b',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleBlameCommitFile,
+			Description: gui.Tr.SLocalize("blameFileAtCommit"),
+			Action:      "commitFiles.blameFileAtCommit",
+		},
+		{
+			ViewName:    "commitFiles",
+			Key:         'r',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleEnterRestoringPanel,
+			Description: gui.Tr.SLocalize("restoreLinesFromCommit"),
+			Action:      "commitFiles.restoreLinesFromCommit",
 		},
 		{
 			ViewName:    "commitFiles",
@@ -545,6 +1157,7 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleToggleFileForPatch,
 			Description: gui.Tr.SLocalize("toggleAddToPatch"),
+			Action:      "commitFiles.toggleAddToPatch",
 		},
 		{
 			ViewName:    "commitFiles",
@@ -552,6 +1165,7 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleEnterCommitFile,
 			Description: gui.Tr.SLocalize("enterFile"),
+			Action:      "commitFiles.enterFile",
 		},
 		{
 			ViewName: "secondary",
@@ -577,6 +1191,20 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 		}...)
 	}
 
+	// cycling the diff algorithm re-renders whatever the selected side panel
+	// shows in the main view. Binding it globally would steal ctrl+w from the
+	// editable views
+	for _, viewName := range sidePanelNames {
+		bindings = append(bindings, &Binding{
+			ViewName:    viewName,
+			Key:         gocui.KeyCtrlW,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCycleDiffAlgorithm,
+			Description: gui.Tr.SLocalize("cycleDiffAlgorithm"),
+			Action:      "universal.cycleDiffAlgorithm",
+		})
+	}
+
 	// Appends keybindings to jump to a particular sideView using numbers
 	for i, viewName := range []string{"status", "files", "branches", "commits", "stash"} {
 		bindings = append(bindings, &Binding{ViewName: "", Key: rune(i+1) + '0', Modifier: gocui.ModNone, Handler: gui.goToSideView(viewName)})
@@ -608,6 +1236,8 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 		}...)
 	}
 
+	gui.applyUserKeybindings(bindings)
+
 	return bindings
 }
 
@@ -745,6 +1375,12 @@ func (gui *Gui) GetContextMap() map[string][]*Binding {
 				Modifier:    gocui.ModNone,
 				Handler:     gui.handleToggleSelectHunk,
 				Description: gui.Tr.SLocalize("ToggleSelectHunk"),
+			}, {
+				ViewName:    "main",
+				Key:         'e',
+				Modifier:    gocui.ModNone,
+				Handler:     gui.handleEditHunk,
+				Description: gui.Tr.SLocalize("editHunk"),
 			}, {
 				ViewName:    "main",
 				Key:         gocui.KeyTab,
@@ -880,6 +1516,144 @@ func (gui *Gui) GetContextMap() map[string][]*Binding {
 				Handler:  gui.handleMouseScrollDown,
 			},
 		},
+		"restoring": {
+			{
+				ViewName:    "main",
+				Key:         gocui.KeyEsc,
+				Modifier:    gocui.ModNone,
+				Handler:     gui.handleEscapeRestoringPanel,
+				Description: gui.Tr.SLocalize("ExitLineByLineMode"),
+			}, {
+				ViewName:    "main",
+				Key:         gocui.KeyArrowUp,
+				Modifier:    gocui.ModNone,
+				Handler:     gui.handleSelectPrevLine,
+				Description: gui.Tr.SLocalize("PrevLine"),
+			}, {
+				ViewName:    "main",
+				Key:         gocui.KeyArrowDown,
+				Modifier:    gocui.ModNone,
+				Handler:     gui.handleSelectNextLine,
+				Description: gui.Tr.SLocalize("NextLine"),
+			}, {
+				ViewName: "main",
+				Key:      'k',
+				Modifier: gocui.ModNone,
+				Handler:  gui.handleSelectPrevLine,
+			}, {
+				ViewName: "main",
+				Key:      'j',
+				Modifier: gocui.ModNone,
+				Handler:  gui.handleSelectNextLine,
+			}, {
+				ViewName:    "main",
+				Key:         gocui.KeyArrowLeft,
+				Modifier:    gocui.ModNone,
+				Handler:     gui.handleSelectPrevHunk,
+				Description: gui.Tr.SLocalize("PrevHunk"),
+			}, {
+				ViewName:    "main",
+				Key:         gocui.KeyArrowRight,
+				Modifier:    gocui.ModNone,
+				Handler:     gui.handleSelectNextHunk,
+				Description: gui.Tr.SLocalize("NextHunk"),
+			}, {
+				ViewName: "main",
+				Key:      'h',
+				Modifier: gocui.ModNone,
+				Handler:  gui.handleSelectPrevHunk,
+			}, {
+				ViewName: "main",
+				Key:      'l',
+				Modifier: gocui.ModNone,
+				Handler:  gui.handleSelectNextHunk,
+			}, {
+				ViewName:    "main",
+				Key:         gocui.KeySpace,
+				Modifier:    gocui.ModNone,
+				Handler:     gui.handleRestoreSelection,
+				Description: gui.Tr.SLocalize("RestoreSelection"),
+			}, {
+				ViewName:    "main",
+				Key:         'v',
+				Modifier:    gocui.ModNone,
+				Handler:     gui.handleToggleSelectRange,
+				Description: gui.Tr.SLocalize("ToggleDragSelect"),
+			}, {
+				ViewName:    "main",
+				Key:         'a',
+				Modifier:    gocui.ModNone,
+				Handler:     gui.handleToggleSelectHunk,
+				Description: gui.Tr.SLocalize("ToggleSelectHunk"),
+			}, {
+				ViewName: "main",
+				Key:      gocui.MouseLeft,
+				Modifier: gocui.ModNone,
+				Handler:  gui.handleMouseDown,
+			}, {
+				ViewName: "main",
+				Key:      gocui.MouseLeft,
+				Modifier: gocui.ModMotion,
+				Handler:  gui.handleMouseDrag,
+			}, {
+				ViewName: "main",
+				Key:      gocui.MouseWheelUp,
+				Modifier: gocui.ModNone,
+				Handler:  gui.handleMouseScrollUp,
+			}, {
+				ViewName: "main",
+				Key:      gocui.MouseWheelDown,
+				Modifier: gocui.ModNone,
+				Handler:  gui.handleMouseScrollDown,
+			},
+		},
+		"blame": {
+			{
+				ViewName:    "main",
+				Key:         gocui.KeyEsc,
+				Modifier:    gocui.ModNone,
+				Handler:     gui.handleEscapeBlamePanel,
+				Description: gui.Tr.SLocalize("ReturnToCommitFilesPanel"),
+			}, {
+				ViewName:    "main",
+				Key:         gocui.KeyArrowUp,
+				Modifier:    gocui.ModNone,
+				Handler:     gui.handleBlamePrevLine,
+				Description: gui.Tr.SLocalize("PrevLine"),
+			}, {
+				ViewName:    "main",
+				Key:         gocui.KeyArrowDown,
+				Modifier:    gocui.ModNone,
+				Handler:     gui.handleBlameNextLine,
+				Description: gui.Tr.SLocalize("NextLine"),
+			}, {
+				ViewName: "main",
+				Key:      'k',
+				Modifier: gocui.ModNone,
+				Handler:  gui.handleBlamePrevLine,
+			}, {
+				ViewName: "main",
+				Key:      'j',
+				Modifier: gocui.ModNone,
+				Handler:  gui.handleBlameNextLine,
+			}, {
+				ViewName: "main",
+				Key:      gocui.MouseWheelUp,
+				Modifier: gocui.ModNone,
+				Handler:  gui.handleBlamePrevLine,
+			}, {
+				ViewName: "main",
+				Key:      gocui.MouseWheelDown,
+				Modifier: gocui.ModNone,
+				Handler:  gui.handleBlameNextLine,
+			}, {
+				ViewName:    "main",
+				Key:         gocui.KeyEnter,
+				Modifier:    gocui.ModNone,
+				Handler:     gui.handleGoToBlameCommit,
+				Description: gui.Tr.SLocalize("goToBlameCommit"),
+			},
+		},
 		"merging": {
 			{
 				ViewName:    "main",
@@ -0,0 +1,140 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/commands"
+	"github.com/jesseduffield/lazygit/pkg/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestApplyUserKeybindings is a function.
+func TestApplyUserKeybindings(t *testing.T) {
+	type scenario struct {
+		testName        string
+		userKeybindings map[string]string
+		expectedPush    interface{}
+		expectedPull    interface{}
+	}
+
+	scenarios := []scenario{
+		{
+			"no overrides",
+			map[string]string{},
+			'P',
+			'p',
+		},
+		{
+			"override with a ctrl key",
+			map[string]string{"universal.push": "ctrl+p"},
+			gocui.KeyCtrlP,
+			'p',
+		},
+		{
+			"override with a named key",
+			map[string]string{"universal.pull": "enter"},
+			'P',
+			gocui.KeyEnter,
+		},
+		{
+			"swapping two keys",
+			map[string]string{"universal.push": "p", "universal.pull": "P"},
+			'p',
+			'P',
+		},
+		{
+			"unknown action is ignored",
+			map[string]string{"universal.doesNotExist": "x"},
+			'P',
+			'p',
+		},
+		{
+			"invalid key is ignored",
+			map[string]string{"universal.push": "hyper+p"},
+			'P',
+			'p',
+		},
+		{
+			"remapping onto another action's key is rejected",
+			map[string]string{"universal.push": "p"},
+			'P',
+			'p',
+		},
+		{
+			"two actions remapped onto the same key are both rejected",
+			map[string]string{"universal.push": "x", "universal.pull": "x"},
+			'P',
+			'p',
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gui := &Gui{
+				Log:    commands.NewDummyLog(),
+				Config: commands.NewDummyAppConfig(),
+			}
+			for action, key := range s.userKeybindings {
+				gui.Config.GetUserConfig().Set("keybinding."+action, key)
+			}
+			push := &Binding{ViewName: "", Key: 'P', Modifier: gocui.ModNone, Action: "universal.push"}
+			pull := &Binding{ViewName: "", Key: 'p', Modifier: gocui.ModNone, Action: "universal.pull"}
+
+			gui.applyUserKeybindings([]*Binding{push, pull})
+
+			assert.EqualValues(t, s.expectedPush, push.Key)
+			assert.EqualValues(t, s.expectedPull, pull.Key)
+		})
+	}
+}
+
+// TestApplyUserKeybindingsSidePanels is a function.
+func TestApplyUserKeybindingsSidePanels(t *testing.T) {
+	gui := &Gui{
+		Log:    commands.NewDummyLog(),
+		Config: commands.NewDummyAppConfig(),
+	}
+	gui.Config.GetUserConfig().Set("keybinding.universal.cycleDiffAlgorithm", "ctrl+y")
+	files := &Binding{ViewName: "files", Key: gocui.KeyCtrlW, Modifier: gocui.ModNone, Action: "universal.cycleDiffAlgorithm"}
+	commits := &Binding{ViewName: "commits", Key: gocui.KeyCtrlW, Modifier: gocui.ModNone, Action: "universal.cycleDiffAlgorithm"}
+	commitMessage := &Binding{ViewName: "commitMessage", Key: gocui.KeyCtrlW, Modifier: gocui.ModNone}
+
+	gui.applyUserKeybindings([]*Binding{files, commits, commitMessage})
+
+	assert.EqualValues(t, gocui.KeyCtrlY, files.Key)
+	assert.EqualValues(t, gocui.KeyCtrlY, commits.Key)
+	assert.EqualValues(t, gocui.KeyCtrlW, commitMessage.Key)
+}
+
+// TestInitialKeybindingActions is a function.
+func TestInitialKeybindingActions(t *testing.T) {
+	gui := &Gui{
+		Log:    commands.NewDummyLog(),
+		Config: commands.NewDummyAppConfig(),
+		Tr:     i18n.NewLocalizer(commands.NewDummyLog()),
+	}
+
+	type bindingKey struct {
+		viewName string
+		action   string
+	}
+	seen := map[bindingKey]bool{}
+	for _, binding := range gui.GetInitialKeybindings() {
+		if binding.Action == "" {
+			continue
+		}
+
+		// an action is named after the view it's bound in
+		section := strings.SplitN(binding.Action, ".", 2)[0]
+		if section != "universal" {
+			assert.EqualValues(t, binding.ViewName, section, binding.Action)
+		}
+		assert.NotEmpty(t, keyToConfigString(binding.Key), binding.Action)
+
+		key := bindingKey{viewName: binding.ViewName, action: binding.Action}
+		assert.False(t, seen[key], "%s is bound twice in the same view", binding.Action)
+		seen[key] = true
+	}
+}
@@ -5,12 +5,14 @@ import (
 	"github.com/jesseduffield/lazygit/pkg/commands"
 )
 
-// Currently there are two 'pseudo-panels' that make use of this 'pseudo-panel'.
-// One is the staging panel where we stage files line-by-line, the other is the
-// patch building panel where we add lines of an old commit's file to a patch.
+// Currently there are three 'pseudo-panels' that make use of this 'pseudo-panel'.
+// One is the staging panel where we stage files line-by-line, another is the
+// patch building panel where we add lines of an old commit's file to a patch,
+// and the last is the restoring panel where we bring lines of an old commit's
+// file back into the working tree.
 // This file contains the logic around selecting lines and displaying the diffs
-// staging_panel.go and patch_building_panel.go have functions specific to their
-// use cases
+// staging_panel.go, patch_building_panel.go and restoring_panel.go have
+// functions specific to their use cases
 
 // these represent what select mode we're in
 const (
@@ -20,7 +20,7 @@ func (gui *Gui) refreshPatchBuildingPanel(selectedLineIdx int) error {
 		return gui.renderString(gui.g, "commitFiles", gui.Tr.SLocalize("NoCommiteFiles"))
 	}
 
-	diff, err := gui.GitCommand.ShowCommitFile(commitFile.Sha, commitFile.Name, true)
+	diff, err := gui.GitCommand.ShowCommitFile(commitFile.Sha, commitFile.Name, "", true)
 	if err != nil {
 		return err
 	}
@@ -22,7 +22,7 @@ func (gui *Gui) handleCreateRebaseOptionsMenu(g *gocui.Gui, v *gocui.View) error
 		{value: "abort"},
 	}
 
-	if gui.State.WorkingTreeState == "rebasing" {
+	if gui.State.WorkingTreeState == "rebasing" || gui.State.WorkingTreeState == "cherry-picking" || gui.State.WorkingTreeState == "reverting" || gui.State.WorkingTreeState == "applying" {
 		options = append(options, &option{value: "skip"})
 	}
 
@@ -37,9 +37,16 @@ func (gui *Gui) handleCreateRebaseOptionsMenu(g *gocui.Gui, v *gocui.View) error
 	}
 
 	var title string
-	if gui.State.WorkingTreeState == "merging" {
+	switch gui.State.WorkingTreeState {
+	case "merging":
 		title = gui.Tr.SLocalize("MergeOptionsTitle")
-	} else {
+	case "cherry-picking":
+		title = gui.Tr.SLocalize("CherryPickOptionsTitle")
+	case "reverting":
+		title = gui.Tr.SLocalize("RevertOptionsTitle")
+	case "applying":
+		title = gui.Tr.SLocalize("AmOptionsTitle")
+	default:
 		title = gui.Tr.SLocalize("RebaseOptionsTitle")
 	}
 
@@ -49,13 +56,18 @@ func (gui *Gui) handleCreateRebaseOptionsMenu(g *gocui.Gui, v *gocui.View) error
 func (gui *Gui) genericMergeCommand(command string) error {
 	status := gui.State.WorkingTreeState
 
-	if status != "merging" && status != "rebasing" {
+	// we should end up with a command like 'git merge --continue'
+	commandType, ok := map[string]string{
+		"merging":        "merge",
+		"rebasing":       "rebase",
+		"cherry-picking": "cherry-pick",
+		"reverting":      "revert",
+		"applying":       "am",
+	}[status]
+	if !ok {
 		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("NotMergingOrRebasing"))
 	}
 
-	commandType := strings.Replace(status, "ing", "e", 1)
-	// we should end up with a command like 'git merge --continue'
-
 	// it's impossible for a rebase to require a commit so we'll use a subprocess only if it's a merge
 	if status == "merging" && command != "abort" && gui.Config.GetUserConfig().GetBool("git.merging.manualCommit") {
 		sub := gui.OSCommand.PrepareSubProcess("git", commandType, fmt.Sprintf("--%s", command))
@@ -65,13 +77,65 @@ func (gui *Gui) genericMergeCommand(command string) error {
 		}
 		return nil
 	}
-	result := gui.GitCommand.GenericMerge(commandType, command)
+	var result error
+	switch {
+	case status == "cherry-picking" && command == "continue":
+		result = gui.GitCommand.CherryPickContinue()
+	case status == "cherry-picking" && command == "abort":
+		result = gui.GitCommand.CherryPickAbort()
+	default:
+		result = gui.GitCommand.GenericMerge(commandType, command)
+	}
 	if err := gui.handleGenericMergeCommandResult(result); err != nil {
 		return err
 	}
 	return nil
 }
 
+// handleRebaseContinue continues a rebase directly from the files panel, saving
+// the user a trip to the merge/rebase options menu once they've staged their
+// resolved files
+func (gui *Gui) handleRebaseContinue(g *gocui.Gui, v *gocui.View) error {
+	canContinue, err := gui.GitCommand.CanContinueRebase()
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	if !canContinue {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("CantContinueRebase"))
+	}
+
+	return gui.WithWaitingStatus(gui.Tr.SLocalize("RebasingStatus"), func() error {
+		err := gui.GitCommand.GenericMerge("rebase", "continue")
+		return gui.handleGenericMergeCommandResult(err)
+	})
+}
+
+// handleAmendAndContinueRebase is for when we've stopped at a commit to edit
+// it, allowing the user to amend their changes and carry on in one go
+func (gui *Gui) handleAmendAndContinueRebase(g *gocui.Gui, v *gocui.View) error {
+	if gui.State.WorkingTreeState != "rebasing" {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NotRebasing"))
+	}
+
+	sub, err := gui.GitCommand.AmendAndContinueRebase()
+	if sub != nil {
+		gui.SubProcess = sub
+		return gui.Errors.ErrSubProcess
+	}
+	return gui.handleGenericMergeCommandResult(err)
+}
+
+// handleAbortAll is an escape hatch for when the repo is in a state the user
+// doesn't know how to get out of
+func (gui *Gui) handleAbortAll(g *gocui.Gui, v *gocui.View) error {
+	return gui.createConfirmationPanel(g, v, true, gui.Tr.SLocalize("AbortAllTitle"), gui.Tr.SLocalize("AbortAllPrompt"), func(g *gocui.Gui, v *gocui.View) error {
+		if err := gui.GitCommand.AbortAll(); err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		return gui.refreshSidePanels(g)
+	}, nil)
+}
+
 func (gui *Gui) handleGenericMergeCommandResult(result error) error {
 	if err := gui.refreshSidePanels(gui.g); err != nil {
 		return err
@@ -84,7 +148,7 @@ func (gui *Gui) handleGenericMergeCommandResult(result error) error {
 		return gui.genericMergeCommand("skip")
 	} else if strings.Contains(result.Error(), "The previous cherry-pick is now empty") {
 		return gui.genericMergeCommand("continue")
-	} else if strings.Contains(result.Error(), "When you have resolved this problem") || strings.Contains(result.Error(), "fix conflicts") || strings.Contains(result.Error(), "Resolve all conflicts manually") {
+	} else if strings.Contains(result.Error(), "When you have resolved this problem") || strings.Contains(result.Error(), "fix conflicts") || strings.Contains(result.Error(), "Resolve all conflicts manually") || strings.Contains(strings.ToLower(result.Error()), "after resolving the conflicts") {
 		return gui.createConfirmationPanel(gui.g, gui.getFilesView(), true, gui.Tr.SLocalize("FoundConflictsTitle"), gui.Tr.SLocalize("FoundConflicts"),
 			func(g *gocui.Gui, v *gocui.View) error {
 				return nil
@@ -0,0 +1,96 @@
+package gui
+
+import (
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/commands"
+)
+
+// the restoring panel shows the difference between a file in the working tree
+// and its version in an old commit, letting the user bring individual lines of
+// the old version back into the working tree
+
+func (gui *Gui) handleEnterRestoringPanel(g *gocui.Gui, v *gocui.View) error {
+	commitFile := gui.getSelectedCommitFile(g)
+	if commitFile == nil {
+		return gui.renderString(g, "commitFiles", gui.Tr.SLocalize("NoCommiteFiles"))
+	}
+
+	if err := gui.changeContext("restoring"); err != nil {
+		return err
+	}
+	if err := gui.switchFocus(g, v, gui.getMainView()); err != nil {
+		return err
+	}
+	return gui.refreshRestoringPanel()
+}
+
+func (gui *Gui) refreshRestoringPanel() error {
+	commitFile := gui.getSelectedCommitFile(gui.g)
+	if commitFile == nil {
+		return gui.handleEscapeRestoringPanel(gui.g, nil)
+	}
+
+	gui.State.SplitMainPanel = false
+	gui.getMainView().Title = gui.Tr.TemplateLocalize(
+		"RestoringTitle",
+		Teml{
+			"fileName": commitFile.Name,
+			"rev":      commitFile.Sha,
+		},
+	)
+
+	diff, err := gui.GitCommand.DiffFileAgainstRevision(commitFile.Sha, commitFile.Name)
+	if err != nil {
+		_ = gui.handleEscapeRestoringPanel(gui.g, nil)
+		return gui.createErrorPanel(gui.g, err.Error())
+	}
+
+	empty, err := gui.refreshLineByLinePanel(diff, "", false, -1)
+	if err != nil {
+		return err
+	}
+
+	if empty {
+		_ = gui.handleEscapeRestoringPanel(gui.g, nil)
+		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("NothingToRestore"))
+	}
+
+	return nil
+}
+
+func (gui *Gui) handleRestoreSelection(g *gocui.Gui, v *gocui.View) error {
+	state := gui.State.Panels.LineByLine
+
+	commitFile := gui.getSelectedCommitFile(g)
+	if commitFile == nil {
+		return gui.renderString(g, "commitFiles", gui.Tr.SLocalize("NoCommiteFiles"))
+	}
+
+	patch := commands.RestorePatchForRange(gui.Log, commitFile.Name, state.Diff, state.FirstLineIdx, state.LastLineIdx)
+	if patch == "" {
+		return nil
+	}
+
+	if err := gui.GitCommand.ApplyPatch(patch); err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+
+	if state.SelectMode == RANGE {
+		state.SelectMode = LINE
+	}
+
+	if err := gui.refreshFiles(); err != nil {
+		return err
+	}
+
+	return gui.refreshRestoringPanel()
+}
+
+func (gui *Gui) handleEscapeRestoringPanel(g *gocui.Gui, v *gocui.View) error {
+	gui.State.Panels.LineByLine = nil
+	if err := gui.changeContext("normal"); err != nil {
+		return err
+	}
+
+	return gui.switchFocus(gui.g, nil, gui.getCommitFilesView())
+}
@@ -38,11 +38,11 @@ func (gui *Gui) refreshStagingPanel(forceSecondaryFocused bool, selectedLineIdx
 	}
 
 	if secondaryFocused {
-		gui.getMainView().Title = gui.Tr.SLocalize("StagedChanges")
-		gui.getSecondaryView().Title = gui.Tr.SLocalize("UnstagedChanges")
+		gui.getMainView().Title = gui.diffTitle(true)
+		gui.getSecondaryView().Title = gui.diffTitle(false)
 	} else {
-		gui.getMainView().Title = gui.Tr.SLocalize("UnstagedChanges")
-		gui.getSecondaryView().Title = gui.Tr.SLocalize("StagedChanges")
+		gui.getMainView().Title = gui.diffTitle(false)
+		gui.getSecondaryView().Title = gui.diffTitle(true)
 	}
 
 	// note for custom diffs, we'll need to send a flag here saying not to use the custom diff
@@ -71,6 +71,8 @@ func (gui *Gui) refreshStagingPanel(forceSecondaryFocused bool, selectedLineIdx
 	return nil
 }
 
+// handleTogglePanelClick switches to the diff shown in the secondary panel,
+// which is always the opposite of the one in the main panel
 func (gui *Gui) handleTogglePanelClick(g *gocui.Gui, v *gocui.View) error {
 	state := gui.State.Panels.LineByLine
 
@@ -115,6 +117,9 @@ func (gui *Gui) applySelection(reverse bool) error {
 	patch := commands.ModifiedPatchForRange(gui.Log, file.Name, state.Diff, state.FirstLineIdx, state.LastLineIdx, reverse, false)
 
 	if patch == "" {
+		if !reverse && !state.SecondaryFocused {
+			return gui.explainStagedSelection(file)
+		}
 		return nil
 	}
 
@@ -142,6 +147,56 @@ func (gui *Gui) applySelection(reverse bool) error {
 	return nil
 }
 
+// explainStagedSelection is for when there's nothing to stage in the selected
+// range of the unstaged diff. If that's because the lines are already staged we
+// tell the user where to go to unstage them
+func (gui *Gui) explainStagedSelection(file *commands.File) error {
+	state := gui.State.Panels.LineByLine
+
+	firstLine := commands.DiffLineNumber(state.Diff, state.FirstLineIdx)
+	lastLine := commands.DiffLineNumber(state.Diff, state.LastLineIdx)
+	if firstLine == -1 || lastLine == -1 {
+		return nil
+	}
+
+	staged, err := gui.GitCommand.HunkStagedState(file, firstLine, lastLine)
+	if err != nil || !staged {
+		return err
+	}
+	return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("SelectionAlreadyStaged"))
+}
+
+// handleEditHunk lets the user edit the selected hunk in their editor before
+// staging it, like the 'e' option of `git add -p`
+func (gui *Gui) handleEditHunk(g *gocui.Gui, v *gocui.View) error {
+	state := gui.State.Panels.LineByLine
+
+	if state.SecondaryFocused {
+		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("CantEditStagedHunk"))
+	}
+
+	file, err := gui.getSelectedFile(gui.g)
+	if err != nil {
+		return err
+	}
+
+	hunkPatch := commands.HunkPatchForLine(gui.Log, file.Name, state.Diff, state.SelectedLineIdx)
+	if hunkPatch == "" {
+		return nil
+	}
+
+	path, sub, err := gui.GitCommand.EditHunk(hunkPatch)
+	if err != nil {
+		return gui.createErrorPanel(gui.g, err.Error())
+	}
+
+	gui.onSubProcDone = func() error {
+		return gui.GitCommand.ApplyEditedHunk(path)
+	}
+	gui.SubProcess = sub
+	return gui.Errors.ErrSubProcess
+}
+
 func (gui *Gui) handleMouseDownSecondaryWhileStaging(g *gocui.Gui, v *gocui.View) error {
 	state := gui.State.Panels.LineByLine
 
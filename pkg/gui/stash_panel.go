@@ -30,7 +30,7 @@ func (gui *Gui) handleStashEntrySelect(g *gocui.Gui, v *gocui.View) error {
 		return err
 	}
 
-	gui.getMainView().Title = "Stash"
+	gui.getMainView().Title = gui.withDiffAlgorithm("Stash")
 
 	stashEntry := gui.getSelectedStashEntry(v)
 	if stashEntry == nil {
@@ -39,14 +39,40 @@ func (gui *Gui) handleStashEntrySelect(g *gocui.Gui, v *gocui.View) error {
 	if err := gui.focusPoint(0, gui.State.Panels.Stash.SelectedLine, len(gui.State.StashEntries), v); err != nil {
 		return err
 	}
+
+	gui.State.SplitMainPanel = true
+	diffAgainstWorkingTree := gui.State.Panels.Stash.DiffAgainstWorkingTree
+	if diffAgainstWorkingTree {
+		gui.getSecondaryView().Title = gui.Tr.SLocalize("StashWorkingTreeDiffTitle")
+	} else {
+		gui.getSecondaryView().Title = gui.Tr.SLocalize("StashStatTitle")
+	}
 	go func() {
 		// doing this asynchronously cos it can take time
 		diff, _ := gui.GitCommand.GetStashEntryDiff(stashEntry.Index)
 		_ = gui.renderString(g, "main", diff)
+		if diffAgainstWorkingTree {
+			workingTreeDiff, _ := gui.GitCommand.DiffStashAgainstWorkingTree(stashEntry.Index)
+			if workingTreeDiff == "" {
+				workingTreeDiff = gui.Tr.SLocalize("StashMatchesWorkingTree")
+			}
+			_ = gui.renderString(g, "secondary", workingTreeDiff)
+			return
+		}
+		stat, _ := gui.GitCommand.GetStashStat(stashEntry.Index)
+		_ = gui.renderString(g, "secondary", stat)
 	}()
 	return nil
 }
 
+// handleToggleStashWorkingTreeDiff switches the secondary panel between the
+// selected stash entry's stat and its diff against the working tree, which
+// tells us whether the entry can be dropped without losing anything
+func (gui *Gui) handleToggleStashWorkingTreeDiff(g *gocui.Gui, v *gocui.View) error {
+	gui.State.Panels.Stash.DiffAgainstWorkingTree = !gui.State.Panels.Stash.DiffAgainstWorkingTree
+	return gui.handleStashEntrySelect(g, v)
+}
+
 func (gui *Gui) refreshStashEntries(g *gocui.Gui) error {
 	g.Update(func(g *gocui.Gui) error {
 		gui.State.StashEntries = gui.GitCommand.GetStashEntries()
@@ -135,10 +161,51 @@ func (gui *Gui) stashDo(g *gocui.Gui, v *gocui.View, method string) error {
 	return gui.refreshFiles()
 }
 
+type stashFileOption struct {
+	fileName string
+}
+
+// GetDisplayStrings is a function.
+func (o *stashFileOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.fileName}
+}
+
+// handleCreateRestoreStashFileMenu lets the user bring back a single file from
+// the selected stash entry without applying the whole thing
+func (gui *Gui) handleCreateRestoreStashFileMenu(g *gocui.Gui, v *gocui.View) error {
+	stashEntry := gui.getSelectedStashEntry(v)
+	if stashEntry == nil {
+		return nil
+	}
+
+	fileNames, err := gui.GitCommand.GetStashEntryFiles(stashEntry.Index)
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+
+	options := make([]*stashFileOption, len(fileNames))
+	for i, fileName := range fileNames {
+		options[i] = &stashFileOption{fileName: fileName}
+	}
+
+	handleMenuPress := func(index int) error {
+		if err := gui.GitCommand.RestoreStashFile(stashEntry.Index, options[index].fileName); err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		return gui.refreshFiles()
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("RestoreStashFileTitle"), options, len(options), handleMenuPress)
+}
+
 func (gui *Gui) handleStashSave(stashFunc func(message string) error) error {
 	if len(gui.trackedFiles()) == 0 && len(gui.stagedFiles()) == 0 {
 		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("NoTrackedStagedFilesStash"))
 	}
+	return gui.promptForStashMessage(stashFunc)
+}
+
+func (gui *Gui) promptForStashMessage(stashFunc func(message string) error) error {
 	return gui.createPromptPanel(gui.g, gui.getFilesView(), gui.Tr.SLocalize("StashChanges"), "", func(g *gocui.Gui, v *gocui.View) error {
 		if err := stashFunc(gui.trimmedContent(v)); err != nil {
 			gui.createErrorPanel(g, err.Error())
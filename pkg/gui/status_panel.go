@@ -2,13 +2,75 @@ package gui
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/commands"
 	"github.com/jesseduffield/lazygit/pkg/utils"
 )
 
+// getCommitCount returns the number of commits reachable from HEAD, only
+// asking git again once HEAD has moved
+func (gui *Gui) getCommitCount() (int, error) {
+	state := gui.State.Panels.Status
+	sha := gui.headSha()
+	if sha != "" && sha == state.commitCountSha {
+		return state.commitCount, nil
+	}
+	commitCount, err := gui.GitCommand.GetCommitCount()
+	if err != nil {
+		return 0, err
+	}
+	state.commitCount, state.commitCountSha = commitCount, sha
+	return commitCount, nil
+}
+
+// headSha returns the sha of the HEAD commit as of the last commits refresh,
+// skipping over any commits we're in the process of rebasing
+func (gui *Gui) headSha() string {
+	for _, commit := range gui.State.Commits {
+		if commit.Status != "rebasing" {
+			return commit.Sha
+		}
+	}
+	return ""
+}
+
+// hasDirtySubmodules tells us whether a submodule's checked out commit differs
+// from the recorded one, only asking git when the tracked changed files differ
+// from last time
+func (gui *Gui) hasDirtySubmodules() bool {
+	state := gui.State.Panels.Status
+	key := trackedChangesKey(gui.State.Files)
+	if key == "" {
+		return false
+	}
+	if key == state.dirtySubmodulesKey {
+		return state.dirtySubmodules
+	}
+	dirty, err := gui.GitCommand.HasDirtySubmodules()
+	if err != nil {
+		return false
+	}
+	state.dirtySubmodules, state.dirtySubmodulesKey = dirty, key
+	return dirty
+}
+
+// trackedChangesKey sums up the tracked files with changes, given a dirty
+// submodule will always be one of them
+func trackedChangesKey(files []*commands.File) string {
+	key := ""
+	for _, file := range files {
+		if file.Tracked {
+			key += file.ShortStatus + " " + file.Name + "\n"
+		}
+	}
+	return key
+}
+
 func (gui *Gui) refreshStatus(g *gocui.Gui) error {
 	state := gui.State.Panels.Status
 
@@ -21,6 +83,7 @@ func (gui *Gui) refreshStatus(g *gocui.Gui) error {
 	// contents end up cleared
 	g.Update(func(*gocui.Gui) error {
 		v.Clear()
+		v.Title = fmt.Sprintf("%s (%s)", gui.Tr.SLocalize("StatusTitle"), commands.FormatChangesSummary(gui.State.Files))
 		state.pushables, state.pullables = gui.GitCommand.GetCurrentBranchUpstreamDifferenceCount()
 		if err := gui.updateWorkTreeState(); err != nil {
 			return err
@@ -32,6 +95,19 @@ func (gui *Gui) refreshStatus(g *gocui.Gui) error {
 			status += utils.ColoredString(fmt.Sprintf(" (%s)", gui.State.WorkingTreeState), color.FgYellow)
 		}
 
+		// committing in this state makes commits that are easily lost, so we
+		// make it obvious
+		state.detachedHead, _ = gui.GitCommand.IsDetachedHead()
+		if state.detachedHead {
+			status += utils.ColoredString(fmt.Sprintf(" (%s)", gui.Tr.SLocalize("DetachedHead")), color.FgRed)
+		}
+
+		// the files panel only shows a submodule as modified, so we call out
+		// that its checked out commit isn't the recorded one
+		if gui.hasDirtySubmodules() {
+			status += utils.ColoredString(fmt.Sprintf(" (%s)", gui.Tr.SLocalize("DirtySubmodules")), color.FgYellow)
+		}
+
 		if len(branches) > 0 {
 			branch := branches[0]
 			name := utils.ColoredString(branch.Name, branch.GetColor())
@@ -39,6 +115,24 @@ func (gui *Gui) refreshStatus(g *gocui.Gui) error {
 			status += fmt.Sprintf(" %s → %s", repoName, name)
 		}
 
+		if commitCount, err := gui.getCommitCount(); err == nil {
+			status += " " + gui.Tr.TemplateLocalize(
+				"CommitCount",
+				Teml{
+					"count": strconv.Itoa(commitCount),
+				},
+			)
+		}
+
+		if lastFetchTime, err := gui.GitCommand.LastFetchTime(); err == nil && !lastFetchTime.IsZero() {
+			status += " " + gui.Tr.TemplateLocalize(
+				"LastFetched",
+				Teml{
+					"time": utils.FormatRelativeDuration(time.Since(lastFetchTime)),
+				},
+			)
+		}
+
 		fmt.Fprint(v, status)
 		return nil
 	})
@@ -67,7 +161,7 @@ func (gui *Gui) handleStatusClick(g *gocui.Gui, v *gocui.View) error {
 	repoName := utils.GetCurrentRepoName()
 	gui.Log.Warn(gui.State.WorkingTreeState)
 	switch gui.State.WorkingTreeState {
-	case "rebasing", "merging":
+	case "rebasing", "merging", "cherry-picking", "reverting", "applying":
 		workingTreeStatus := fmt.Sprintf("(%s)", gui.State.WorkingTreeState)
 		if cursorInSubstring(cx, upstreamStatus+" ", workingTreeStatus) {
 			return gui.handleCreateRebaseOptionsMenu(gui.g, v)
@@ -113,6 +207,121 @@ func (gui *Gui) handleStatusSelect(g *gocui.Gui, v *gocui.View) error {
 	return gui.renderString(g, "main", dashboardString)
 }
 
+func (gui *Gui) handleShowShortlog(g *gocui.Gui, v *gocui.View) error {
+	gui.State.SplitMainPanel = false
+	gui.getMainView().Title = gui.Tr.SLocalize("ShortlogTitle")
+	if err := gui.renderString(g, "main", gui.Tr.SLocalize("LoadingShortlog")); err != nil {
+		return err
+	}
+
+	// this can take a while on large repos so we don't want to block the UI
+	go func() {
+		shortlog, err := gui.GitCommand.GetShortlog()
+		if err != nil {
+			_ = gui.createErrorPanel(g, err.Error())
+			return
+		}
+		_ = gui.renderString(g, "main", shortlog)
+	}()
+	return nil
+}
+
+// handleShowReflogSince answers 'what have I done today?' by listing the reflog
+// entries since a time of the user's choosing
+func (gui *Gui) handleShowReflogSince(g *gocui.Gui, v *gocui.View) error {
+	return gui.createPromptPanel(g, v, gui.Tr.SLocalize("ReflogSincePrompt"), "1 day ago", func(g *gocui.Gui, promptView *gocui.View) error {
+		since := gui.trimmedContent(promptView)
+		entries, err := gui.GitCommand.GetReflogSince(since)
+		if err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+
+		gui.State.SplitMainPanel = false
+		gui.getMainView().Title = gui.Tr.TemplateLocalize(
+			"ReflogSinceTitle",
+			Teml{
+				"since": since,
+			},
+		)
+		list, err := utils.RenderList(entries, false)
+		if err != nil {
+			return err
+		}
+		if list == "" {
+			list = gui.Tr.SLocalize("NoReflogEntries")
+		}
+		return gui.renderString(g, "main", list)
+	})
+}
+
+// handleCreateBranchFromDetachedHead gives the commits made on a detached HEAD
+// a branch to live on
+func (gui *Gui) handleCreateBranchFromDetachedHead(g *gocui.Gui, v *gocui.View) error {
+	if !gui.State.Panels.Status.detachedHead {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NotDetachedHead"))
+	}
+
+	return gui.createPromptPanel(g, v, gui.Tr.SLocalize("NewBranchAtDetachedHead"), "", func(g *gocui.Gui, promptView *gocui.View) error {
+		if err := gui.GitCommand.NewBranch(gui.trimmedContent(promptView)); err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		return gui.refreshSidePanels(g)
+	})
+}
+
+// handleRunMaintenance runs git's housekeeping tasks on demand, showing whether
+// they're also scheduled to run in the background
+func (gui *Gui) handleRunMaintenance(g *gocui.Gui, v *gocui.View) error {
+	strategy, err := gui.GitCommand.GetMaintenanceStrategy()
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	if strategy == "" {
+		strategy = gui.Tr.SLocalize("MaintenanceNotScheduled")
+	}
+
+	gui.State.SplitMainPanel = false
+	gui.getMainView().Title = gui.Tr.TemplateLocalize(
+		"MaintenanceTitle",
+		Teml{
+			"strategy": strategy,
+		},
+	)
+
+	return gui.WithWaitingStatus(gui.Tr.SLocalize("RunningMaintenanceStatus"), func() error {
+		output, err := gui.GitCommand.RunMaintenance()
+		if err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		if output == "" {
+			output = gui.Tr.SLocalize("MaintenanceDone")
+		}
+		return gui.renderString(g, "main", output)
+	})
+}
+
+// handleCreateHooksMenu lists the repo's hooks, letting the user enable or
+// disable one by selecting it
+func (gui *Gui) handleCreateHooksMenu(g *gocui.Gui, v *gocui.View) error {
+	hooks, err := gui.GitCommand.GetHooks()
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	if len(hooks) == 0 {
+		return gui.createMessagePanel(g, v, gui.Tr.SLocalize("HooksTitle"), gui.Tr.SLocalize("NoHooks"))
+	}
+
+	handleMenuPress := func(index int) error {
+		hook := hooks[index]
+		if err := gui.GitCommand.SetHookEnabled(hook, !hook.Enabled); err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		return nil
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("HooksTitle"), hooks, len(hooks), handleMenuPress)
+}
+
 func (gui *Gui) handleOpenConfig(g *gocui.Gui, v *gocui.View) error {
 	return gui.openFile(gui.Config.GetUserConfig().ConfigFileUsed())
 }
@@ -135,11 +344,44 @@ func lazygitTitle() string {
 }
 
 func (gui *Gui) updateWorkTreeState() error {
+	// cherry-pick conflicts also look like a merge so we check this first
+	cherryPicking, err := gui.GitCommand.CherryPickMode()
+	if err != nil {
+		return err
+	}
+	if cherryPicking {
+		gui.State.WorkingTreeState = "cherry-picking"
+		return nil
+	}
+	// git am keeps its state where a rebase would, so we check it first
+	applying, err := gui.GitCommand.AmMode()
+	if err != nil {
+		return err
+	}
+	if applying {
+		gui.State.WorkingTreeState = "applying"
+		return nil
+	}
+	// likewise a revert that stopped on conflicts
+	reverting, err := gui.GitCommand.RevertMode()
+	if err != nil {
+		return err
+	}
+	if reverting {
+		gui.State.WorkingTreeState = "reverting"
+		return nil
+	}
+	// a MERGE_HEAD means a merge (e.g. from a pull) stopped on conflicts, in
+	// which case an abort needs to go to `git merge --abort`
+	mergeHeadExists, err := gui.GitCommand.MergeMode()
+	if err != nil {
+		return err
+	}
 	merging, err := gui.GitCommand.IsInMergeState()
 	if err != nil {
 		return err
 	}
-	if merging {
+	if mergeHeadExists || merging {
 		gui.State.WorkingTreeState = "merging"
 		return nil
 	}
@@ -0,0 +1,86 @@
+package gui
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/commands"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetCommitCount is a function.
+func TestGetCommitCount(t *testing.T) {
+	calls := 0
+	osCommand := commands.NewDummyOSCommand()
+	osCommand.SetCommand(func(cmd string, args ...string) *exec.Cmd {
+		calls++
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"rev-list", "--count", "HEAD"}, args)
+
+		return exec.Command("echo", "12")
+	})
+
+	gui := &Gui{
+		Log:        commands.NewDummyLog(),
+		GitCommand: commands.NewDummyGitCommandWithOSCommand(osCommand),
+		State: guiState{
+			Commits: []*commands.Commit{
+				{Sha: "0123456789abcdef", Status: "rebasing"},
+				{Sha: "abcdef0123456789", Status: "unpushed"},
+			},
+			Panels: &panelStates{Status: &statusPanelState{}},
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		count, err := gui.getCommitCount()
+		assert.NoError(t, err)
+		assert.EqualValues(t, 12, count)
+	}
+	assert.EqualValues(t, 1, calls)
+	assert.EqualValues(t, "abcdef0123456789", gui.State.Panels.Status.commitCountSha)
+
+	// once HEAD moves we count again
+	gui.State.Commits = []*commands.Commit{{Sha: "fedcba9876543210", Status: "unpushed"}}
+	_, err := gui.getCommitCount()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, calls)
+}
+
+// TestHasDirtySubmodules is a function.
+func TestHasDirtySubmodules(t *testing.T) {
+	calls := 0
+	osCommand := commands.NewDummyOSCommand()
+	osCommand.SetCommand(func(cmd string, args ...string) *exec.Cmd {
+		calls++
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"submodule", "status"}, args)
+
+		return exec.Command("printf", "+5d6e7f8 lib/two (heads/master)")
+	})
+
+	gui := &Gui{
+		Log:        commands.NewDummyLog(),
+		GitCommand: commands.NewDummyGitCommandWithOSCommand(osCommand),
+		State: guiState{
+			Files: []*commands.File{
+				{Name: "new.txt", ShortStatus: "??"},
+			},
+			Panels: &panelStates{Status: &statusPanelState{}},
+		},
+	}
+
+	// without tracked changes there's nothing to ask git about
+	assert.False(t, gui.hasDirtySubmodules())
+	assert.EqualValues(t, 0, calls)
+
+	gui.State.Files = append(gui.State.Files, &commands.File{Name: "lib/two", ShortStatus: " M", Tracked: true})
+	for i := 0; i < 2; i++ {
+		assert.True(t, gui.hasDirtySubmodules())
+	}
+	assert.EqualValues(t, 1, calls)
+
+	gui.State.Files = append(gui.State.Files, &commands.File{Name: "file.txt", ShortStatus: "M ", Tracked: true})
+	assert.True(t, gui.hasDirtySubmodules())
+	assert.EqualValues(t, 2, calls)
+}
@@ -0,0 +1,163 @@
+package gui
+
+import (
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/commands"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// there's no tags panel as such: tags are listed in a menu opened from the
+// branches panel, and created from the commits panel
+
+func (gui *Gui) handleCreateTagsMenu(g *gocui.Gui, v *gocui.View) error {
+	tags, err := gui.GitCommand.GetTags()
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	if len(tags) == 0 {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoTags"))
+	}
+
+	handleMenuPress := func(index int) error {
+		return gui.createTagOptionsMenu(tags[index])
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("TagsTitle"), tags, len(tags), handleMenuPress)
+}
+
+type tagOption struct {
+	description string
+	handler     func() error
+}
+
+// GetDisplayStrings is a function.
+func (o *tagOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description}
+}
+
+func (gui *Gui) createTagOptionsMenu(tag *commands.Tag) error {
+	options := []*tagOption{
+		{
+			description: gui.Tr.SLocalize("showTag"),
+			handler: func() error {
+				return gui.showTag(tag)
+			},
+		},
+		{
+			description: gui.Tr.SLocalize("checkoutTag"),
+			handler: func() error {
+				return gui.checkoutTag(tag)
+			},
+		},
+		{
+			description: gui.Tr.SLocalize("cancel"),
+			handler: func() error {
+				return nil
+			},
+		},
+	}
+
+	handleMenuPress := func(index int) error {
+		return options[index].handler()
+	}
+
+	return gui.createMenu(tag.Name, options, len(options), handleMenuPress)
+}
+
+func (gui *Gui) showTag(tag *commands.Tag) error {
+	output, err := gui.GitCommand.ShowTag(tag.Name)
+	if err != nil {
+		return gui.createErrorPanel(gui.g, err.Error())
+	}
+	gui.State.SplitMainPanel = false
+	gui.getMainView().Title = tag.Name
+	return gui.renderString(gui.g, "main", output)
+}
+
+// checkoutTag checks out a tag after warning that new commits made there won't
+// belong to any branch
+func (gui *Gui) checkoutTag(tag *commands.Tag) error {
+	message := gui.Tr.TemplateLocalize(
+		"SureCheckoutTag",
+		Teml{
+			"tagName": tag.Name,
+		},
+	)
+	return gui.createConfirmationPanel(gui.g, gui.getBranchesView(), true, gui.Tr.SLocalize("CheckoutTagTitle"), message, func(g *gocui.Gui, v *gocui.View) error {
+		if err := gui.GitCommand.CheckoutTag(tag.Name); err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		return gui.refreshSidePanels(g)
+	}, nil)
+}
+
+// handleCreateLightweightTag tags the selected commit
+func (gui *Gui) handleCreateLightweightTag(g *gocui.Gui, v *gocui.View) error {
+	commit := gui.getSelectedCommit(g)
+	if commit == nil {
+		return nil
+	}
+
+	return gui.createPromptPanel(g, v, gui.Tr.SLocalize("TagNamePrompt"), "", func(g *gocui.Gui, promptView *gocui.View) error {
+		name := gui.trimmedContent(promptView)
+		if name == "" {
+			return nil
+		}
+		if err := gui.GitCommand.CreateLightweightTag(name, commit.Sha); err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		return gui.refreshCommits(g)
+	})
+}
+
+// handleCompareTags asks for two tags, one after the other, then shows the diff
+// between them in the main panel and the commits in between in the secondary
+// panel
+func (gui *Gui) handleCompareTags(g *gocui.Gui, v *gocui.View) error {
+	tags, err := gui.GitCommand.GetTags()
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	if len(tags) < 2 {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NotEnoughTagsToCompare"))
+	}
+
+	handleFromTagPress := func(fromIndex int) error {
+		handleToTagPress := func(toIndex int) error {
+			return gui.renderTagComparison(tags[fromIndex].Name, tags[toIndex].Name)
+		}
+
+		return gui.createMenu(gui.Tr.SLocalize("CompareToTagTitle"), tags, len(tags), handleToTagPress)
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("CompareFromTagTitle"), tags, len(tags), handleFromTagPress)
+}
+
+func (gui *Gui) renderTagComparison(fromTag, toTag string) error {
+	commits, err := gui.GitCommand.GetCommitsBetweenTags(fromTag, toTag)
+	if err != nil {
+		return gui.createErrorPanel(gui.g, err.Error())
+	}
+
+	gui.State.SplitMainPanel = true
+	gui.getMainView().Title = fromTag + ".." + toTag
+	gui.getSecondaryView().Title = gui.Tr.SLocalize("CommitsBetweenTagsTitle")
+
+	commitList := gui.Tr.SLocalize("NoCommitsBetweenTags")
+	if len(commits) > 0 {
+		commitList, err = utils.RenderList(commits, false)
+		if err != nil {
+			return err
+		}
+	}
+	if err := gui.renderString(gui.g, "secondary", commitList); err != nil {
+		return err
+	}
+
+	go func() {
+		// doing this asynchronously cos it can take time
+		diff, _ := gui.GitCommand.DiffTags(fromTag, toTag)
+		_ = gui.renderString(gui.g, "main", diff)
+	}()
+	return nil
+}
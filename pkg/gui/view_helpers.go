@@ -120,7 +120,8 @@ func (gui *Gui) newLineFocused(g *gocui.Gui, v *gocui.View) error {
 		if gui.State.Context == "merging" {
 			return gui.refreshMergePanel()
 		}
-		v.Highlight = false
+		// in blame mode we highlight the line whose commit enter would go to
+		v.Highlight = gui.State.Context == "blame"
 		return nil
 	default:
 		panic(gui.Tr.SLocalize("NoViewMachingNewLineFocusedSwitchStatement"))
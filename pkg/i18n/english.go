@@ -846,6 +846,648 @@ func addEnglish(i18nObject *i18n.Bundle) error {
 		}, &i18n.Message{
 			ID:    "notTrackingRemote",
 			Other: "(not tracking any remote)",
+		}, &i18n.Message{
+			ID:    "continueRebase",
+			Other: "continue rebase",
+		}, &i18n.Message{
+			ID:    "CantContinueRebase",
+			Other: "You can only continue a rebase once all merge conflicts have been resolved",
+		}, &i18n.Message{
+			ID:    "showFileAtRevision",
+			Other: "show file at revision",
+		}, &i18n.Message{
+			ID:    "EnterRevision",
+			Other: "Revision (e.g. HEAD~5):",
+		}, &i18n.Message{
+			ID:    "abortAll",
+			Other: "abort in-progress merge/rebase/cherry-pick/revert",
+		}, &i18n.Message{
+			ID:    "AbortAllTitle",
+			Other: "Abort",
+		}, &i18n.Message{
+			ID:    "AbortAllPrompt",
+			Other: "Are you sure you want to abort the current operation? Any progress made in it will be lost",
+		}, &i18n.Message{
+			ID:    "NothingToAbort",
+			Other: "There is no merge, rebase, cherry-pick or revert in progress",
+		}, &i18n.Message{
+			ID:    "amendFileToHead",
+			Other: "amend last commit with only this file",
+		}, &i18n.Message{
+			ID:    "SureToAmendFile",
+			Other: "Are you sure you want to amend the last commit with the changes to {{.fileName}}? Other staged files will be left alone",
+		}, &i18n.Message{
+			ID:    "compareWithCurrentBranch",
+			Other: "view files changed compared with checked out branch",
+		}, &i18n.Message{
+			ID:    "CantCompareBranchWithItself",
+			Other: "You cannot compare a branch with itself",
+		}, &i18n.Message{
+			ID:    "FilesChangedTitle",
+			Other: "Files changed: {{.checkedOutBranch}}...{{.selectedBranch}}",
+		}, &i18n.Message{
+			ID:    "NoFilesChanged",
+			Other: "No files changed",
+		}, &i18n.Message{
+			ID:    "NoDefaultBranch",
+			Other: "Could not determine the default branch of this repo",
+		}, &i18n.Message{
+			ID:    "ToggleSkipHooksOption",
+			Other: "{{.keyBindToggle}}: toggle skipping hooks",
+		}, &i18n.Message{
+			ID:    "SkippingHooks",
+			Other: "(skipping hooks)",
+		}, &i18n.Message{
+			ID:    "viewDirectoryLog",
+			Other: "view log of a directory",
+		}, &i18n.Message{
+			ID:    "EnterDirectory",
+			Other: "Directory:",
+		}, &i18n.Message{
+			ID:    "DirectoryLogTitle",
+			Other: "Log: {{.dir}}",
+		}, &i18n.Message{
+			ID:    "resetFileToHead",
+			Other: "reset staged and unstaged changes to HEAD",
+		}, &i18n.Message{
+			ID:    "showShortlog",
+			Other: "show commit count per author",
+		}, &i18n.Message{
+			ID:    "ShortlogTitle",
+			Other: "Commits per author",
+		}, &i18n.Message{
+			ID:    "LoadingShortlog",
+			Other: "loading...",
+		}, &i18n.Message{
+			ID:    "amendAndContinueRebase",
+			Other: "amend last commit and continue rebase",
+		}, &i18n.Message{
+			ID:    "NotRebasing",
+			Other: "You are not in the middle of a rebase",
+		}, &i18n.Message{
+			ID: "EditHunkInstructions",
+			Other: `# ---
+# To remove '-' lines, make them ' ' lines (context).
+# To remove '+' lines, delete them.
+# Lines starting with # will be removed.
+# If you delete the whole hunk nothing will be staged.
+`,
+		}, &i18n.Message{
+			ID:    "editHunk",
+			Other: "edit hunk",
+		}, &i18n.Message{
+			ID:    "CantEditStagedHunk",
+			Other: "You can only edit unstaged hunks",
+		}, &i18n.Message{
+			ID:    "compareCommitsWithCurrentBranch",
+			Other: "view commits not shared with checked out branch",
+		}, &i18n.Message{
+			ID:    "CompareCommitsTitle",
+			Other: "Compare commits",
+		}, &i18n.Message{
+			ID:    "CommitsNotInSelectedBranch",
+			Other: "commits on {{.checkedOutBranch}} not in {{.selectedBranch}}",
+		}, &i18n.Message{
+			ID:    "CommitsNotInCheckedOutBranch",
+			Other: "commits on {{.selectedBranch}} not in {{.checkedOutBranch}}",
+		}, &i18n.Message{
+			ID:    "NoCommitsInRange",
+			Other: "No commits",
+		}, &i18n.Message{
+			ID:    "blameFileAtCommit",
+			Other: "blame file as of this commit",
+		}, &i18n.Message{
+			ID:    "BlameTitle",
+			Other: "Blame: {{.fileName}} @ {{.rev}}",
+		}, &i18n.Message{
+			ID:    "pushAndCreatePullRequest",
+			Other: "push checked out branch and create pull request",
+		}, &i18n.Message{
+			ID:    "applyPatchFile",
+			Other: "apply patch file",
+		}, &i18n.Message{
+			ID:    "EnterPatchFilePath",
+			Other: "Path to patch file:",
+		}, &i18n.Message{
+			ID:    "applyPatchToWorkingTree",
+			Other: "apply to working tree",
+		}, &i18n.Message{
+			ID:    "applyPatchToIndex",
+			Other: "apply to index",
+		}, &i18n.Message{
+			ID:    "pasteCommitRange",
+			Other: "paste copied commits as a range (git cherry-pick)",
+		}, &i18n.Message{
+			ID:    "NoCopiedCommits",
+			Other: "You haven't copied any commits",
+		}, &i18n.Message{
+			ID:    "SureCherryPickRange",
+			Other: "Are you sure you want to cherry-pick every commit from {{.startSha}} to {{.endSha}}, including any that weren't copied in between?",
+		}, &i18n.Message{
+			ID:    "CherryPickOptionsTitle",
+			Other: "Cherry-pick Options",
+		}, &i18n.Message{
+			ID:    "moveCommitToTop",
+			Other: "move commit to top",
+		}, &i18n.Message{
+			ID:    "moveCommitToBottom",
+			Other: "move commit to bottom",
+		}, &i18n.Message{
+			ID:    "CantMoveTodoToEnd",
+			Other: "Moving a todo all the way up or down isn't supported mid-rebase; move it one step at a time instead",
+		}, &i18n.Message{
+			ID:    "LargeFileTitle",
+			Other: "Large file",
+		}, &i18n.Message{
+			ID:    "LargeFilePrompt",
+			Other: "Bigger than {{.limit}}: {{.file}}. Are you sure you want to stage this?",
+		}, &i18n.Message{
+			ID:    "moveCommitsToNewBranch",
+			Other: "move this and later commits to a new branch",
+		}, &i18n.Message{
+			ID:    "CantMoveCommitsMidRebase",
+			Other: "You can't move commits to a new branch while rebasing",
+		}, &i18n.Message{
+			ID:    "MustStashToMoveCommits",
+			Other: "You must stash or discard your changes before moving commits to a new branch",
+		}, &i18n.Message{
+			ID:    "MoveCommitsToNewBranchPrompt",
+			Other: "New branch name (the top {{.count}} commit(s) will be moved off the current branch):",
+		}, &i18n.Message{
+			ID:    "CommitStat",
+			Other: "{{.files}} file(s) changed, +{{.additions}} -{{.deletions}}",
+		}, &i18n.Message{
+			ID:    "CheckoutDirtyTitle",
+			Other: "Checkout with uncommitted changes",
+		}, &i18n.Message{
+			ID: "CheckoutDirtyPrompt",
+			Other: `Your working tree differs from {{.branchName}} by:
+
+{{.diffStat}}
+
+Are you sure you want to check it out?`,
+		}, &i18n.Message{
+			ID:    "CommitNotInRange",
+			Other: "That commit isn't part of the range being rebased",
+		}, &i18n.Message{
+			ID:    "FixupTargetTitle",
+			Other: "Fixup Target",
+		}, &i18n.Message{
+			ID:    "SetCommitAuthorOption",
+			Other: "{{.keyBindAuthor}}: set author",
+		}, &i18n.Message{
+			ID:    "CommitAuthorTitle",
+			Other: "Commit Author",
+		}, &i18n.Message{
+			ID:    "CommittingAs",
+			Other: "(as {{.author}})",
+		}, &i18n.Message{
+			ID:    "showReflogSince",
+			Other: "show what changed since a point in time",
+		}, &i18n.Message{
+			ID:    "ReflogSincePrompt",
+			Other: "Since (e.g. '1 day ago', '2020-01-01'):",
+		}, &i18n.Message{
+			ID:    "ReflogSinceTitle",
+			Other: "Reflog since {{.since}}",
+		}, &i18n.Message{
+			ID:    "NoReflogEntries",
+			Other: "No reflog entries in that time",
+		}, &i18n.Message{
+			ID:    "NoUpstreamForBranch",
+			Other: "The current branch has no upstream, so there is nothing to compare against",
+		}, &i18n.Message{
+			ID:    "IncomingCommitsTitle",
+			Other: "Pull these commits?",
+		}, &i18n.Message{
+			ID:    "removeTrackedFile",
+			Other: "delete and stage the deletion (git rm)",
+		}, &i18n.Message{
+			ID:    "removeTrackedFileKeepLocal",
+			Other: "stop tracking but keep the file (git rm --cached)",
+		}, &i18n.Message{
+			ID:    "toggleFullSha",
+			Other: "toggle showing full commit shas",
+		}, &i18n.Message{
+			ID:    "restoreStashFile",
+			Other: "restore a single file from this stash entry",
+		}, &i18n.Message{
+			ID:    "RestoreStashFileTitle",
+			Other: "Restore file from stash",
+		}, &i18n.Message{
+			ID:    "toggleCommitMarked",
+			Other: "mark/unmark commit for squashing",
+		}, &i18n.Message{
+			ID:    "squashMarkedCommits",
+			Other: "squash marked commits into the oldest marked commit",
+		}, &i18n.Message{
+			ID:    "MarkAtLeastTwoCommits",
+			Other: "You need to mark at least two commits to squash",
+		}, &i18n.Message{
+			ID:    "CantSquashMarkedMidRebase",
+			Other: "You can't squash marked commits that are part of an ongoing rebase",
+		}, &i18n.Message{
+			ID:    "SureSquashMarkedCommits",
+			Other: "Are you sure you want to squash all the marked commits into the oldest marked commit?",
+		}, &i18n.Message{
+			ID:    "StashStatTitle",
+			Other: "Stat",
+		}, &i18n.Message{
+			ID:    "createFixupCommitFromGraph",
+			Other: "create fixup commit for a commit on any branch",
+		}, &i18n.Message{
+			ID:    "FixupTargetFromGraphTitle",
+			Other: "Pick a commit to fix up",
+		}, &i18n.Message{
+			ID:    "NoCommitOnGraphLine",
+			Other: "There is no commit on this line of the graph",
+		}, &i18n.Message{
+			ID:    "rebaseWithExec",
+			Other: "run a command on this commit and every commit above it",
+		}, &i18n.Message{
+			ID:    "RebaseExecPrompt",
+			Other: "Command to run on each commit:",
+		}, &i18n.Message{
+			ID:    "CantExecMidRebase",
+			Other: "You can't run a command on commits while a merge or rebase is in progress",
+		}, &i18n.Message{
+			ID:    "DetachedHead",
+			Other: "detached HEAD",
+		}, &i18n.Message{
+			ID:    "createBranchFromDetachedHead",
+			Other: "create branch at detached HEAD",
+		}, &i18n.Message{
+			ID:    "NotDetachedHead",
+			Other: "HEAD is not detached",
+		}, &i18n.Message{
+			ID:    "NewBranchAtDetachedHead",
+			Other: "New branch name (branch is created at the current commit):",
+		}, &i18n.Message{
+			ID:    "nextConflictedFile",
+			Other: "jump to next file with merge conflicts",
+		}, &i18n.Message{
+			ID:    "NoConflictedFiles",
+			Other: "There are no files with merge conflicts",
+		}, &i18n.Message{
+			ID:    "amendLastCommitKeepDate",
+			Other: "amend last commit keeping its original date",
+		}, &i18n.Message{
+			ID:    "stageGlob",
+			Other: "stage all files matching a pattern",
+		}, &i18n.Message{
+			ID:    "StageGlobPrompt",
+			Other: "Stage files matching (e.g. *.go):",
+		}, &i18n.Message{
+			ID:    "EmptyPattern",
+			Other: "Pattern cannot be empty",
+		}, &i18n.Message{
+			ID:    "toggleMergeParent",
+			Other: "toggle showing merge commits against their first/second parent",
+		}, &i18n.Message{
+			ID:    "DiffAgainstParentTitle",
+			Other: "Patch (against parent {{.parent}})",
+		}, &i18n.Message{
+			ID:    "InvalidParentNumber",
+			Other: "Parent number must be 1 or more",
+		}, &i18n.Message{
+			ID:    "stageFile",
+			Other: "stage selected file",
+		}, &i18n.Message{
+			ID:    "unstageFile",
+			Other: "unstage selected file",
+		}, &i18n.Message{
+			ID:    "rebaseOntoCommit",
+			Other: "rebase current branch onto this commit",
+		}, &i18n.Message{
+			ID:    "SureRebaseOntoCommit",
+			Other: "Are you sure you want to move the commits this branch has on top of {{.baseBranch}} onto {{.commit}}?",
+		}, &i18n.Message{
+			ID:    "CantRebaseOntoMidRebase",
+			Other: "You can't start a rebase while a merge or rebase is in progress",
+		}, &i18n.Message{
+			ID:    "CommitCount",
+			Other: "({{.count}} commits)",
+		}, &i18n.Message{
+			ID:    "openShell",
+			Other: "open a shell in the repo (exit the shell to return)",
+		}, &i18n.Message{
+			ID:    "showStagedDiff",
+			Other: "show diff of all staged changes",
+		}, &i18n.Message{
+			ID:    "StagedDiffTitle",
+			Other: "All Staged Changes",
+		}, &i18n.Message{
+			ID:    "NoStagedChanges",
+			Other: "There are no staged changes",
+		}, &i18n.Message{
+			ID:    "discardFileChangesInRange",
+			Other: "discard this file's changes from this commit and the ones before it",
+		}, &i18n.Message{
+			ID:    "DiscardFileChangesInRangePrompt",
+			Other: "Number of commits to discard changes from (starting at this one and going back):",
+		}, &i18n.Message{
+			ID:    "InvalidCommitCount",
+			Other: "Please enter a positive number of commits",
+		}, &i18n.Message{
+			ID:    "runMaintenance",
+			Other: "run git maintenance",
+		}, &i18n.Message{
+			ID:    "MaintenanceTitle",
+			Other: "Maintenance (strategy: {{.strategy}})",
+		}, &i18n.Message{
+			ID:    "MaintenanceNotScheduled",
+			Other: "not scheduled",
+		}, &i18n.Message{
+			ID:    "RunningMaintenanceStatus",
+			Other: "running maintenance",
+		}, &i18n.Message{
+			ID:    "MaintenanceDone",
+			Other: "Maintenance finished",
+		}, &i18n.Message{
+			ID:    "revertHead",
+			Other: "undo last commit with a revert commit",
+		}, &i18n.Message{
+			ID:    "RevertHeadTitle",
+			Other: "Revert last commit",
+		}, &i18n.Message{
+			ID:    "SureRevertHead",
+			Other: "Are you sure you want to add a commit that reverts the last commit?",
+		}, &i18n.Message{
+			ID:    "checkIgnore",
+			Other: "show which gitignore rule matches a path",
+		}, &i18n.Message{
+			ID:    "CheckIgnoreTitle",
+			Other: "Gitignore rule",
+		}, &i18n.Message{
+			ID:    "CheckIgnorePrompt",
+			Other: "Path to check against the gitignore rules:",
+		}, &i18n.Message{
+			ID:    "PathNotIgnored",
+			Other: "No gitignore rule matches this path",
+		}, &i18n.Message{
+			ID:    "EmptyPath",
+			Other: "Path cannot be empty",
+		}, &i18n.Message{
+			ID:    "commitAndPush",
+			Other: "commit changes and push",
+		}, &i18n.Message{
+			ID:    "CommitAndPushWait",
+			Other: "Committing and pushing...",
+		}, &i18n.Message{
+			ID:    "NotBisecting",
+			Other: "You need to start a bisect session with 'git bisect start' first",
+		}, &i18n.Message{
+			ID:    "bisectRun",
+			Other: "find first bad commit with bisect run",
+		}, &i18n.Message{
+			ID:    "BisectRunPrompt",
+			Other: "Command to test each commit with (exit 0 = good):",
+		}, &i18n.Message{
+			ID:    "BisectRunStatus",
+			Other: "Bisecting",
+		}, &i18n.Message{
+			ID:    "BisectResultTitle",
+			Other: "Bisect result",
+		}, &i18n.Message{
+			ID:    "cycleDiffAlgorithm",
+			Other: "cycle diff algorithm (myers/patience/histogram/minimal)",
+		}, &i18n.Message{
+			ID:    "revertRange",
+			Other: "revert all commits from selected commit up to HEAD",
+		}, &i18n.Message{
+			ID:    "RevertRangeTitle",
+			Other: "Revert range",
+		}, &i18n.Message{
+			ID:    "SureRevertRange",
+			Other: "Are you sure you want to revert the {{.count}} commit(s) from the selected commit up to HEAD? A revert commit will be added for each",
+		}, &i18n.Message{
+			ID:    "CannotRevertRangeFromFirstCommit",
+			Other: "You cannot revert a range starting at the first commit",
+		}, &i18n.Message{
+			ID:    "CantRevertRangeMidRebase",
+			Other: "You can't revert a range of commits while rebasing, merging, cherry-picking or reverting",
+		}, &i18n.Message{
+			ID:    "RevertingStatus",
+			Other: "reverting",
+		}, &i18n.Message{
+			ID:    "RevertOptionsTitle",
+			Other: "Revert Options",
+		}, &i18n.Message{
+			ID:    "ForcePushProtectedBranch",
+			Other: "This branch is listed in git.protectedBranches, so it can't be force pushed",
+		}, &i18n.Message{
+			ID:    "toggleFileMarked",
+			Other: "mark/unmark file for amending into last commit",
+		}, &i18n.Message{
+			ID:    "amendMarkedFiles",
+			Other: "amend last commit with marked files only",
+		}, &i18n.Message{
+			ID:    "NoFilesMarkedForAmend",
+			Other: "No files are marked for amending",
+		}, &i18n.Message{
+			ID:    "SureToAmendMarkedFiles",
+			Other: "Are you sure you want to amend the last commit with only the marked files? Anything else that's staged will stay staged",
+		}, &i18n.Message{
+			ID:    "showCommitSignature",
+			Other: "show commit signature details",
+		}, &i18n.Message{
+			ID:    "SignatureTitle",
+			Other: "Signature",
+		}, &i18n.Message{
+			ID:    "viewHooks",
+			Other: "view and enable/disable git hooks",
+		}, &i18n.Message{
+			ID:    "HooksTitle",
+			Other: "Hooks (select to enable/disable)",
+		}, &i18n.Message{
+			ID:    "NoHooks",
+			Other: "No hooks are installed in this repo",
+		}, &i18n.Message{
+			ID:    "commitPathspec",
+			Other: "commit only changes under a path",
+		}, &i18n.Message{
+			ID:    "EnterCommitPathspec",
+			Other: "Commit only changes under path:",
+		}, &i18n.Message{
+			ID:    "CommittingPathspec",
+			Other: "(only {{.path}})",
+		}, &i18n.Message{
+			ID:    "CarriedChangesConflict",
+			Other: "The branch was checked out, but your changes conflicted with it when they were reapplied. Once you've resolved the conflicts you can drop the stash entry, which still holds your changes.",
+		}, &i18n.Message{
+			ID:    "OldestUnpushedCommit",
+			Other: "Oldest commit to be pushed: {{.commit}}",
+		}, &i18n.Message{
+			ID:    "unstageHunk",
+			Other: "unstage a single hunk",
+		}, &i18n.Message{
+			ID:    "UnstageHunkTitle",
+			Other: "Unstage hunk",
+		}, &i18n.Message{
+			ID:    "FileHasNoStagedChanges",
+			Other: "This file has no staged changes",
+		}, &i18n.Message{
+			ID:    "NoSuchHunk",
+			Other: "There is no such hunk in the staged diff",
+		}, &i18n.Message{
+			ID:    "LastFetched",
+			Other: "(fetched {{.time}} ago)",
+		}, &i18n.Message{
+			ID:    "applyPatchAsCommit",
+			Other: "apply as commit, keeping its author and message (git am)",
+		}, &i18n.Message{
+			ID:    "AmOptionsTitle",
+			Other: "Am Options",
+		}, &i18n.Message{
+			ID:    "restoreLinesFromCommit",
+			Other: "restore lines from this version of the file",
+		}, &i18n.Message{
+			ID:    "RestoringTitle",
+			Other: "Restore {{.fileName}} from {{.rev}}",
+		}, &i18n.Message{
+			ID:    "RestoreSelection",
+			Other: "restore selected lines into working tree",
+		}, &i18n.Message{
+			ID:    "NothingToRestore",
+			Other: "The file in the working tree already matches this version",
+		}, &i18n.Message{
+			ID:    "cycleMergeCommitsMode",
+			Other: "cycle between showing merge commits, hiding them and following first parents only",
+		}, &i18n.Message{
+			ID:    "MoveTargetExists",
+			Other: "A file already exists at that path",
+		}, &i18n.Message{
+			ID:    "moveFile",
+			Other: "rename/move file (git mv)",
+		}, &i18n.Message{
+			ID:    "MoveFilePrompt",
+			Other: "New path:",
+		}, &i18n.Message{
+			ID:    "toggleStashWorkingTreeDiff",
+			Other: "toggle between stat and diff against working tree",
+		}, &i18n.Message{
+			ID:    "StashWorkingTreeDiffTitle",
+			Other: "Diff Against Working Tree",
+		}, &i18n.Message{
+			ID:    "StashMatchesWorkingTree",
+			Other: "The working tree already matches this stash entry",
+		}, &i18n.Message{
+			ID:    "stashIncludingUntracked",
+			Other: "stash all changes including untracked files",
+		}, &i18n.Message{
+			ID:    "NoFilesToStash",
+			Other: "You have no files to stash",
+		}, &i18n.Message{
+			ID:    "BlameLineNotCommitted",
+			Other: "This line hasn't been committed yet",
+		}, &i18n.Message{
+			ID:    "BlameCommitNotLoaded",
+			Other: "That commit isn't among the commits loaded in the commits panel",
+		}, &i18n.Message{
+			ID:    "goToBlameCommit",
+			Other: "go to commit",
+		}, &i18n.Message{
+			ID:    "ReturnToCommitFilesPanel",
+			Other: "return to commit files panel",
+		}, &i18n.Message{
+			ID:    "unstageFiles",
+			Other: "unstage marked files, keeping their changes",
+		}, &i18n.Message{
+			ID:    "NoStagedFilesToUnstage",
+			Other: "None of these files have staged changes",
+		}, &i18n.Message{
+			ID:    "viewTags",
+			Other: "view tags",
+		}, &i18n.Message{
+			ID:    "createLightweightTag",
+			Other: "tag this commit",
+		}, &i18n.Message{
+			ID:    "NoTags",
+			Other: "This repo has no tags",
+		}, &i18n.Message{
+			ID:    "TagsTitle",
+			Other: "Tags",
+		}, &i18n.Message{
+			ID:    "TagNamePrompt",
+			Other: "Tag name:",
+		}, &i18n.Message{
+			ID:    "compareTags",
+			Other: "compare two tags",
+		}, &i18n.Message{
+			ID:    "NotEnoughTagsToCompare",
+			Other: "You need at least two tags to compare",
+		}, &i18n.Message{
+			ID:    "CompareFromTagTitle",
+			Other: "Compare from tag",
+		}, &i18n.Message{
+			ID:    "CompareToTagTitle",
+			Other: "Compare to tag",
+		}, &i18n.Message{
+			ID:    "CommitsBetweenTagsTitle",
+			Other: "Commits",
+		}, &i18n.Message{
+			ID:    "NoCommitsBetweenTags",
+			Other: "No commits between these tags",
+		}, &i18n.Message{
+			ID:    "DirtySubmodules",
+			Other: "submodules modified",
+		}, &i18n.Message{
+			ID:    "deleteLocalBranch",
+			Other: "delete local branch {{.selectedBranchName}}",
+		}, &i18n.Message{
+			ID:    "deleteRemoteBranch",
+			Other: "delete remote branch {{.upstream}}",
+		}, &i18n.Message{
+			ID:    "deleteLocalAndRemoteBranch",
+			Other: "delete local branch {{.selectedBranchName}} and remote branch {{.upstream}}",
+		}, &i18n.Message{
+			ID:    "DeleteRemoteBranchMessage",
+			Other: "Are you sure you want to delete the remote branch {{.upstream}}?",
+		}, &i18n.Message{
+			ID:    "DeleteLocalAndRemoteBranchMessage",
+			Other: "Are you sure you want to delete the branch {{.selectedBranchName}} and the remote branch {{.upstream}}?",
+		}, &i18n.Message{
+			ID:    "DeletingRemoteBranchStatus",
+			Other: "Deleting remote branch...",
+		}, &i18n.Message{
+			ID:    "renameBranch",
+			Other: "rename branch",
+		}, &i18n.Message{
+			ID:    "RenameBranchPrompt",
+			Other: "Rename branch {{.branchName}} to:",
+		}, &i18n.Message{
+			ID:    "createSquashCommit",
+			Other: "create squash commit for this commit",
+		}, &i18n.Message{
+			ID:    "CreateSquashCommit",
+			Other: "Create squash commit",
+		}, &i18n.Message{
+			ID:    "SureCreateSquashCommit",
+			Other: "Are you sure you want to create a squash! commit for commit {{.commit}}?",
+		}, &i18n.Message{
+			ID:    "BinaryFileStaging",
+			Other: "Binary files can only be staged as a whole: press space to stage or unstage the whole file",
+		}, &i18n.Message{
+			ID:    "CantRebaseDefaultBranchOnto",
+			Other: "The checked out branch is the default branch, so it has no commits of its own to rebase",
+		}, &i18n.Message{
+			ID:    "SelectionAlreadyStaged",
+			Other: "These lines are already staged. Press tab to switch to the staged changes if you want to unstage them",
+		}, &i18n.Message{
+			ID:    "showTag",
+			Other: "show tag",
+		}, &i18n.Message{
+			ID:    "checkoutTag",
+			Other: "checkout tag",
+		}, &i18n.Message{
+			ID:    "CheckoutTagTitle",
+			Other: "Checkout tag",
+		}, &i18n.Message{
+			ID:    "SureCheckoutTag",
+			Other: "Checking out {{.tagName}} will leave you with a detached HEAD: commits you make there won't belong to any branch. Continue?",
+		}, &i18n.Message{
+			ID:    "toggleBranchGraph",
+			Other: "toggle showing the branch's graph instead of its tip commit",
 		},
 	)
 }
@@ -299,3 +299,21 @@ func DifferenceInt(a, b []int) []int {
 	}
 	return result
 }
+
+// FormatRelativeDuration formats a duration in its largest whole unit, e.g.
+// '2h' or '3d', for showing how long ago something happened
+func FormatRelativeDuration(duration time.Duration) string {
+	day := 24 * time.Hour
+	switch {
+	case duration < time.Minute:
+		return "<1m"
+	case duration < time.Hour:
+		return fmt.Sprintf("%dm", duration/time.Minute)
+	case duration < day:
+		return fmt.Sprintf("%dh", duration/time.Hour)
+	case duration < 7*day:
+		return fmt.Sprintf("%dd", duration/day)
+	default:
+		return fmt.Sprintf("%dw", duration/(7*day))
+	}
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -573,3 +574,28 @@ func TestAsJson(t *testing.T) {
 	// no idea why this is returning empty hashes but it's works in the app ¯\_(ツ)_/¯
 	assert.EqualValues(t, "{}", output)
 }
+
+// TestFormatRelativeDuration is a function.
+func TestFormatRelativeDuration(t *testing.T) {
+	type scenario struct {
+		duration time.Duration
+		expected string
+	}
+
+	scenarios := []scenario{
+		{0, "<1m"},
+		{59 * time.Second, "<1m"},
+		{time.Minute, "1m"},
+		{59 * time.Minute, "59m"},
+		{2*time.Hour + 30*time.Minute, "2h"},
+		{23 * time.Hour, "23h"},
+		{24 * time.Hour, "1d"},
+		{6 * 24 * time.Hour, "6d"},
+		{7 * 24 * time.Hour, "1w"},
+		{30 * 24 * time.Hour, "4w"},
+	}
+
+	for _, s := range scenarios {
+		assert.EqualValues(t, s.expected, FormatRelativeDuration(s.duration))
+	}
+}